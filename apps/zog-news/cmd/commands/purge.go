@@ -0,0 +1,68 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+	"zog-news/database"
+	"zog-news/internal/repository/postgres"
+)
+
+// runPurge permanently removes articles and topics that have been in the
+// trash for longer than --older-than, e.g. `purge --older-than 30d`
+func runPurge(args []string) error {
+	flags := flag.NewFlagSet("purge", flag.ContinueOnError)
+	olderThan := flags.String("older-than", "30d", "minimum time in the trash, e.g. 30d or 12h")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	age, err := parseAge(*olderThan)
+	if err != nil {
+		return err
+	}
+	before := time.Now().UTC().Add(-age)
+
+	dbPool, err := database.SetupPgxPool()
+	if err != nil {
+		return fmt.Errorf("failed to connect to DB: %w", err)
+	}
+	defer dbPool.Close()
+
+	ctx := context.Background()
+
+	// articles first, they still link to the purged topics
+	articles, err := postgres.NewArticleRepository(dbPool).PurgeDeletedArticles(ctx, before)
+	if err != nil {
+		return fmt.Errorf("purging articles failed: %w", err)
+	}
+	topics, err := postgres.NewTopicRepository(dbPool).PurgeDeletedTopics(ctx, before)
+	if err != nil {
+		return fmt.Errorf("purging topics failed: %w", err)
+	}
+
+	slog.Info("Purged deleted rows", "articles", articles, "topics", topics, "deleted_before", before)
+	return nil
+}
+
+// parseAge accepts Go durations plus a d suffix for days
+func parseAge(value string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, errors.New("invalid --older-than value: " + value)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+
+	age, err := time.ParseDuration(value)
+	if err != nil || age < 0 {
+		return 0, errors.New("invalid --older-than value: " + value)
+	}
+	return age, nil
+}
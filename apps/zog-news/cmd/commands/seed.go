@@ -13,13 +13,20 @@ func runSeeder(db *sql.DB, target string) error {
 
 	switch target {
 	case "all":
-		if err := seeders.SeedArticles(db); err != nil {
+		if err := seeders.SeedUsers(db); err != nil {
 			return fmt.Errorf("seeding users failed: %w", err)
 		}
+		if err := seeders.SeedArticles(db); err != nil {
+			return fmt.Errorf("seeding articles failed: %w", err)
+		}
 		// continue for other tables
+	case "users":
+		if err := seeders.SeedUsers(db); err != nil {
+			return fmt.Errorf("seeding users failed: %w", err)
+		}
 	case "articles":
 		if err := seeders.SeedArticles(db); err != nil {
-			return fmt.Errorf("seeding users failed: %w", err)
+			return fmt.Errorf("seeding articles failed: %w", err)
         }
     // continue for other tables
 	default:
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"time"
+)
+
+// AuthConfig holds the settings used to sign and expire auth tokens
+type AuthConfig struct {
+	SecretKey       []byte
+	Issuer          string
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+}
+
+// LoadAuthConfig reads the auth settings from the environment.
+// APP_SECRET_KEY is required, token lifetimes fall back to 15 minutes for
+// access tokens and 30 days for refresh tokens.
+func LoadAuthConfig() (AuthConfig, error) {
+	secret := os.Getenv("APP_SECRET_KEY")
+	if secret == "" {
+		return AuthConfig{}, errors.New("APP_SECRET_KEY environment variable not set")
+	}
+
+	issuer := os.Getenv("SERVICE_NAME")
+	if issuer == "" {
+		issuer = "zog-news"
+	}
+
+	accessTTL, err := durationFromEnv("ACCESS_TOKEN_TTL", 15*time.Minute)
+	if err != nil {
+		return AuthConfig{}, err
+	}
+	refreshTTL, err := durationFromEnv("REFRESH_TOKEN_TTL", 30*24*time.Hour)
+	if err != nil {
+		return AuthConfig{}, err
+	}
+
+	return AuthConfig{
+		SecretKey:       []byte(secret),
+		Issuer:          issuer,
+		AccessTokenTTL:  accessTTL,
+		RefreshTokenTTL: refreshTTL,
+	}, nil
+}
+
+func durationFromEnv(key string, fallback time.Duration) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, errors.New(key + " is not a valid duration: " + err.Error())
+	}
+	return d, nil
+}
@@ -0,0 +1,36 @@
+package config
+
+import (
+	"os"
+	"time"
+)
+
+// CacheConfig holds the settings of the article and topic read cache
+type CacheConfig struct {
+	// ValkeyURL is empty when no Valkey is configured, reads are then cached
+	// in process memory
+	ValkeyURL  string
+	ArticleTTL time.Duration
+	TopicTTL   time.Duration
+}
+
+// LoadCacheConfig reads the cache settings from the environment.
+// VALKEY_URL is optional, cached articles expire after 5 minutes and topics
+// after 10 minutes unless CACHE_ARTICLE_TTL or CACHE_TOPIC_TTL say otherwise,
+// a TTL of 0 turns caching off.
+func LoadCacheConfig() (CacheConfig, error) {
+	articleTTL, err := durationFromEnv("CACHE_ARTICLE_TTL", 5*time.Minute)
+	if err != nil {
+		return CacheConfig{}, err
+	}
+	topicTTL, err := durationFromEnv("CACHE_TOPIC_TTL", 10*time.Minute)
+	if err != nil {
+		return CacheConfig{}, err
+	}
+
+	return CacheConfig{
+		ValkeyURL:  os.Getenv("VALKEY_URL"),
+		ArticleTTL: articleTTL,
+		TopicTTL:   topicTTL,
+	}, nil
+}
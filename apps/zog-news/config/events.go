@@ -0,0 +1,64 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"time"
+)
+
+// Sinks the event relay can publish to
+const (
+	EventSinkStdout  = "stdout"
+	EventSinkWebhook = "webhook"
+)
+
+// EventsConfig holds where domain events are relayed to and how
+type EventsConfig struct {
+	// Sink is empty when events only go to the registered webhooks
+	Sink       string
+	WebhookURL string
+
+	Interval    time.Duration
+	MaxAttempts int
+}
+
+// LoadEventsConfig reads the event relay settings from the environment.
+// EVENT_SINK is optional and either stdout or webhook, the webhook sink needs
+// EVENT_WEBHOOK_URL. The outbox is checked every 5 seconds
+// (EVENT_RELAY_INTERVAL) and an event is given up after 10 failed attempts
+// (EVENT_MAX_ATTEMPTS).
+func LoadEventsConfig() (EventsConfig, error) {
+	sink := os.Getenv("EVENT_SINK")
+	webhookURL := os.Getenv("EVENT_WEBHOOK_URL")
+	switch sink {
+	case "", EventSinkStdout:
+	case EventSinkWebhook:
+		if webhookURL == "" {
+			return EventsConfig{}, errors.New("EVENT_WEBHOOK_URL environment variable not set")
+		}
+	default:
+		return EventsConfig{}, errors.New("EVENT_SINK must be stdout or webhook, got " + sink)
+	}
+
+	interval, err := durationFromEnv("EVENT_RELAY_INTERVAL", 5*time.Second)
+	if err != nil {
+		return EventsConfig{}, err
+	}
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	maxAttempts, err := intFromEnv("EVENT_MAX_ATTEMPTS", 10)
+	if err != nil {
+		return EventsConfig{}, err
+	}
+	if maxAttempts <= 0 {
+		maxAttempts = 1
+	}
+
+	return EventsConfig{
+		Sink:        sink,
+		WebhookURL:  webhookURL,
+		Interval:    interval,
+		MaxAttempts: maxAttempts,
+	}, nil
+}
@@ -0,0 +1,56 @@
+package config
+
+import (
+	"errors"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// FeedConfig holds how the RSS and Atom feeds describe the site
+type FeedConfig struct {
+	// PublicURL is the scheme and host links in feeds point at, empty to use
+	// the host of each feed request
+	PublicURL string
+	Title     string
+	// Language is the ISO 639 code of the articles, Google News requires it
+	Language string
+	// Limit is how many of the latest articles a feed lists
+	Limit int
+}
+
+// LoadFeedConfig reads the feed settings from the environment. APP_PUBLIC_URL
+// is optional, FEED_TITLE defaults to the API title, FEED_LANGUAGE to en and
+// a feed lists the 50 latest articles (FEED_LIMIT, at most 100).
+func LoadFeedConfig() (FeedConfig, error) {
+	publicURL := strings.TrimSuffix(os.Getenv("APP_PUBLIC_URL"), "/")
+	if publicURL != "" {
+		u, err := url.Parse(publicURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return FeedConfig{}, errors.New("APP_PUBLIC_URL must be an absolute http or https URL")
+		}
+	}
+
+	title := os.Getenv("FEED_TITLE")
+	if title == "" {
+		title = "Zero One Group News"
+	}
+	language := os.Getenv("FEED_LANGUAGE")
+	if language == "" {
+		language = "en"
+	}
+	limit, err := intFromEnv("FEED_LIMIT", 50)
+	if err != nil {
+		return FeedConfig{}, err
+	}
+	if limit <= 0 {
+		limit = 50
+	}
+
+	return FeedConfig{
+		PublicURL: publicURL,
+		Title:     title,
+		Language:  language,
+		Limit:     limit,
+	}, nil
+}
@@ -0,0 +1,76 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"strconv"
+	"time"
+)
+
+// MailConfig holds the SMTP server emails are sent through and how the outbox
+// worker delivers them
+type MailConfig struct {
+	// Host is empty when no SMTP server is configured, emails then stay in
+	// the outbox until one is
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+
+	Interval    time.Duration
+	MaxAttempts int
+}
+
+// LoadMailConfig reads the mail settings from the environment. SMTP_HOST is
+// optional, SMTP_PORT defaults to 1025 where Mailpit listens. The outbox is
+// checked every 10 seconds (MAIL_INTERVAL) and an email is given up after 5
+// failed attempts (MAIL_MAX_ATTEMPTS).
+func LoadMailConfig() (MailConfig, error) {
+	port, err := intFromEnv("SMTP_PORT", 1025)
+	if err != nil {
+		return MailConfig{}, err
+	}
+	interval, err := durationFromEnv("MAIL_INTERVAL", 10*time.Second)
+	if err != nil {
+		return MailConfig{}, err
+	}
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+	maxAttempts, err := intFromEnv("MAIL_MAX_ATTEMPTS", 5)
+	if err != nil {
+		return MailConfig{}, err
+	}
+	if maxAttempts <= 0 {
+		maxAttempts = 1
+	}
+
+	from := os.Getenv("SMTP_EMAIL_FROM")
+	if from == "" {
+		from = "Zero One Group News <noreply@localhost>"
+	}
+
+	return MailConfig{
+		Host:        os.Getenv("SMTP_HOST"),
+		Port:        port,
+		Username:    os.Getenv("SMTP_USERNAME"),
+		Password:    os.Getenv("SMTP_PASSWORD"),
+		From:        from,
+		Interval:    interval,
+		MaxAttempts: maxAttempts,
+	}, nil
+}
+
+func intFromEnv(key string, fallback int) (int, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, errors.New(key + " is not a valid number: " + err.Error())
+	}
+	return n, nil
+}
@@ -0,0 +1,86 @@
+package config
+
+import (
+	"errors"
+	"os"
+)
+
+// Storage backends uploaded media can be kept in
+const (
+	MediaStorageLocal = "local"
+	MediaStorageS3    = "s3"
+)
+
+// MediaConfig holds where uploaded media is stored and how large it may be
+type MediaConfig struct {
+	Storage string
+	// Dir is where the local storage keeps files
+	Dir string
+	// MaxSize is the largest upload accepted, in bytes
+	MaxSize int64
+	// ThumbnailSize is the longest edge of image thumbnails, in pixels
+	ThumbnailSize int
+
+	S3Endpoint        string
+	S3Bucket          string
+	S3Region          string
+	S3AccessKeyID     string
+	S3SecretAccessKey string
+}
+
+// LoadMediaConfig reads the media settings from the environment.
+// MEDIA_STORAGE is local or s3 and defaults to local, which keeps files in
+// ./data/media (MEDIA_DIR). The s3 storage needs MEDIA_S3_ENDPOINT,
+// MEDIA_S3_BUCKET, MEDIA_S3_ACCESS_KEY_ID and MEDIA_S3_SECRET_ACCESS_KEY,
+// MEDIA_S3_REGION defaults to us-east-1. Uploads are limited to 10 MiB
+// (MEDIA_MAX_SIZE, in bytes) and thumbnails are 320 pixels on their longest
+// edge (MEDIA_THUMBNAIL_SIZE).
+func LoadMediaConfig() (MediaConfig, error) {
+	config := MediaConfig{
+		Storage:           os.Getenv("MEDIA_STORAGE"),
+		Dir:               os.Getenv("MEDIA_DIR"),
+		S3Endpoint:        os.Getenv("MEDIA_S3_ENDPOINT"),
+		S3Bucket:          os.Getenv("MEDIA_S3_BUCKET"),
+		S3Region:          os.Getenv("MEDIA_S3_REGION"),
+		S3AccessKeyID:     os.Getenv("MEDIA_S3_ACCESS_KEY_ID"),
+		S3SecretAccessKey: os.Getenv("MEDIA_S3_SECRET_ACCESS_KEY"),
+	}
+
+	switch config.Storage {
+	case "", MediaStorageLocal:
+		config.Storage = MediaStorageLocal
+	case MediaStorageS3:
+		if config.S3Endpoint == "" || config.S3Bucket == "" ||
+			config.S3AccessKeyID == "" || config.S3SecretAccessKey == "" {
+			return MediaConfig{}, errors.New("MEDIA_S3_ENDPOINT, MEDIA_S3_BUCKET, MEDIA_S3_ACCESS_KEY_ID and MEDIA_S3_SECRET_ACCESS_KEY must be set for s3 media storage")
+		}
+	default:
+		return MediaConfig{}, errors.New("MEDIA_STORAGE must be local or s3, got " + config.Storage)
+	}
+	if config.Dir == "" {
+		config.Dir = "./data/media"
+	}
+	if config.S3Region == "" {
+		config.S3Region = "us-east-1"
+	}
+
+	maxSize, err := intFromEnv("MEDIA_MAX_SIZE", 10<<20)
+	if err != nil {
+		return MediaConfig{}, err
+	}
+	if maxSize <= 0 {
+		maxSize = 10 << 20
+	}
+	config.MaxSize = int64(maxSize)
+
+	thumbnailSize, err := intFromEnv("MEDIA_THUMBNAIL_SIZE", 320)
+	if err != nil {
+		return MediaConfig{}, err
+	}
+	if thumbnailSize <= 0 {
+		thumbnailSize = 320
+	}
+	config.ThumbnailSize = thumbnailSize
+
+	return config, nil
+}
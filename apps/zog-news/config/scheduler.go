@@ -0,0 +1,16 @@
+package config
+
+import "time"
+
+// SchedulerInterval returns how often scheduled articles are checked for
+// publishing, read from SCHEDULER_INTERVAL (default one minute).
+func SchedulerInterval() (time.Duration, error) {
+	interval, err := durationFromEnv("SCHEDULER_INTERVAL", time.Minute)
+	if err != nil {
+		return 0, err
+	}
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	return interval, nil
+}
@@ -0,0 +1,38 @@
+package config
+
+import "time"
+
+// StatsConfig holds how article views are counted
+type StatsConfig struct {
+	// FlushInterval is how often the views counted in memory are written
+	FlushInterval time.Duration
+	// MaxPending caps how many article and day pairs are counted between
+	// flushes, reaching it writes them right away and views of further pairs
+	// are dropped until they are written
+	MaxPending int
+}
+
+// LoadStatsConfig reads the view counting settings from the environment.
+// Views are written every 10 seconds (STATS_FLUSH_INTERVAL), or as soon as
+// 10000 articles (STATS_MAX_PENDING) were viewed since the last write.
+func LoadStatsConfig() (StatsConfig, error) {
+	interval, err := durationFromEnv("STATS_FLUSH_INTERVAL", 10*time.Second)
+	if err != nil {
+		return StatsConfig{}, err
+	}
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+	maxPending, err := intFromEnv("STATS_MAX_PENDING", 10000)
+	if err != nil {
+		return StatsConfig{}, err
+	}
+	if maxPending <= 0 {
+		maxPending = 10000
+	}
+
+	return StatsConfig{
+		FlushInterval: interval,
+		MaxPending:    maxPending,
+	}, nil
+}
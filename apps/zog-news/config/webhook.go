@@ -0,0 +1,46 @@
+package config
+
+import "time"
+
+// WebhookConfig holds how webhook deliveries are dispatched
+type WebhookConfig struct {
+	Interval    time.Duration
+	MaxAttempts int
+	// DisableAfter is how many failed attempts in a row disable a webhook
+	DisableAfter int
+}
+
+// LoadWebhookConfig reads the webhook dispatcher settings from the
+// environment. Pending deliveries are checked every 5 seconds
+// (WEBHOOK_INTERVAL), a delivery is given up after 8 failed attempts
+// (WEBHOOK_MAX_ATTEMPTS) and a webhook is disabled after 20 failed attempts in
+// a row across its deliveries (WEBHOOK_DISABLE_AFTER).
+func LoadWebhookConfig() (WebhookConfig, error) {
+	interval, err := durationFromEnv("WEBHOOK_INTERVAL", 5*time.Second)
+	if err != nil {
+		return WebhookConfig{}, err
+	}
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	maxAttempts, err := intFromEnv("WEBHOOK_MAX_ATTEMPTS", 8)
+	if err != nil {
+		return WebhookConfig{}, err
+	}
+	if maxAttempts <= 0 {
+		maxAttempts = 1
+	}
+	disableAfter, err := intFromEnv("WEBHOOK_DISABLE_AFTER", 20)
+	if err != nil {
+		return WebhookConfig{}, err
+	}
+	if disableAfter <= 0 {
+		disableAfter = 1
+	}
+
+	return WebhookConfig{
+		Interval:     interval,
+		MaxAttempts:  maxAttempts,
+		DisableAfter: disableAfter,
+	}, nil
+}
@@ -0,0 +1,25 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func SetupValkey(valkeyURL string) (*redis.Client, error) {
+	options, err := redis.ParseURL(valkeyURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse valkey URL: %w", err)
+	}
+
+	client := redis.NewClient(options)
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to ping valkey: %w", err)
+	}
+
+	fmt.Println("Connected to Valkey...")
+
+	return client, nil
+}
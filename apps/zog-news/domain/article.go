@@ -2,6 +2,7 @@ package domain
 
 import (
 	"errors"
+	"fmt"
 	"time"
 )
 
@@ -9,58 +10,216 @@ import (
 
 // ArticleStatus represents the status of an article
 //	@Description	Article status enum
-//	@Enum			draft,published,archived
+//	@Enum			draft,in_review,published,archived
 type ArticleStatus string
 
 const (
 	StatusDraft     ArticleStatus = "draft"
+	StatusInReview  ArticleStatus = "in_review"
 	StatusPublished ArticleStatus = "published"
-	StatusDeleted   ArticleStatus = "deleted"
+	StatusArchived  ArticleStatus = "archived"
 )
 
+// ContentFormat represents how the content of an article is written
+//	@Description	Article content format enum
+//	@Enum			markdown,html,plain
+type ContentFormat string
+
+const (
+	FormatMarkdown ContentFormat = "markdown"
+	FormatHTML     ContentFormat = "html"
+	FormatPlain    ContentFormat = "plain"
+)
+
+// DefaultContentFormat is the format of new articles that don't name one
+const DefaultContentFormat = FormatMarkdown
+
+// IsValid reports whether f is a known content format
+func (f ContentFormat) IsValid() bool {
+	switch f {
+	case FormatMarkdown, FormatHTML, FormatPlain:
+		return true
+	}
+	return false
+}
+
 // Article represents an article entity
 //	@Description	Article entity with associated topics
 type Article struct {
 	ID      string        `json:"id" example:"d4b8583d-5038-4838-bcd7-3d8dddfedd6a"`
 	Title   string        `json:"title" example:"Breaking News: Important Update"`
 	Content string        `json:"content" example:"This is the content of the article..."`
-	Author  string        `json:"author" example:"John Doe"`
 	Status  ArticleStatus `json:"status" example:"published"`
 
-	// List of topic IDs associated with the article
-	TopicIDs []string `json:"-,omitempty" db:"-"`
+	// How the content is written and the sanitized HTML rendered from it.
+	// The HTML is only sent when asked for with render=html.
+	ContentFormat ContentFormat `json:"content_format" example:"markdown"`
+	ContentHTML   string        `json:"content_html,omitempty" example:"<p>This is the content of the article...</p>"`
+
+	// Derived from the rendered content: the start of its text and the
+	// estimated minutes it takes to read
+	Excerpt            string `json:"excerpt" example:"This is the content of the article..."`
+	ReadingTimeMinutes int    `json:"reading_time_minutes" example:"4"`
+
+	// Generated from the title, old slugs keep redirecting after a rename
+	Slug string `json:"slug" example:"breaking-news-important-update"`
+
+	// The user who wrote the article and their display name
+	AuthorID string `json:"author_id" example:"7f1c2a9e-3b4d-4c5e-8f6a-9b0c1d2e3f4a"`
+	Author   string `json:"author" example:"John Doe"`
+
+	// Topics to set on create and update, only read from requests. Names are
+	// matched case-insensitively and created when missing. Leaving both nil
+	// on update keeps the current topics.
+	TopicIDs   []string `json:"topic_ids,omitempty" db:"-" swaggerignore:"true"`
+	TopicNames []string `json:"topic_names,omitempty" db:"-" swaggerignore:"true"`
 
 	// Full Topic objects associated with the article for responses
 	Topics []Topic `json:"topics,omitempty" db:"-"`
 
+	// The image shown with the article and the media attached to it in
+	// order, attachments are only loaded for a single article
+	CoverMediaID *string `json:"cover_media_id,omitempty" example:"5b1e9d2c-4f3a-4c8e-9a7b-2d6f8e1c3a5b"`
+	Attachments  []Media `json:"attachments,omitempty" db:"-"`
+
+	// Attachments to set on create and update, only read from requests. Nil
+	// on update keeps the current attachments.
+	AttachmentIDs []string `json:"attachment_ids,omitempty" db:"-" swaggerignore:"true"`
+
+	// When the article went live, and when a reviewed article is due to go live
+	PublishedAt *time.Time `json:"published_at,omitempty" example:"2023-06-01T13:00:00Z"`
+	ScheduledAt *time.Time `json:"scheduled_at,omitempty" example:"2023-06-02T08:00:00Z"`
+
+	// Bumped on every change, served as the ETag
+	Version int `json:"version" example:"3"`
+
+	// Only set on articles in the trash
+	DeletedAt *time.Time `json:"deleted_at,omitempty" example:"2023-06-03T09:00:00Z"`
+
 	CreatedAt time.Time `json:"created_at" example:"2023-06-01T12:00:00Z"`
 	UpdatedAt time.Time `json:"updated_at" example:"2023-06-01T12:30:00Z"`
 }
 
 // CreateArticleRequest represents the request body for creating an article
-//	@Description	Request body for creating a new article
+//	@Description	Request body for creating a new article, new articles always start as drafts
 type CreateArticleRequest struct {
-	Title   string        `json:"title" validate:"required" example:"Breaking News: Important Update"`
-	Content string        `json:"content" validate:"required" example:"This is the content of the article..."`
-	Author  string        `json:"author" validate:"required" example:"John Doe"`
-	Status  ArticleStatus `json:"status" validate:"oneof=draft published archived" example:"draft"`
+	Title   string `json:"title" validate:"required,max=255" example:"Breaking News: Important Update"`
+	Content string `json:"content" validate:"required" example:"This is the content of the article..."`
+	// Defaults to markdown
+	ContentFormat ContentFormat `json:"content_format,omitempty" validate:"omitempty,content_format" example:"markdown"`
+	// Existing topics to attach
+	TopicIDs []string `json:"topic_ids,omitempty" validate:"omitempty,dive,uuid" example:"550e8400-e29b-41d4-a716-446655440000"`
+	// Topics to attach by name, missing ones are created (editors only)
+	TopicNames []string `json:"topic_names,omitempty" validate:"omitempty,dive,required,max=64" example:"Technology"`
+	// An uploaded image to show with the article
+	CoverMediaID *string `json:"cover_media_id,omitempty" validate:"omitempty,uuid" example:"5b1e9d2c-4f3a-4c8e-9a7b-2d6f8e1c3a5b"`
+	// Uploaded media to attach, in order
+	AttachmentIDs []string `json:"attachment_ids,omitempty" validate:"omitempty,max=50,dive,uuid" example:"5b1e9d2c-4f3a-4c8e-9a7b-2d6f8e1c3a5b"`
 }
 
 // UpdateArticleRequest represents the request body for updating an article
-//	@Description	Request body for updating an existing article
+//	@Description	Request body for updating an existing article. The status can only change through the workflow actions
 type UpdateArticleRequest struct {
-	Title   string        `json:"title" validate:"required" example:"Updated Breaking News"`
+	Title   string        `json:"title" validate:"required,max=255" example:"Updated Breaking News"`
 	Content string        `json:"content" validate:"required" example:"This is the updated content..."`
-	Author  string        `json:"author" validate:"required" example:"Jane Doe"`
-	Status  ArticleStatus `json:"status" validate:"oneof=draft published archived" example:"published"`
+	Status  ArticleStatus `json:"status" validate:"omitempty,article_status" example:"draft"`
+	// Keeps the current format when left out
+	ContentFormat ContentFormat `json:"content_format,omitempty" validate:"omitempty,content_format" example:"markdown"`
+	// Replaces the topics when either list is sent, an empty list removes them all
+	TopicIDs []string `json:"topic_ids,omitempty" validate:"omitempty,dive,uuid" example:"550e8400-e29b-41d4-a716-446655440000"`
+	// Topics to attach by name, missing ones are created (editors only)
+	TopicNames []string `json:"topic_names,omitempty" validate:"omitempty,dive,required,max=64" example:"Technology"`
+	// Keeps the current cover when left out, an empty ID removes it
+	CoverMediaID *string `json:"cover_media_id,omitempty" validate:"omitempty,uuid" example:"5b1e9d2c-4f3a-4c8e-9a7b-2d6f8e1c3a5b"`
+	// Replaces the attachments when sent, an empty list removes them all
+	AttachmentIDs []string `json:"attachment_ids,omitempty" validate:"omitempty,max=50,dive,uuid" example:"5b1e9d2c-4f3a-4c8e-9a7b-2d6f8e1c3a5b"`
+}
+
+// ArticleSort represents the column an article list is ordered by
+//	@Description	Article sort column enum
+//	@Enum			created_at,updated_at,published_at,title
+type ArticleSort string
+
+const (
+	SortCreatedAt ArticleSort = "created_at"
+	SortUpdatedAt ArticleSort = "updated_at"
+	// Unpublished articles sort by their creation time
+	SortPublishedAt ArticleSort = "published_at"
+	SortTitle       ArticleSort = "title"
+)
+
+// SortOrder represents the direction of a sort
+//	@Description	Sort direction enum
+//	@Enum			asc,desc
+type SortOrder string
+
+const (
+	OrderAsc  SortOrder = "asc"
+	OrderDesc SortOrder = "desc"
+)
+
+const (
+	// DefaultArticleLimit is the page size used when no limit is given
+	DefaultArticleLimit = 20
+	// MaxArticleLimit caps the page size a client may request
+	MaxArticleLimit = 100
+)
+
+// ArticleScope limits article reads to what the caller may see. The service
+// sets it from the caller, it is never read from the query.
+type ArticleScope struct {
+	// Restricted limits reads to published articles, and to the unpublished
+	// ones written by OwnerID when set
+	Restricted bool   `json:"restricted,omitempty"`
+	OwnerID    string `json:"owner_id,omitempty"`
 }
 
 // ArticleFilter represents query parameters for filtering articles
 //	@Description	Query parameters for filtering articles
 type ArticleFilter struct {
 	Search string        `json:"search" query:"search" example:"breaking news"`
-	Status ArticleStatus `json:"status" query:"status" example:"published"`
+	Status ArticleStatus `json:"status" query:"status" validate:"omitempty,article_status" example:"published"`
 	Topic  string        `json:"topic" query:"topic" example:"technology"`
+
+	// TopicID limits the list to a topic, and its subtopics with
+	// IncludeDescendants. Set by the topic feeds, not read from the query.
+	TopicID            string `json:"topic_id,omitempty" swaggerignore:"true"`
+	IncludeDescendants bool   `json:"include_descendants,omitempty" swaggerignore:"true"`
+
+	Scope ArticleScope `json:"scope" swaggerignore:"true"`
+
+	// Pagination and ordering
+	Limit  int         `json:"limit" query:"limit" example:"20"`
+	Cursor string      `json:"cursor" query:"cursor" example:"eyJzIjoiY3JlYXRlZF9hdCIsInYiOiIyMDIzLTA2LTAxVDEyOjAwOjAwWiIsImlkIjoiZDRiODU4M2QifQ"`
+	Sort   ArticleSort `json:"sort" query:"sort" example:"created_at"`
+	Order  SortOrder   `json:"order" query:"order" example:"desc"`
+}
+
+// Normalize fills in pagination defaults and rejects unknown sort options
+func (f *ArticleFilter) Normalize() error {
+	switch f.Sort {
+	case "":
+		f.Sort = SortCreatedAt
+	case SortCreatedAt, SortUpdatedAt, SortPublishedAt, SortTitle:
+	default:
+		return fmt.Errorf("%w: unknown sort %q", ErrBadParamInput, f.Sort)
+	}
+
+	switch f.Order {
+	case "":
+		f.Order = OrderDesc
+	case OrderAsc, OrderDesc:
+	default:
+		return fmt.Errorf("%w: unknown order %q", ErrBadParamInput, f.Order)
+	}
+
+	if f.Limit <= 0 {
+		f.Limit = DefaultArticleLimit
+	}
+	if f.Limit > MaxArticleLimit {
+		f.Limit = MaxArticleLimit
+	}
+	return nil
 }
 
 func (a *Article) HasTopicID(topic string) error {
@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// ReplaceArticleTopicsRequest represents the request body for replacing the topics of an article
+//	@Description	Request body for replacing every topic of an article, an empty list removes them all
+type ReplaceArticleTopicsRequest struct {
+	TopicIDs []string `json:"topic_ids" validate:"required,dive,uuid" example:"550e8400-e29b-41d4-a716-446655440000"`
+}
+
+// PatchArticleTopicsRequest represents the request body for changing some topics of an article
+//	@Description	Request body for adding and removing topics of an article in one request
+type PatchArticleTopicsRequest struct {
+	Add    []string `json:"add" validate:"dive,uuid" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Remove []string `json:"remove" validate:"dive,uuid" example:"6ba7b810-9dad-11d1-80b4-00c04fd430c8"`
+}
+
+// NormalizeTopicIDs checks every ID is a UUID and drops duplicates, keeping
+// the first occurrence
+func NormalizeTopicIDs(ids []string) ([]string, error) {
+	seen := make(map[string]bool, len(ids))
+	normalized := make([]string, 0, len(ids))
+	for _, id := range ids {
+		parsed, err := uuid.Parse(id)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid topic ID %q", ErrBadParamInput, id)
+		}
+		key := parsed.String()
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		normalized = append(normalized, key)
+	}
+	return normalized, nil
+}
+
+// NormalizeTopicNames normalizes the names, drops empty ones and duplicates
+// that only differ in case, keeping the first spelling
+func NormalizeTopicNames(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	normalized := make([]string, 0, len(names))
+	for _, name := range names {
+		name = NormalizeTopicName(name)
+		key := strings.ToLower(name)
+		if name == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		normalized = append(normalized, name)
+	}
+	return normalized
+}
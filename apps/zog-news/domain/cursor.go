@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// ArticleCursor marks the position of the last article on a page so the next
+// page can continue right after it (keyset pagination).
+type ArticleCursor struct {
+	Sort  ArticleSort `json:"s"`
+	Value string      `json:"v"`
+	ID    string      `json:"id"`
+}
+
+// NewArticleCursor builds the cursor pointing at the given article for a sort column
+func NewArticleCursor(article *Article, sort ArticleSort) ArticleCursor {
+	cursor := ArticleCursor{Sort: sort, ID: article.ID}
+	switch sort {
+	case SortTitle:
+		cursor.Value = article.Title
+	case SortUpdatedAt:
+		cursor.Value = article.UpdatedAt.Format(time.RFC3339Nano)
+	case SortPublishedAt:
+		publishedAt := article.CreatedAt
+		if article.PublishedAt != nil {
+			publishedAt = *article.PublishedAt
+		}
+		cursor.Value = publishedAt.Format(time.RFC3339Nano)
+	default:
+		cursor.Value = article.CreatedAt.Format(time.RFC3339Nano)
+	}
+	return cursor
+}
+
+// Encode returns the opaque string representation handed out to clients
+func (c ArticleCursor) Encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// TimeValue parses the cursor value of a timestamp sort column
+func (c ArticleCursor) TimeValue() (time.Time, error) {
+	t, err := time.Parse(time.RFC3339Nano, c.Value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: malformed cursor", ErrBadParamInput)
+	}
+	return t, nil
+}
+
+// DecodeArticleCursor parses a cursor previously returned by Encode
+func DecodeArticleCursor(s string) (*ArticleCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrBadParamInput)
+	}
+
+	var cursor ArticleCursor
+	if err := json.Unmarshal(raw, &cursor); err != nil || cursor.ID == "" {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrBadParamInput)
+	}
+	return &cursor, nil
+}
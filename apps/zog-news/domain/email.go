@@ -0,0 +1,21 @@
+package domain
+
+// Email templates queued in the outbox, each names a template of the
+// notification package
+const (
+	// EmailArticleSubmitted tells editors a draft is waiting for review
+	EmailArticleSubmitted = "article_submitted"
+	// EmailArticlePublished tells the author their article went live
+	EmailArticlePublished = "article_published"
+)
+
+// OutboxEmail is an email waiting in the outbox to be delivered. Data holds
+// the template fields, captured when the email was queued.
+type OutboxEmail struct {
+	ID        string
+	Template  string
+	Recipient string
+	Data      map[string]string
+	// Attempts counts deliveries tried so far, including the current one
+	Attempts int
+}
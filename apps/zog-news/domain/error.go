@@ -1,6 +1,10 @@
 package domain
 
-import "errors"
+import (
+    "errors"
+    "fmt"
+    "strings"
+)
 
 var (
     // ErrInternalServerError will throw if any the Internal Server Error happen
@@ -15,6 +19,64 @@ var (
     ErrUserNotFound = errors.New("user not found")
     // ErrArticleNotFound
     ErrArticleNotFound = errors.New("article not found")
+    // ErrRevisionNotFound
+    ErrRevisionNotFound = errors.New("revision not found")
     // ErrTopicNotFound
     ErrTopicNotFound = errors.New("topic not found")
+    // ErrWebhookNotFound
+    ErrWebhookNotFound = errors.New("webhook not found")
+    // ErrMediaNotFound
+    ErrMediaNotFound = errors.New("media not found")
+    // ErrMediaTooLarge will throw if an upload is over the size limit
+    ErrMediaTooLarge = errors.New("media file is too large")
+    // ErrUnsupportedMediaType will throw if an upload is not one of the accepted file types
+    ErrUnsupportedMediaType = errors.New("media type is not supported")
+    // ErrInvalidCredentials will throw if the email or password does not match
+    ErrInvalidCredentials = errors.New("invalid email or password")
+    // ErrInvalidToken will throw if a token is malformed, expired or revoked
+    ErrInvalidToken = errors.New("invalid or expired token")
+    // ErrUnauthorized will throw if the request is not authenticated
+    ErrUnauthorized = errors.New("authentication required")
+    // ErrForbidden will throw if the caller is not allowed to perform the action
+    ErrForbidden = errors.New("you are not allowed to perform this action")
+    // ErrPreconditionFailed will throw if the resource changed since the client read it
+    ErrPreconditionFailed = errors.New("the resource was modified by someone else")
+    // ErrInvalidTransition will throw if an article can't move to the requested status
+    ErrInvalidTransition = errors.New("article can't move to the requested status")
+    // ErrValidation will throw if request fields break their constraints, see ValidationError
+    ErrValidation = errors.New("request validation failed")
 )
+
+// FieldError describes why a single request field was rejected
+// @Description Invalid request field
+type FieldError struct {
+    // JSON name of the field, list items are indexed like topic_names[1]
+    Field string `json:"field" example:"title"`
+    // Machine readable reason, the name of the broken constraint
+    Reason string `json:"reason" example:"max"`
+    // Argument of the constraint, such as the maximum length
+    Param string `json:"param,omitempty" example:"255"`
+}
+
+// ValidationError lists every invalid field of a request, it matches
+// ErrValidation with errors.Is
+type ValidationError struct {
+    Fields []FieldError
+}
+
+// NewValidationError reports the given invalid fields
+func NewValidationError(fields ...FieldError) *ValidationError {
+    return &ValidationError{Fields: fields}
+}
+
+func (e *ValidationError) Error() string {
+    names := make([]string, len(e.Fields))
+    for i, f := range e.Fields {
+        names[i] = f.Field
+    }
+    return fmt.Sprintf("%s: invalid %s", ErrValidation, strings.Join(names, ", "))
+}
+
+func (e *ValidationError) Unwrap() error {
+    return ErrValidation
+}
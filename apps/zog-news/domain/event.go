@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// EventType names a change to an article or topic
+type EventType string
+
+const (
+	EventArticleCreated   EventType = "article.created"
+	EventArticleUpdated   EventType = "article.updated"
+	EventArticleSubmitted EventType = "article.submitted"
+	EventArticleScheduled EventType = "article.scheduled"
+	EventArticlePublished EventType = "article.published"
+	EventArticleRejected  EventType = "article.rejected"
+	EventArticleArchived  EventType = "article.archived"
+	EventArticleDeleted   EventType = "article.deleted"
+	EventArticleRestored  EventType = "article.restored"
+	EventArticlePurged    EventType = "article.purged"
+
+	// EventTopicLinked and EventTopicUnlinked belong to the article, their
+	// payload holds the article_id and topic_id
+	EventTopicLinked   EventType = "topic.linked"
+	EventTopicUnlinked EventType = "topic.unlinked"
+
+	EventTopicCreated  EventType = "topic.created"
+	EventTopicUpdated  EventType = "topic.updated"
+	EventTopicDeleted  EventType = "topic.deleted"
+	EventTopicRestored EventType = "topic.restored"
+	EventTopicPurged   EventType = "topic.purged"
+	// EventTopicMerged carries the target topic and the source_ids merged
+	// into it, the article links it moved have no events of their own
+	EventTopicMerged EventType = "topic.merged"
+)
+
+// EventAll subscribes a webhook to every event type
+const EventAll EventType = "*"
+
+var eventTypes = map[EventType]bool{
+	EventArticleCreated:   true,
+	EventArticleUpdated:   true,
+	EventArticleSubmitted: true,
+	EventArticleScheduled: true,
+	EventArticlePublished: true,
+	EventArticleRejected:  true,
+	EventArticleArchived:  true,
+	EventArticleDeleted:   true,
+	EventArticleRestored:  true,
+	EventArticlePurged:    true,
+	EventTopicLinked:      true,
+	EventTopicUnlinked:    true,
+	EventTopicCreated:     true,
+	EventTopicUpdated:     true,
+	EventTopicDeleted:     true,
+	EventTopicRestored:    true,
+	EventTopicPurged:      true,
+	EventTopicMerged:      true,
+}
+
+// IsValid reports whether t is a known event type
+func (t EventType) IsValid() bool {
+	return eventTypes[t]
+}
+
+// Aggregates events belong to
+const (
+	AggregateArticle = "article"
+	AggregateTopic   = "topic"
+)
+
+// Event is a change recorded in the outbox in the transaction that made it.
+// Payload is a snapshot of the aggregate right after the change, or right
+// before it for purges.
+type Event struct {
+	ID string `json:"id"`
+	// Sequence increases with every recorded event, consumers order by it
+	// since a retried event can arrive after newer ones
+	Sequence      int64           `json:"sequence"`
+	Type          EventType       `json:"type"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   string          `json:"aggregate_id"`
+	Payload       json.RawMessage `json:"payload"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+	// Attempts counts deliveries tried so far, including the current one
+	Attempts int `json:"-"`
+}
@@ -26,6 +26,37 @@ type ResponseMultipleData[Data any] struct {
 	Message string `json:"message" example:"Operation completed successfully"`
 }
 
+// CursorMeta represents the pagination state of a cursor paginated list
+// @Description Cursor pagination metadata
+type CursorMeta struct {
+	NextCursor string `json:"next_cursor" example:"eyJzIjoiY3JlYXRlZF9hdCIsInYiOiIyMDIzLTA2LTAxVDEyOjAwOjAwWiIsImlkIjoiZDRiODU4M2QifQ"`
+	HasMore    bool   `json:"has_more" example:"true"`
+}
+
+// ResponseCursorData represents an API response with a page of data items
+// @Description API response structure for cursor paginated data items
+type ResponseCursorData[Data any] struct {
+	Code    int        `json:"code" example:"200"`
+	Status  string     `json:"status" example:"success"`
+	Data    []Data     `json:"data"`
+	Meta    CursorMeta `json:"meta"`
+	Message string     `json:"message" example:"Operation completed successfully"`
+}
+
+// Problem represents an error response as RFC 9457 problem details
+// @Description Error response served as application/problem+json
+type Problem struct {
+	Type     string `json:"type" example:"urn:zog-news:problem:article_not_found"`
+	Title    string `json:"title" example:"Article not found"`
+	Status   int    `json:"status" example:"404"`
+	Detail   string `json:"detail,omitempty" example:"article not found"`
+	Instance string `json:"instance,omitempty" example:"/api/v1/articles/d4b8583d-5038-4838-bcd7-3d8dddfedd6a"`
+	// Stable machine readable error code, the last part of the type
+	Code string `json:"code" example:"article_not_found"`
+	// Every invalid field when the request failed validation
+	Errors []FieldError `json:"errors,omitempty"`
+}
+
 // Empty represents an empty response data
 // @Description Empty response data structure
 type Empty struct{}
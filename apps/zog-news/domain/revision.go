@@ -0,0 +1,104 @@
+package domain
+
+import (
+	"strings"
+	"time"
+
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// ArticleRevision represents a snapshot of an article taken on every change
+//	@Description	Snapshot of an article's title and content
+type ArticleRevision struct {
+	ID        string `json:"id" example:"0c7e2f5a-9d1b-4e3a-8f6c-2b5d7a9e1c3f"`
+	ArticleID string `json:"article_id" example:"d4b8583d-5038-4838-bcd7-3d8dddfedd6a"`
+	Revision  int    `json:"revision" example:"3"`
+	Title     string `json:"title" example:"Breaking News: Important Update"`
+	Content   string `json:"content" example:"This is the content of the article..."`
+	// ContentFormat is how Content is written
+	ContentFormat ContentFormat `json:"content_format" example:"markdown"`
+	// The user who made the change, empty once the user is removed
+	EditorID  string    `json:"editor_id" example:"7f1c2a9e-3b4d-4c5e-8f6a-9b0c1d2e3f4a"`
+	Editor    string    `json:"editor" example:"John Doe"`
+	CreatedAt time.Time `json:"created_at" example:"2023-06-01T12:30:00Z"`
+}
+
+// DiffOperation tells whether a line is kept, added or removed
+//	@Description	Diff line operation enum
+//	@Enum			equal,insert,delete
+type DiffOperation string
+
+const (
+	DiffEqual  DiffOperation = "equal"
+	DiffInsert DiffOperation = "insert"
+	DiffDelete DiffOperation = "delete"
+)
+
+// DiffLine represents a single line of a revision diff
+type DiffLine struct {
+	Op   DiffOperation `json:"op" example:"insert"`
+	Text string        `json:"text" example:"This line was added"`
+}
+
+// ArticleRevisionDiff represents the line-level changes between two revisions
+//	@Description	Line-level diff of the title and content between two revisions
+type ArticleRevisionDiff struct {
+	ArticleID string     `json:"article_id" example:"d4b8583d-5038-4838-bcd7-3d8dddfedd6a"`
+	From      int        `json:"from" example:"1"`
+	To        int        `json:"to" example:"3"`
+	Title     []DiffLine `json:"title"`
+	Content   []DiffLine `json:"content"`
+}
+
+// RevisionDiffFilter represents the revisions to compare, To defaults to the
+// latest revision and From to the one before To
+type RevisionDiffFilter struct {
+	From int `query:"from" example:"1"`
+	To   int `query:"to" example:"3"`
+}
+
+// DiffRevisions compares two revisions line by line
+func DiffRevisions(from, to *ArticleRevision) ArticleRevisionDiff {
+	return ArticleRevisionDiff{
+		ArticleID: to.ArticleID,
+		From:      from.Revision,
+		To:        to.Revision,
+		Title:     diffLines(from.Title, to.Title),
+		Content:   diffLines(from.Content, to.Content),
+	}
+}
+
+func diffLines(a, b string) []DiffLine {
+	// a missing newline at the end must not mark the last line as changed
+	a, b = withTrailingNewline(a), withTrailingNewline(b)
+
+	dmp := diffmatchpatch.New()
+	// diff whole lines by mapping each distinct line to a single rune first
+	runesA, runesB, lines := dmp.DiffLinesToRunes(a, b)
+	diffs := dmp.DiffCharsToLines(dmp.DiffMainRunes(runesA, runesB, false), lines)
+
+	result := []DiffLine{}
+	for _, d := range diffs {
+		op := DiffEqual
+		switch d.Type {
+		case diffmatchpatch.DiffInsert:
+			op = DiffInsert
+		case diffmatchpatch.DiffDelete:
+			op = DiffDelete
+		}
+		for _, line := range strings.SplitAfter(d.Text, "\n") {
+			if line == "" {
+				continue
+			}
+			result = append(result, DiffLine{Op: op, Text: strings.TrimSuffix(line, "\n")})
+		}
+	}
+	return result
+}
+
+func withTrailingNewline(s string) string {
+	if s == "" || strings.HasSuffix(s, "\n") {
+		return s
+	}
+	return s + "\n"
+}
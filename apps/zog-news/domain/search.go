@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ArticleSearchFilter represents query parameters for full-text searching articles
+//	@Description	Query parameters for searching articles
+type ArticleSearchFilter struct {
+	// Web search syntax: "quoted phrase", -negation, OR and trailing * for prefixes
+	Query  string        `json:"q" query:"q" example:"\"breaking news\" -sport tech*"`
+	Status ArticleStatus `json:"status" query:"status" validate:"omitempty,article_status" example:"published"`
+	Topic  string        `json:"topic" query:"topic" example:"technology"`
+	Limit  int           `json:"limit" query:"limit" example:"20"`
+	Offset int           `json:"offset" query:"offset" example:"0"`
+
+	Scope ArticleScope `json:"scope" swaggerignore:"true"`
+}
+
+// Normalize fills in pagination defaults and rejects an empty query
+func (f *ArticleSearchFilter) Normalize() error {
+	f.Query = strings.TrimSpace(f.Query)
+	if f.Query == "" {
+		return fmt.Errorf("%w: search query is required", ErrBadParamInput)
+	}
+
+	if f.Limit <= 0 {
+		f.Limit = DefaultArticleLimit
+	}
+	if f.Limit > MaxArticleLimit {
+		f.Limit = MaxArticleLimit
+	}
+	if f.Offset < 0 {
+		f.Offset = 0
+	}
+	return nil
+}
+
+// ArticleSearchResult represents an article matched by a full-text search
+//	@Description	Article matched by a search with its rank and highlighted snippet
+type ArticleSearchResult struct {
+	Article
+	Rank     float32 `json:"rank" example:"0.6079271"`
+	Headline string  `json:"headline" example:"the <mark>breaking</mark> <mark>news</mark> of the day"`
+}
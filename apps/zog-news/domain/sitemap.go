@@ -0,0 +1,23 @@
+package domain
+
+import "time"
+
+const (
+	// SitemapMaxURLs is how many URLs a single sitemap may list, larger sites
+	// split them behind a sitemap index
+	SitemapMaxURLs = 50000
+	// NewsSitemapMaxURLs is how many articles a Google News sitemap may list
+	NewsSitemapMaxURLs = 1000
+	// NewsSitemapWindow is how far back a Google News sitemap reaches
+	NewsSitemapWindow = 48 * time.Hour
+)
+
+// SitemapEntry is a published article or live topic listed in a sitemap
+type SitemapEntry struct {
+	ID    string
+	Slug  string
+	Title string
+	// PublishedAt is only set on articles
+	PublishedAt  *time.Time
+	LastModified time.Time
+}
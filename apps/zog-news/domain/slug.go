@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// MaxSlugLength caps generated slugs, longer titles are cut at a word boundary
+const MaxSlugLength = 80
+
+// slugTransliterations spells out lowercase letters that don't decompose
+// into an ASCII letter plus accents
+var slugTransliterations = map[rune]string{
+	'ß': "ss", 'æ': "ae", 'œ': "oe", 'ø': "o", 'đ': "d", 'ð': "d",
+	'ł': "l", 'þ': "th", 'ı': "i", 'ħ': "h",
+
+	// Cyrillic
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ж': "zh",
+	'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n",
+	'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f",
+	'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y",
+	'ь': "", 'э': "e", 'ю': "yu", 'я': "ya", 'ё': "yo", 'є': "ye", 'і': "i",
+	'ї': "yi", 'ґ': "g",
+
+	// Greek
+	'α': "a", 'β': "v", 'γ': "g", 'δ': "d", 'ε': "e", 'ζ': "z", 'η': "i",
+	'θ': "th", 'ι': "i", 'κ': "k", 'λ': "l", 'μ': "m", 'ν': "n", 'ξ': "x",
+	'ο': "o", 'π': "p", 'ρ': "r", 'σ': "s", 'ς': "s", 'τ': "t", 'υ': "y",
+	'φ': "f", 'χ': "ch", 'ψ': "ps", 'ω': "o",
+}
+
+// Slugify turns a title into a lowercase ASCII slug of letters, digits and
+// single hyphens. It returns an empty string when nothing is left.
+func Slugify(s string) string {
+	var b strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(s) {
+		part, known := slugTransliterations[r]
+		if !known {
+			// drop the accents of letters such as é or ά and keep their base
+			for _, d := range norm.NFD.String(string(r)) {
+				if d >= 'a' && d <= 'z' || d >= '0' && d <= '9' {
+					part += string(d)
+				} else {
+					part += slugTransliterations[d]
+				}
+			}
+		}
+		if part == "" {
+			// anything else separates words, unless it is a silent letter
+			if !known {
+				hyphen = b.Len() > 0
+			}
+			continue
+		}
+
+		if hyphen {
+			b.WriteByte('-')
+			hyphen = false
+		}
+		b.WriteString(part)
+	}
+
+	slug := b.String()
+	if len(slug) > MaxSlugLength {
+		slug = slug[:MaxSlugLength]
+		if i := strings.LastIndexByte(slug, '-'); i > 0 {
+			slug = slug[:i]
+		}
+	}
+	return strings.Trim(slug, "-")
+}
@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Windows trending articles can be ranked over
+const (
+	Window24h = "24h"
+	Window7d  = "7d"
+	Window30d = "30d"
+)
+
+// TrendingWindows maps the trending windows to their length
+var TrendingWindows = map[string]time.Duration{
+	Window24h: 24 * time.Hour,
+	Window7d:  7 * 24 * time.Hour,
+	Window30d: 30 * 24 * time.Hour,
+}
+
+const (
+	// DefaultTrendingLimit is how many trending articles are listed when no
+	// limit is given
+	DefaultTrendingLimit = 10
+	// MaxTrendingLimit caps how many trending articles a client may request
+	MaxTrendingLimit = 50
+
+	// DefaultStatsDays is how many days of views article stats cover when
+	// not asked otherwise
+	DefaultStatsDays = 30
+	// MaxStatsDays caps how many days of views article stats may cover
+	MaxStatsDays = 365
+)
+
+// TrendingFilter represents query parameters for ranking trending articles
+//	@Description	Query parameters for trending articles
+type TrendingFilter struct {
+	Window string `json:"window" query:"window" example:"24h"`
+	Topic  string `json:"topic" query:"topic" example:"technology"`
+	Limit  int    `json:"limit" query:"limit" example:"10"`
+}
+
+// Normalize fills in the defaults and rejects unknown windows
+func (f *TrendingFilter) Normalize() error {
+	f.Window = strings.TrimSpace(f.Window)
+	if f.Window == "" {
+		f.Window = Window24h
+	}
+	if _, ok := TrendingWindows[f.Window]; !ok {
+		return fmt.Errorf("%w: window must be 24h, 7d or 30d", ErrBadParamInput)
+	}
+
+	if f.Limit <= 0 {
+		f.Limit = DefaultTrendingLimit
+	}
+	if f.Limit > MaxTrendingLimit {
+		f.Limit = MaxTrendingLimit
+	}
+	return nil
+}
+
+// HalfLife is how long a view takes to lose half its weight in the trending
+// score, half the window
+func (f *TrendingFilter) HalfLife() time.Duration {
+	return TrendingWindows[f.Window] / 2
+}
+
+// TrendingArticle represents a published article ranked by its recent views
+//	@Description	Published article with its views in the window and trending score
+type TrendingArticle struct {
+	Article
+	Views int64   `json:"views" example:"1520"`
+	Score float64 `json:"score" example:"874.25"`
+}
+
+// ArticleViews is a number of views an article got on a day, counted in
+// memory and added to its stats in batches
+type ArticleViews struct {
+	ArticleID string
+	// Day is midnight UTC of the day the views happened
+	Day   time.Time
+	Views int64
+}
+
+// DailyViews represents the views of an article on one day
+//	@Description	Views of an article on one day, UTC
+type DailyViews struct {
+	Date  string `json:"date" example:"2025-07-18"`
+	Views int64  `json:"views" example:"312"`
+}
+
+// ArticleStats represents the views of an article over the last days
+//	@Description	Views of an article per day, oldest first, days without views included
+type ArticleStats struct {
+	ArticleID  string       `json:"article_id" example:"d4b8583d-5038-4838-bcd7-3d8dddfedd6a"`
+	TotalViews int64        `json:"total_views" example:"4210"`
+	Days       []DailyViews `json:"days"`
+}
@@ -1,6 +1,8 @@
 package domain
 
 import (
+	"sort"
+	"strings"
 	"time"
 )
 
@@ -9,20 +11,35 @@ import (
 type Topic struct {
 	ID        string    `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
 	Name      string    `json:"name" example:"Technology"`
+	Slug      string    `json:"slug" example:"technology"`
+	ParentID  *string   `json:"parent_id,omitempty" example:"6ba7b810-9dad-11d1-80b4-00c04fd430c8"`
+	Version   int       `json:"version,omitempty" example:"1"`
 	CreatedAt time.Time `json:"created_at" example:"2023-06-01T12:00:00Z"`
 	UpdatedAt time.Time `json:"updated_at" example:"2023-06-01T12:30:00Z"`
+	// Only set on topics in the trash
+	DeletedAt *time.Time `json:"deleted_at,omitempty" example:"2023-06-03T09:00:00Z"`
+	// Only set in the topic tree
+	Children []Topic `json:"children,omitempty"`
 }
 
 // CreateTopicRequest represents the request body for creating a topic
 // @Description Request body for creating a new topic
 type CreateTopicRequest struct {
-	Name string `json:"name" validate:"required" example:"Technology"`
+	Name     string  `json:"name" validate:"required,max=64" example:"Technology"`
+	ParentID *string `json:"parent_id,omitempty" validate:"omitempty,uuid" example:"6ba7b810-9dad-11d1-80b4-00c04fd430c8"`
 }
 
 // UpdateTopicRequest represents the request body for updating a topic
-// @Description Request body for updating an existing topic
+// @Description Request body for updating an existing topic, leaving out parent_id moves it to the top level
 type UpdateTopicRequest struct {
-	Name string `json:"name" validate:"required" example:"Updated Technology"`
+	Name     string  `json:"name" validate:"required,max=64" example:"Updated Technology"`
+	ParentID *string `json:"parent_id,omitempty" validate:"omitempty,uuid" example:"6ba7b810-9dad-11d1-80b4-00c04fd430c8"`
+}
+
+// MergeTopicsRequest represents the request body for merging topics into another
+// @Description Request body for merging topics into the target topic, the sources end up in the trash
+type MergeTopicsRequest struct {
+	SourceIDs []string `json:"source_ids" validate:"required,min=1,dive,uuid" example:"6ba7b810-9dad-11d1-80b4-00c04fd430c8"`
 }
 
 // TopicFilter represents query parameters for filtering topics
@@ -30,3 +47,49 @@ type UpdateTopicRequest struct {
 type TopicFilter struct {
 	Search string `json:"search" query:"search" example:"tech"`
 }
+
+// TopicArticlesFilter represents query parameters for listing a topic's articles
+// @Description Query parameters for listing a topic's articles
+type TopicArticlesFilter struct {
+	// Also return articles tagged with any subtopic
+	IncludeDescendants bool `json:"include_descendants" query:"include_descendants" example:"true"`
+
+	Scope ArticleScope `json:"scope" swaggerignore:"true"`
+}
+
+// NormalizeTopicName trims a topic name and collapses the whitespace inside it,
+// names that only differ in case are the same topic
+func NormalizeTopicName(name string) string {
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// BuildTopicTree nests a flat topic list under their parents, sorted by name.
+// Topics whose parent is not in the list become roots.
+func BuildTopicTree(topics []Topic) []Topic {
+	known := make(map[string]bool, len(topics))
+	for _, t := range topics {
+		known[t.ID] = true
+	}
+
+	children := make(map[string][]Topic)
+	var roots []Topic
+	for _, t := range topics {
+		if t.ParentID != nil && known[*t.ParentID] {
+			children[*t.ParentID] = append(children[*t.ParentID], t)
+			continue
+		}
+		roots = append(roots, t)
+	}
+
+	var attach func(nodes []Topic) []Topic
+	attach = func(nodes []Topic) []Topic {
+		sort.SliceStable(nodes, func(i, j int) bool {
+			return strings.ToLower(nodes[i].Name) < strings.ToLower(nodes[j].Name)
+		})
+		for i := range nodes {
+			nodes[i].Children = attach(children[nodes[i].ID])
+		}
+		return nodes
+	}
+	return attach(roots)
+}
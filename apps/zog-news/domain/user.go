@@ -0,0 +1,117 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Role represents what a user is allowed to do
+// @Description User role enum
+// @Enum author,editor,admin
+type Role string
+
+const (
+	// RoleAuthor may write articles and edit their own drafts
+	RoleAuthor Role = "author"
+	// RoleEditor may additionally edit, publish and archive anyone's article
+	RoleEditor Role = "editor"
+	// RoleAdmin may additionally manage topics and users
+	RoleAdmin Role = "admin"
+)
+
+// IsValid reports whether r is a known role
+func (r Role) IsValid() bool {
+	switch r {
+	case RoleAuthor, RoleEditor, RoleAdmin:
+		return true
+	}
+	return false
+}
+
+// User represents a registered user
+// @Description User account
+type User struct {
+	ID           string    `json:"id" example:"7f1c2a9e-3b4d-4c5e-8f6a-9b0c1d2e3f4a"`
+	Name         string    `json:"name" example:"John Doe"`
+	Email        string    `json:"email" example:"john@example.com"`
+	Role         Role      `json:"role" example:"author"`
+	PasswordHash string    `json:"-"`
+	CreatedAt    time.Time `json:"created_at" example:"2023-06-01T12:00:00Z"`
+	UpdatedAt    time.Time `json:"updated_at" example:"2023-06-01T12:30:00Z"`
+}
+
+// RegisterRequest represents the request body for registering a user
+// @Description Request body for registering a new user
+type RegisterRequest struct {
+	Name     string `json:"name" validate:"required,max=100" example:"John Doe"`
+	Email    string `json:"email" validate:"required,email,max=255" example:"john@example.com"`
+	Password string `json:"password" validate:"required,min=8,max=72" example:"correct horse battery"`
+}
+
+// LoginRequest represents the request body for logging in
+// @Description Request body for logging in with email and password
+type LoginRequest struct {
+	Email    string `json:"email" validate:"required,email" example:"john@example.com"`
+	Password string `json:"password" validate:"required" example:"correct horse battery"`
+}
+
+// RefreshTokenRequest represents the request body carrying a refresh token
+// @Description Request body for refreshing or revoking a session
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required" example:"q3Xk0rJ8bM1..."`
+}
+
+// UpdateUserRoleRequest represents the request body for changing a user's role
+// @Description Request body for changing the role of a user
+type UpdateUserRoleRequest struct {
+	Role Role `json:"role" validate:"required,oneof=author editor admin" example:"editor"`
+}
+
+// AuthTokens represents the tokens issued to an authenticated user
+// @Description Access and refresh tokens of a session
+type AuthTokens struct {
+	AccessToken  string    `json:"access_token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	RefreshToken string    `json:"refresh_token" example:"q3Xk0rJ8bM1..."`
+	TokenType    string    `json:"token_type" example:"Bearer"`
+	ExpiresAt    time.Time `json:"expires_at" example:"2023-06-01T12:15:00Z"`
+}
+
+// RefreshToken represents a stored refresh token session
+type RefreshToken struct {
+	ID        string
+	UserID    string
+	TokenHash string
+	ExpiresAt time.Time
+	RevokedAt *time.Time
+}
+
+// AuthUser represents the caller identified by an access token
+type AuthUser struct {
+	ID    string
+	Email string
+	Role  Role
+}
+
+// HasRole reports whether the caller's role is at least the given one
+func (u *AuthUser) HasRole(role Role) bool {
+	return roleRank[u.Role] >= roleRank[role]
+}
+
+var roleRank = map[Role]int{
+	RoleAuthor: 1,
+	RoleEditor: 2,
+	RoleAdmin:  3,
+}
+
+type authUserKey struct{}
+
+// ContextWithAuthUser returns a copy of ctx carrying the authenticated caller
+func ContextWithAuthUser(ctx context.Context, user *AuthUser) context.Context {
+	return context.WithValue(ctx, authUserKey{}, user)
+}
+
+// AuthUserFromContext returns the authenticated caller stored in ctx, if any
+func AuthUserFromContext(ctx context.Context) (*AuthUser, bool) {
+	user, ok := ctx.Value(authUserKey{}).(*AuthUser)
+	return user, ok && user != nil
+}
@@ -0,0 +1,74 @@
+package domain
+
+import "time"
+
+// Webhook is a subscriber URL events are POSTed to
+// @Description Webhook subscription
+type Webhook struct {
+	ID  string `json:"id" example:"3f2b8c1e-7a4d-4e9b-9c2a-1d5e6f7a8b9c"`
+	URL string `json:"url" example:"https://example.com/hooks/zog-news"`
+	// Event types delivered to the URL, * delivers every event
+	EventTypes []EventType `json:"event_types" example:"article.published"`
+	// Key of the X-Signature HMAC, only returned when the webhook is created
+	Secret string `json:"secret,omitempty" example:"whsec_6fJ2c0yq1xv8Qk3mT9rLw4HnZbA7sDeP"`
+	// Active is false once the webhook was disabled after repeated failures
+	Active bool `json:"active" example:"true"`
+	// Failed delivery attempts since the last successful one
+	FailureCount int        `json:"failure_count" example:"0"`
+	DisabledAt   *time.Time `json:"disabled_at,omitempty" example:"2023-06-03T09:00:00Z"`
+	CreatedBy    string     `json:"created_by" example:"7f1c2a9e-3b4d-4c5e-8f6a-9b0c1d2e3f4a"`
+	CreatedAt    time.Time  `json:"created_at" example:"2023-06-01T12:00:00Z"`
+	UpdatedAt    time.Time  `json:"updated_at" example:"2023-06-01T12:30:00Z"`
+}
+
+// CreateWebhookRequest represents the request body for registering a webhook
+// @Description Request body for registering a webhook
+type CreateWebhookRequest struct {
+	URL        string      `json:"url" validate:"required,url,max=2048" example:"https://example.com/hooks/zog-news"`
+	EventTypes []EventType `json:"event_types" validate:"required,min=1,dive,event_type" example:"article.published"`
+}
+
+// DeliveryStatus tells where a webhook delivery stands
+// @Description Webhook delivery status enum
+// @Enum pending,succeeded,failed
+type DeliveryStatus string
+
+const (
+	// DeliveryPending is waiting for its first or next attempt
+	DeliveryPending DeliveryStatus = "pending"
+	// DeliverySucceeded got a 2xx response
+	DeliverySucceeded DeliveryStatus = "succeeded"
+	// DeliveryFailed used up its attempts
+	DeliveryFailed DeliveryStatus = "failed"
+)
+
+// WebhookDelivery is one event sent, or to be sent, to a webhook
+// @Description Delivery of an event to a webhook
+type WebhookDelivery struct {
+	ID        string         `json:"id" example:"0c7e2f5a-9d1b-4e3a-8f6c-2b5d7a9e1c3f"`
+	WebhookID string         `json:"webhook_id" example:"3f2b8c1e-7a4d-4e9b-9c2a-1d5e6f7a8b9c"`
+	EventID   string         `json:"event_id" example:"5b1d3e7f-2a4c-4b6d-8e0f-1a3c5e7b9d2f"`
+	EventType EventType      `json:"event_type" example:"article.published"`
+	Status    DeliveryStatus `json:"status" example:"succeeded"`
+	Attempts  int            `json:"attempts" example:"1"`
+	// HTTP status of the last response, absent when no response came back
+	ResponseStatus *int       `json:"response_status,omitempty" example:"200"`
+	LastError      string     `json:"last_error,omitempty" example:"webhook responded with 503 Service Unavailable"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at,omitempty" example:"2023-06-01T12:01:00Z"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty" example:"2023-06-01T12:00:01Z"`
+	CreatedAt      time.Time  `json:"created_at" example:"2023-06-01T12:00:00Z"`
+}
+
+// PendingDelivery is a claimed delivery with everything needed to send it
+type PendingDelivery struct {
+	ID        string
+	WebhookID string
+	URL       string
+	Secret    string
+	EventID   string
+	EventType EventType
+	// Body is the event as JSON, signed and sent as is
+	Body []byte
+	// Attempts counts deliveries tried so far, including the current one
+	Attempts int
+}
@@ -0,0 +1,63 @@
+package domain
+
+import "time"
+
+// articleTransitions is the editorial workflow: drafts are submitted for
+// review, reviewed articles are published or rejected back to draft, and
+// published articles are eventually archived.
+var articleTransitions = map[ArticleStatus][]ArticleStatus{
+	StatusDraft:     {StatusInReview},
+	StatusInReview:  {StatusPublished, StatusDraft},
+	StatusPublished: {StatusArchived},
+	StatusArchived:  {},
+}
+
+// IsValid reports whether s is a known status
+func (s ArticleStatus) IsValid() bool {
+	_, ok := articleTransitions[s]
+	return ok
+}
+
+// CanTransitionTo reports whether the workflow allows moving from s to next
+func (s ArticleStatus) CanTransitionTo(next ArticleStatus) bool {
+	for _, allowed := range articleTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// ArticleTransition represents a status change of an article. From guards
+// against concurrent changes, the transition only applies while the article
+// still has that status.
+type ArticleTransition struct {
+	From        ArticleStatus
+	To          ArticleStatus
+	PublishedAt *time.Time
+	ScheduledAt *time.Time
+}
+
+// EventType returns the event recorded when the transition applies
+func (t *ArticleTransition) EventType() EventType {
+	switch {
+	case t.To == StatusPublished:
+		return EventArticlePublished
+	case t.To == StatusArchived:
+		return EventArticleArchived
+	case t.To == StatusDraft:
+		return EventArticleRejected
+	case t.From == StatusInReview:
+		// still in review, waiting for its scheduled time
+		return EventArticleScheduled
+	default:
+		return EventArticleSubmitted
+	}
+}
+
+// PublishArticleRequest represents the request body for publishing an article
+//	@Description	Request body for publishing an article now or at a later time
+type PublishArticleRequest struct {
+	// Leave empty to publish right away
+	ScheduledAt *time.Time `json:"scheduled_at,omitempty" example:"2023-06-02T08:00:00Z"`
+}
@@ -0,0 +1,129 @@
+// Package events relays the domain events recorded in the outbox to a sink.
+//
+// Repositories record an event in the transaction of the change it
+// describes, so an event exists if and only if the change commits. The Relay
+// picks them up oldest first and publishes them at least once. A failed event
+// is retried with a growing delay while newer events go on, consumers that
+// care about order sort by Event.Sequence and drop duplicates by Event.ID.
+package events
+
+import (
+	"context"
+	"log/slog"
+	"time"
+	"zog-news/config"
+	"zog-news/domain"
+
+	"go.opentelemetry.io/otel"
+)
+
+const (
+	// batchSize is how many events one round claims
+	batchSize = 100
+	// claimLease is how long a claimed event is hidden from other relays
+	claimLease = 5 * time.Minute
+
+	// the first retry waits retryBase, every further one twice as long up
+	// to retryMax
+	retryBase = 5 * time.Second
+	retryMax  = 30 * time.Minute
+)
+
+// Outbox is where events wait to be published
+type Outbox interface {
+	ClaimEvents(ctx context.Context, now, lease time.Time, limit int) ([]domain.Event, error)
+	MarkEventPublished(ctx context.Context, id string, publishedAt time.Time) error
+	MarkEventFailed(ctx context.Context, id string, reason string, retryAt *time.Time) error
+}
+
+// Relay publishes the events in the outbox to a sink
+type Relay struct {
+	outbox Outbox
+	sink   Sink
+	config config.EventsConfig
+	now    func() time.Time
+}
+
+func NewRelay(outbox Outbox, sink Sink, config config.EventsConfig) *Relay {
+	return &Relay{
+		outbox: outbox,
+		sink:   sink,
+		config: config,
+		now:    time.Now,
+	}
+}
+
+// Run publishes due events every interval until ctx is done
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := r.PublishDue(ctx); err != nil && ctx.Err() == nil {
+				slog.Error("Event relay failed", "error", err)
+			}
+		}
+	}
+}
+
+// PublishDue claims the events due now, publishes them and returns how many
+// were published. Failed events are rescheduled, so only outbox errors are
+// returned.
+func (r *Relay) PublishDue(ctx context.Context) (int, error) {
+	tracer := otel.Tracer("events.relay")
+	ctx, span := tracer.Start(ctx, "Relay.PublishDue")
+	defer span.End()
+
+	now := r.now().UTC()
+	events, err := r.outbox.ClaimEvents(ctx, now, now.Add(claimLease), batchSize)
+	if err != nil {
+		span.RecordError(err)
+		return 0, err
+	}
+
+	published := 0
+	for _, event := range events {
+		if ctx.Err() != nil {
+			// unpublished claims are retried once their lease passes
+			return published, ctx.Err()
+		}
+
+		publishErr := r.sink.Publish(ctx, event)
+		if publishErr == nil {
+			published++
+			err = r.outbox.MarkEventPublished(ctx, event.ID, r.now().UTC())
+		} else {
+			slog.WarnContext(ctx, "Publishing event failed",
+				slog.String("id", event.ID),
+				slog.String("type", string(event.Type)),
+				slog.Int("attempt", event.Attempts),
+				slog.String("error", publishErr.Error()),
+			)
+			err = r.outbox.MarkEventFailed(ctx, event.ID, publishErr.Error(), r.retryAt(event))
+		}
+		if err != nil {
+			span.RecordError(err)
+			return published, err
+		}
+	}
+	return published, nil
+}
+
+// retryAt returns when a failed event is tried again, nil once it used up its
+// attempts
+func (r *Relay) retryAt(event domain.Event) *time.Time {
+	if event.Attempts >= r.config.MaxAttempts {
+		return nil
+	}
+
+	delay := retryBase
+	for i := 1; i < event.Attempts && delay < retryMax; i++ {
+		delay *= 2
+	}
+	at := r.now().UTC().Add(min(delay, retryMax))
+	return &at
+}
@@ -0,0 +1,141 @@
+package events_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+	"zog-news/config"
+	"zog-news/domain"
+	"zog-news/events"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeOutbox struct {
+	due       []domain.Event
+	published []string
+	failed    map[string]*time.Time
+}
+
+func (f *fakeOutbox) ClaimEvents(_ context.Context, _, _ time.Time, _ int) ([]domain.Event, error) {
+	due := f.due
+	f.due = nil
+	return due, nil
+}
+
+func (f *fakeOutbox) MarkEventPublished(_ context.Context, id string, _ time.Time) error {
+	f.published = append(f.published, id)
+	return nil
+}
+
+func (f *fakeOutbox) MarkEventFailed(_ context.Context, id string, _ string, retryAt *time.Time) error {
+	if f.failed == nil {
+		f.failed = make(map[string]*time.Time)
+	}
+	f.failed[id] = retryAt
+	return nil
+}
+
+func articleEvent(id string, sequence int64, attempts int) domain.Event {
+	return domain.Event{
+		ID:            id,
+		Sequence:      sequence,
+		Type:          domain.EventArticlePublished,
+		AggregateType: domain.AggregateArticle,
+		AggregateID:   "d4b8583d-5038-4838-bcd7-3d8dddfedd6a",
+		Payload:       json.RawMessage(`{"title":"Breaking News"}`),
+		OccurredAt:    time.Date(2025, 7, 15, 8, 0, 0, 0, time.UTC),
+		Attempts:      attempts,
+	}
+}
+
+func TestRelayPublishDue(t *testing.T) {
+	t.Parallel()
+
+	eventsConfig := config.EventsConfig{Interval: time.Second, MaxAttempts: 3}
+
+	t.Run("Published", func(t *testing.T) {
+		outbox := &fakeOutbox{due: []domain.Event{articleEvent("1", 1, 1), articleEvent("2", 2, 1)}}
+		sink := events.NewMemorySink()
+
+		published, err := events.NewRelay(outbox, sink, eventsConfig).PublishDue(context.Background())
+		require.NoError(t, err)
+
+		assert.Equal(t, 2, published)
+		assert.Equal(t, []string{"1", "2"}, outbox.published)
+		require.Len(t, sink.Events(), 2)
+		assert.Equal(t, int64(1), sink.Events()[0].Sequence)
+	})
+
+	t.Run("RetriedWithBackoff", func(t *testing.T) {
+		outbox := &fakeOutbox{due: []domain.Event{articleEvent("1", 1, 1), articleEvent("2", 2, 3)}}
+		sink := events.NewMemorySink()
+		sink.SetErr(errors.New("sink unavailable"))
+
+		before := time.Now().UTC()
+		published, err := events.NewRelay(outbox, sink, eventsConfig).PublishDue(context.Background())
+		require.NoError(t, err)
+
+		assert.Zero(t, published)
+		require.NotNil(t, outbox.failed["1"])
+		assert.WithinDuration(t, before.Add(5*time.Second), *outbox.failed["1"], 2*time.Second)
+		// out of attempts
+		require.Contains(t, outbox.failed, "2")
+		assert.Nil(t, outbox.failed["2"])
+	})
+}
+
+func TestStdoutSink(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	sink := events.NewStdoutSink(&out)
+	require.NoError(t, sink.Publish(context.Background(), articleEvent("1", 1, 1)))
+
+	var event map[string]any
+	require.NoError(t, json.Unmarshal(out.Bytes(), &event))
+	assert.Equal(t, "article.published", event["type"])
+	assert.Equal(t, map[string]any{"title": "Breaking News"}, event["payload"])
+	assert.NotContains(t, event, "Attempts")
+}
+
+func TestWebhookSink(t *testing.T) {
+	t.Parallel()
+
+	var received []byte
+	var status atomic.Int32
+	status.Store(http.StatusNoContent)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "article.published", r.Header.Get("X-Event-Type"))
+		received, _ = io.ReadAll(r.Body)
+		w.WriteHeader(int(status.Load()))
+	}))
+	defer server.Close()
+
+	sink := events.NewWebhookSink(server.URL)
+	require.NoError(t, sink.Publish(context.Background(), articleEvent("1", 1, 1)))
+	assert.Contains(t, string(received), `"aggregate_type":"article"`)
+
+	status.Store(http.StatusInternalServerError)
+	assert.Error(t, sink.Publish(context.Background(), articleEvent("1", 1, 1)))
+}
+
+func TestMultiSink(t *testing.T) {
+	t.Parallel()
+
+	first, second := events.NewMemorySink(), events.NewMemorySink()
+	second.SetErr(errors.New("sink unavailable"))
+
+	err := events.MultiSink{first, second}.Publish(context.Background(), articleEvent("1", 1, 1))
+	assert.Error(t, err)
+	// the healthy sink still got the event
+	assert.Len(t, first.Events(), 1)
+}
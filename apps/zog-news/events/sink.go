@@ -0,0 +1,139 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+	"zog-news/domain"
+)
+
+// Sink is where the relay publishes events to
+type Sink interface {
+	Publish(ctx context.Context, event domain.Event) error
+}
+
+// MultiSink publishes every event to each of its sinks, it fails when any of
+// them does. A retried event reaches the sinks that took it before again.
+type MultiSink []Sink
+
+func (m MultiSink) Publish(ctx context.Context, event domain.Event) error {
+	var errs []error
+	for _, sink := range m {
+		if err := sink.Publish(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// StdoutSink writes every event as a line of JSON
+type StdoutSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func NewStdoutSink(w io.Writer) *StdoutSink {
+	return &StdoutSink{w: w}
+}
+
+func (s *StdoutSink) Publish(_ context.Context, event domain.Event) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.w.Write(append(line, '\n'))
+	return err
+}
+
+// webhookTimeout bounds a single POST to the webhook
+const webhookTimeout = 10 * time.Second
+
+// WebhookSink POSTs every event as JSON to a URL, any response other than
+// 2xx fails the publish
+type WebhookSink struct {
+	url    string
+	client *http.Client
+}
+
+func NewWebhookSink(url string) *WebhookSink {
+	return &WebhookSink{
+		url:    url,
+		client: &http.Client{Timeout: webhookTimeout},
+	}
+}
+
+func (s *WebhookSink) Publish(ctx context.Context, event domain.Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Event-ID", event.ID)
+	req.Header.Set("X-Event-Type", string(event.Type))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// drain the body so the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with %s", resp.Status)
+	}
+	return nil
+}
+
+// MemorySink keeps published events in memory, for tests
+type MemorySink struct {
+	mu     sync.Mutex
+	events []domain.Event
+	err    error
+}
+
+func NewMemorySink() *MemorySink {
+	return &MemorySink{}
+}
+
+func (s *MemorySink) Publish(_ context.Context, event domain.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err != nil {
+		return s.err
+	}
+	s.events = append(s.events, event)
+	return nil
+}
+
+// SetErr makes Publish fail with err instead of keeping events, nil restores it
+func (s *MemorySink) SetErr(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.err = err
+}
+
+// Events returns the events published so far
+func (s *MemorySink) Events() []domain.Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.events)
+}
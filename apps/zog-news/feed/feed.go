@@ -0,0 +1,219 @@
+// Package feed renders article lists as RSS 2.0, Atom 1.0 and JSON Feed 1.1
+// documents, and URL lists as sitemaps.
+package feed
+
+import (
+	"encoding/xml"
+	"io"
+	"time"
+	"zog-news/render"
+)
+
+const (
+	// Content types the documents are served with
+	ContentTypeRSS  = "application/rss+xml; charset=utf-8"
+	ContentTypeAtom = "application/atom+xml; charset=utf-8"
+	ContentTypeJSON = "application/feed+json; charset=utf-8"
+	ContentTypeXML  = "application/xml; charset=utf-8"
+)
+
+// Feed is the format independent content of a feed
+type Feed struct {
+	// ID identifies the feed for good, Atom only
+	ID          string
+	Title       string
+	Description string
+	// Language is the ISO 639 code the items are written in
+	Language string
+	// Link is the page the feed describes, SelfLink the feed itself
+	Link     string
+	SelfLink string
+	// Updated is when an item last changed, the zero time when there are none
+	Updated time.Time
+	Items   []Item
+}
+
+// Item is an entry of a feed
+type Item struct {
+	// ID is the UUID of the article, items keep it across edits
+	ID     string
+	Title  string
+	Link   string
+	Author string
+	// ContentHTML is the sanitized HTML rendered from the article content
+	ContentHTML string
+	Excerpt     string
+	Categories  []string
+	Published   time.Time
+	Updated     time.Time
+}
+
+// Summary returns the plain text excerpt of the item, or the start of its
+// content cut at a word boundary when it has none
+func (i *Item) Summary() string {
+	if i.Excerpt != "" {
+		return i.Excerpt
+	}
+	return render.Excerpt(render.Text(i.ContentHTML))
+}
+
+func (i *Item) guid() string {
+	return "urn:uuid:" + i.ID
+}
+
+type rss struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	AtomNS  string     `xml:"xmlns:atom,attr"`
+	DCNS    string     `xml:"xmlns:dc,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	Language      string    `xml:"language,omitempty"`
+	AtomLink      atomLink  `xml:"atom:link"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Generator     string    `xml:"generator"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	Description string   `xml:"description"`
+	Creator     string   `xml:"dc:creator,omitempty"`
+	Categories  []string `xml:"category"`
+	GUID        rssGUID  `xml:"guid"`
+	PubDate     string   `xml:"pubDate"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// WriteRSS writes f as an RSS 2.0 document
+func WriteRSS(w io.Writer, f *Feed) error {
+	doc := rss{
+		Version: "2.0",
+		AtomNS:  "http://www.w3.org/2005/Atom",
+		DCNS:    "http://purl.org/dc/elements/1.1/",
+		Channel: rssChannel{
+			Title:       f.Title,
+			Link:        f.Link,
+			Description: f.Description,
+			Language:    f.Language,
+			AtomLink:    atomLink{Href: f.SelfLink, Rel: "self", Type: "application/rss+xml"},
+			Generator:   "zog-news",
+		},
+	}
+	if !f.Updated.IsZero() {
+		doc.Channel.LastBuildDate = f.Updated.UTC().Format(time.RFC1123Z)
+	}
+	for _, item := range f.Items {
+		doc.Channel.Items = append(doc.Channel.Items, rssItem{
+			Title:       item.Title,
+			Link:        item.Link,
+			Description: item.Summary(),
+			Creator:     item.Author,
+			Categories:  item.Categories,
+			GUID:        rssGUID{Value: item.guid()},
+			PubDate:     item.Published.UTC().Format(time.RFC1123Z),
+		})
+	}
+	return write(w, doc)
+}
+
+type atom struct {
+	XMLName   xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Lang      string      `xml:"xml:lang,attr,omitempty"`
+	ID        string      `xml:"id"`
+	Title     string      `xml:"title"`
+	Subtitle  string      `xml:"subtitle,omitempty"`
+	Updated   string      `xml:"updated"`
+	Links     []atomLink  `xml:"link"`
+	Generator string      `xml:"generator"`
+	Entries   []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Link       atomLink       `xml:"link"`
+	Author     *atomAuthor    `xml:"author"`
+	Categories []atomCategory `xml:"category"`
+	Summary    string         `xml:"summary"`
+	Published  string         `xml:"published"`
+	Updated    string         `xml:"updated"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+// WriteAtom writes f as an Atom 1.0 document
+func WriteAtom(w io.Writer, f *Feed, now time.Time) error {
+	// a feed without entries is still updated, say when it was built
+	updated := f.Updated
+	if updated.IsZero() {
+		updated = now
+	}
+
+	doc := atom{
+		Lang:     f.Language,
+		ID:       f.ID,
+		Title:    f.Title,
+		Subtitle: f.Description,
+		Updated:  updated.UTC().Format(time.RFC3339),
+		Links: []atomLink{
+			{Href: f.Link, Rel: "alternate"},
+			{Href: f.SelfLink, Rel: "self", Type: "application/atom+xml"},
+		},
+		Generator: "zog-news",
+	}
+	for _, item := range f.Items {
+		entry := atomEntry{
+			ID:        item.guid(),
+			Title:     item.Title,
+			Link:      atomLink{Href: item.Link, Rel: "alternate"},
+			Summary:   item.Summary(),
+			Published: item.Published.UTC().Format(time.RFC3339),
+			Updated:   item.Updated.UTC().Format(time.RFC3339),
+		}
+		// Atom requires an author on every entry of a feed without one
+		entry.Author = &atomAuthor{Name: item.Author}
+		if item.Author == "" {
+			entry.Author.Name = f.Title
+		}
+		for _, category := range item.Categories {
+			entry.Categories = append(entry.Categories, atomCategory{Term: category})
+		}
+		doc.Entries = append(doc.Entries, entry)
+	}
+	return write(w, doc)
+}
+
+func write(w io.Writer, doc any) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	return enc.Close()
+}
@@ -0,0 +1,21 @@
+package feed_test
+
+import (
+	"strings"
+	"testing"
+	"zog-news/feed"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestItemSummary(t *testing.T) {
+	t.Parallel()
+
+	short := feed.Item{ContentHTML: "<p>Short</p>\n\n<p><em>content</em></p>"}
+	assert.Equal(t, "Short content", short.Summary())
+
+	long := feed.Item{ContentHTML: "<p>" + strings.Repeat("word ", 100) + "</p>"}
+	summary := long.Summary()
+	assert.True(t, strings.HasSuffix(summary, "word…"), summary)
+	assert.LessOrEqual(t, len([]rune(summary)), 281)
+}
@@ -0,0 +1,108 @@
+package feed
+
+import (
+	"encoding/xml"
+	"io"
+	"time"
+)
+
+// URL is a page listed in a sitemap
+type URL struct {
+	Loc          string
+	LastModified time.Time
+}
+
+// NewsURL is an article listed in a Google News sitemap
+type NewsURL struct {
+	Loc         string
+	Title       string
+	PublishedAt time.Time
+}
+
+// Publication names the site in a Google News sitemap
+type Publication struct {
+	Name     string
+	Language string
+}
+
+type urlSet struct {
+	XMLName xml.Name     `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 urlset"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+type sitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// WriteSitemap writes urls as a sitemap, at most 50,000 of them
+func WriteSitemap(w io.Writer, urls []URL) error {
+	doc := urlSet{URLs: []sitemapURL{}}
+	for _, u := range urls {
+		doc.URLs = append(doc.URLs, sitemapURL{Loc: u.Loc, LastMod: lastMod(u.LastModified)})
+	}
+	return write(w, doc)
+}
+
+type sitemapIndex struct {
+	XMLName  xml.Name     `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 sitemapindex"`
+	Sitemaps []sitemapURL `xml:"sitemap"`
+}
+
+// WriteSitemapIndex writes an index pointing at the given sitemaps
+func WriteSitemapIndex(w io.Writer, sitemaps []URL) error {
+	doc := sitemapIndex{Sitemaps: []sitemapURL{}}
+	for _, u := range sitemaps {
+		doc.Sitemaps = append(doc.Sitemaps, sitemapURL{Loc: u.Loc, LastMod: lastMod(u.LastModified)})
+	}
+	return write(w, doc)
+}
+
+type newsURLSet struct {
+	XMLName xml.Name  `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 urlset"`
+	NewsNS  string    `xml:"xmlns:news,attr"`
+	URLs    []newsURL `xml:"url"`
+}
+
+type newsURL struct {
+	Loc  string   `xml:"loc"`
+	News newsInfo `xml:"news:news"`
+}
+
+type newsInfo struct {
+	Publication     newsPublication `xml:"news:publication"`
+	PublicationDate string          `xml:"news:publication_date"`
+	Title           string          `xml:"news:title"`
+}
+
+type newsPublication struct {
+	Name     string `xml:"news:name"`
+	Language string `xml:"news:language"`
+}
+
+// WriteNewsSitemap writes urls as a Google News sitemap, which lists the
+// articles of the last two days, at most 1,000 of them
+func WriteNewsSitemap(w io.Writer, publication Publication, urls []NewsURL) error {
+	doc := newsURLSet{
+		NewsNS: "http://www.google.com/schemas/sitemap-news/0.9",
+		URLs:   []newsURL{},
+	}
+	for _, u := range urls {
+		doc.URLs = append(doc.URLs, newsURL{
+			Loc: u.Loc,
+			News: newsInfo{
+				Publication:     newsPublication{Name: publication.Name, Language: publication.Language},
+				PublicationDate: u.PublishedAt.UTC().Format(time.RFC3339),
+				Title:           u.Title,
+			},
+		})
+	}
+	return write(w, doc)
+}
+
+func lastMod(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
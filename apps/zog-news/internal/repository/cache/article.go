@@ -0,0 +1,170 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+	"zog-news/domain"
+	"zog-news/service"
+
+	"github.com/google/uuid"
+)
+
+// ArticleRepository caches article reads of the wrapped repository and
+// invalidates them on every write going through it. Search, revisions and
+// the trash are read straight from the wrapped repository.
+type ArticleRepository struct {
+	next  service.ArticleRepository
+	cache *Cache
+}
+
+func NewArticleRepository(next service.ArticleRepository, cache *Cache) *ArticleRepository {
+	return &ArticleRepository{
+		next:  next,
+		cache: cache,
+	}
+}
+
+// articlePage is a cached page of the article list
+type articlePage struct {
+	Articles []domain.Article  `json:"articles"`
+	Meta     domain.CursorMeta `json:"meta"`
+}
+
+func (a *ArticleRepository) GetArticle(ctx context.Context, id uuid.UUID) (*domain.Article, error) {
+	gen, err := a.cache.generations(ctx, articlesGeneration)
+	if err != nil {
+		return a.next.GetArticle(ctx, id)
+	}
+
+	return load(ctx, a.cache, key("article", gen, id.String()), a.cache.config.ArticleTTL,
+		func(ctx context.Context) (*domain.Article, error) {
+			return a.next.GetArticle(ctx, id)
+		})
+}
+
+func (a *ArticleRepository) GetArticleList(ctx context.Context, filter *domain.ArticleFilter) ([]domain.Article, domain.CursorMeta, error) {
+	gen, err := a.cache.generations(ctx, articlesGeneration)
+	if err != nil {
+		return a.next.GetArticleList(ctx, filter)
+	}
+	params, err := json.Marshal(filter)
+	if err != nil {
+		return a.next.GetArticleList(ctx, filter)
+	}
+
+	page, err := load(ctx, a.cache, key("articles", gen, string(params)), a.cache.config.ArticleTTL,
+		func(ctx context.Context) (articlePage, error) {
+			articles, meta, err := a.next.GetArticleList(ctx, filter)
+			return articlePage{Articles: articles, Meta: meta}, err
+		})
+	return page.Articles, page.Meta, err
+}
+
+func (a *ArticleRepository) GetArticleIDBySlug(ctx context.Context, slug string) (uuid.UUID, error) {
+	gen, err := a.cache.generations(ctx, articlesGeneration)
+	if err != nil {
+		return a.next.GetArticleIDBySlug(ctx, slug)
+	}
+
+	return load(ctx, a.cache, key("article-slug", gen, slug), a.cache.config.ArticleTTL,
+		func(ctx context.Context) (uuid.UUID, error) {
+			return a.next.GetArticleIDBySlug(ctx, slug)
+		})
+}
+
+func (a *ArticleRepository) GetTopicsByArticleID(ctx context.Context, articleID uuid.UUID) ([]domain.Topic, error) {
+	gen, err := a.cache.generations(ctx, articlesGeneration)
+	if err != nil {
+		return a.next.GetTopicsByArticleID(ctx, articleID)
+	}
+
+	return load(ctx, a.cache, key("article-topics", gen, articleID.String()), a.cache.config.ArticleTTL,
+		func(ctx context.Context) ([]domain.Topic, error) {
+			return a.next.GetTopicsByArticleID(ctx, articleID)
+		})
+}
+
+func (a *ArticleRepository) SearchArticles(ctx context.Context, filter *domain.ArticleSearchFilter) ([]domain.ArticleSearchResult, error) {
+	return a.next.SearchArticles(ctx, filter)
+}
+
+func (a *ArticleRepository) GetArticleRevisions(ctx context.Context, articleID uuid.UUID) ([]domain.ArticleRevision, error) {
+	return a.next.GetArticleRevisions(ctx, articleID)
+}
+
+func (a *ArticleRepository) GetArticleRevision(ctx context.Context, articleID uuid.UUID, revision int) (*domain.ArticleRevision, error) {
+	return a.next.GetArticleRevision(ctx, articleID, revision)
+}
+
+func (a *ArticleRepository) GetDeletedArticles(ctx context.Context) ([]domain.Article, error) {
+	return a.next.GetDeletedArticles(ctx)
+}
+
+// CreateArticle also invalidates topics, topic names on the article create
+// the topics that don't exist yet
+func (a *ArticleRepository) CreateArticle(ctx context.Context, article *domain.Article) (*domain.Article, error) {
+	defer a.cache.invalidateTopics(ctx)
+	return a.next.CreateArticle(ctx, article)
+}
+
+// UpdateArticle also invalidates topics, see CreateArticle
+func (a *ArticleRepository) UpdateArticle(ctx context.Context, id uuid.UUID, article *domain.Article, editorID string) (*domain.Article, error) {
+	defer a.cache.invalidateTopics(ctx)
+	return a.next.UpdateArticle(ctx, id, article, editorID)
+}
+
+func (a *ArticleRepository) DeleteArticle(ctx context.Context, id uuid.UUID, version int) error {
+	defer a.cache.invalidateArticles(ctx)
+	return a.next.DeleteArticle(ctx, id, version)
+}
+
+func (a *ArticleRepository) TransitionArticle(ctx context.Context, id uuid.UUID, transition *domain.ArticleTransition) (*domain.Article, error) {
+	defer a.cache.invalidateArticles(ctx)
+	return a.next.TransitionArticle(ctx, id, transition)
+}
+
+// PublishScheduledArticles only invalidates when an article was published,
+// the scheduler calls it every interval
+func (a *ArticleRepository) PublishScheduledArticles(ctx context.Context, now time.Time) (int64, error) {
+	published, err := a.next.PublishScheduledArticles(ctx, now)
+	if published > 0 || err != nil {
+		a.cache.invalidateArticles(ctx)
+	}
+	return published, err
+}
+
+func (a *ArticleRepository) RestoreArticle(ctx context.Context, id uuid.UUID) (*domain.Article, error) {
+	defer a.cache.invalidateArticles(ctx)
+	return a.next.RestoreArticle(ctx, id)
+}
+
+func (a *ArticleRepository) PurgeArticle(ctx context.Context, id uuid.UUID) error {
+	defer a.cache.invalidateArticles(ctx)
+	return a.next.PurgeArticle(ctx, id)
+}
+
+func (a *ArticleRepository) AddTopicToArticle(ctx context.Context, articleID uuid.UUID, topicID string) error {
+	defer a.cache.invalidateArticles(ctx)
+	return a.next.AddTopicToArticle(ctx, articleID, topicID)
+}
+
+func (a *ArticleRepository) AddTopicsToArticle(ctx context.Context, articleID uuid.UUID, topicIDs []string) error {
+	defer a.cache.invalidateArticles(ctx)
+	return a.next.AddTopicsToArticle(ctx, articleID, topicIDs)
+}
+
+func (a *ArticleRepository) ReplaceArticleTopics(ctx context.Context, articleID uuid.UUID, topicIDs []string) error {
+	defer a.cache.invalidateArticles(ctx)
+	return a.next.ReplaceArticleTopics(ctx, articleID, topicIDs)
+}
+
+func (a *ArticleRepository) UpdateArticleTopics(ctx context.Context, articleID uuid.UUID, add []string, remove []string) error {
+	defer a.cache.invalidateArticles(ctx)
+	return a.next.UpdateArticleTopics(ctx, articleID, add, remove)
+}
+
+func (a *ArticleRepository) RemoveTopicFromArticle(ctx context.Context, articleID uuid.UUID, topicID string) error {
+	defer a.cache.invalidateArticles(ctx)
+	return a.next.RemoveTopicFromArticle(ctx, articleID, topicID)
+}
@@ -0,0 +1,171 @@
+// Package cache keeps article and topic reads in a Store in front of the
+// postgres repositories.
+//
+// Entries are never deleted. Their keys carry generation counters which every
+// write increments, failed ones included since a write can commit and still
+// return an error, so readers move on to a fresh key the moment a write
+// commits. A read that raced the write can only fill the key of the old
+// generation, which nobody reads anymore and which expires with its TTL.
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"math/rand/v2"
+	"strconv"
+	"time"
+	"zog-news/config"
+
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	keyPrefix = "zog:"
+
+	// articlesGeneration invalidates every cached article at once, topics are
+	// embedded in articles so topic writes increment it too
+	articlesGeneration = keyPrefix + "gen:articles"
+	// topicsGeneration invalidates every cached topic and topic list
+	topicsGeneration = keyPrefix + "gen:topics"
+
+	// ttlJitter spreads expiry by up to this fraction of the TTL so entries
+	// cached together don't all miss at the same moment
+	ttlJitter = 0.1
+)
+
+// Cache is shared by the article and topic repositories so writes to one can
+// invalidate reads of the other
+type Cache struct {
+	store  Store
+	config config.CacheConfig
+	// group lets one caller fetch a missing key while the others wait for it
+	group singleflight.Group
+}
+
+func New(store Store, config config.CacheConfig) *Cache {
+	return &Cache{
+		store:  store,
+		config: config,
+	}
+}
+
+// load returns the value cached at key, on a miss fetch runs once for all
+// concurrent callers and its result is cached for ttl. Store failures are
+// logged and fall through to fetch, a ttl of zero disables caching.
+func load[T any](ctx context.Context, c *Cache, key string, ttl time.Duration, fetch func(context.Context) (T, error)) (T, error) {
+	if ttl <= 0 {
+		return fetch(ctx)
+	}
+
+	var value T
+
+	data, err := c.store.Get(ctx, key)
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(data, &value); err == nil {
+			return value, nil
+		}
+	case !errors.Is(err, ErrMiss):
+		warn(ctx, "read", key, err)
+	}
+
+	shared, err, _ := c.group.Do(key, func() (any, error) {
+		// the other callers wait on this fetch, don't let one of them
+		// cancelling fail the rest
+		ctx := context.WithoutCancel(ctx)
+
+		fetched, err := fetch(ctx)
+		if err != nil {
+			return nil, err
+		}
+		data, err := json.Marshal(fetched)
+		if err != nil {
+			return nil, err
+		}
+		if err := c.store.Set(ctx, key, data, jitter(ttl)); err != nil {
+			warn(ctx, "write", key, err)
+		}
+		return data, nil
+	})
+	if err != nil {
+		return value, err
+	}
+
+	// every caller decodes its own copy, services modify what they read
+	err = json.Unmarshal(shared.([]byte), &value)
+	return value, err
+}
+
+// generations returns the current value of the given counters joined into a
+// key segment like 3.1. Callers skip the cache when it fails, any key they
+// built could be stale.
+func (c *Cache) generations(ctx context.Context, keys ...string) (string, error) {
+	counters, err := c.store.Counters(ctx, keys...)
+	if err != nil {
+		warn(ctx, "read", keys[0], err)
+		return "", err
+	}
+
+	var segment []byte
+	for i, counter := range counters {
+		if i > 0 {
+			segment = append(segment, '.')
+		}
+		segment = strconv.AppendInt(segment, counter, 10)
+	}
+	return string(segment), nil
+}
+
+// key joins the parts of a cache key under keyPrefix, parts callers don't
+// control are hashed so keys stay short and free of separators
+func key(kind, generations string, parts ...string) string {
+	h := sha256.New()
+	for _, part := range parts {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return keyPrefix + kind + ":" + generations + ":" + hex.EncodeToString(h.Sum(nil)[:16])
+}
+
+// invalidateArticles moves every article read to a fresh key
+func (c *Cache) invalidateArticles(ctx context.Context) {
+	c.invalidate(ctx, c.config.ArticleTTL, articlesGeneration)
+}
+
+// invalidateTopics moves every topic read to a fresh key, and every article
+// read too since articles embed their topics. Topic articles are cached for
+// the article TTL under the topics counter, so it lives as long as the longer
+// of the two.
+func (c *Cache) invalidateTopics(ctx context.Context) {
+	c.invalidate(ctx, max(c.config.TopicTTL, c.config.ArticleTTL), topicsGeneration)
+	c.invalidateArticles(ctx)
+}
+
+// invalidate increments the given counters. They outlive the entries they
+// guard by twice the TTL, so an expired counter restarting at zero can't meet
+// an entry from before its first increment.
+func (c *Cache) invalidate(ctx context.Context, ttl time.Duration, keys ...string) {
+	// the write already happened, a caller giving up must not leave readers
+	// on the old generation
+	ctx = context.WithoutCancel(ctx)
+	for _, key := range keys {
+		if err := c.store.Incr(ctx, key, 2*ttl); err != nil {
+			warn(ctx, "invalidate", key, err)
+		}
+	}
+}
+
+func jitter(ttl time.Duration) time.Duration {
+	return ttl + time.Duration(rand.Float64()*ttlJitter*float64(ttl))
+}
+
+func warn(ctx context.Context, op, key string, err error) {
+	slog.WarnContext(ctx, "cache "+op+" failed",
+		slog.String("key", key),
+		slog.String("error", err.Error()),
+	)
+}
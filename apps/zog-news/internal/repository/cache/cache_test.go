@@ -0,0 +1,124 @@
+package cache_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+	"zog-news/config"
+	"zog-news/domain"
+	"zog-news/internal/repository/cache"
+	"zog-news/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeArticleRepository counts GetArticle calls, methods it doesn't override
+// panic on the nil embedded interface
+type fakeArticleRepository struct {
+	service.ArticleRepository
+
+	calls   atomic.Int32
+	release chan struct{}
+	title   atomic.Value
+}
+
+func (f *fakeArticleRepository) GetArticle(_ context.Context, id uuid.UUID) (*domain.Article, error) {
+	f.calls.Add(1)
+	if f.release != nil {
+		<-f.release
+	}
+	return &domain.Article{ID: id.String(), Title: f.title.Load().(string)}, nil
+}
+
+func (f *fakeArticleRepository) UpdateArticle(_ context.Context, id uuid.UUID, article *domain.Article, _ string) (*domain.Article, error) {
+	f.title.Store(article.Title)
+	return &domain.Article{ID: id.String(), Title: article.Title}, nil
+}
+
+func newArticleRepository(fake *fakeArticleRepository) *cache.ArticleRepository {
+	c := cache.New(cache.NewMemoryStore(), config.CacheConfig{
+		ArticleTTL: time.Minute,
+		TopicTTL:   time.Minute,
+	})
+	return cache.NewArticleRepository(fake, c)
+}
+
+func TestArticleReadsAreCachedUntilWrite(t *testing.T) {
+	t.Parallel()
+
+	fake := &fakeArticleRepository{}
+	fake.title.Store("Original")
+	repo := newArticleRepository(fake)
+	ctx := context.Background()
+	id := uuid.New()
+
+	for range 3 {
+		article, err := repo.GetArticle(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, "Original", article.Title)
+	}
+	assert.EqualValues(t, 1, fake.calls.Load())
+
+	_, err := repo.UpdateArticle(ctx, id, &domain.Article{Title: "Updated"}, "")
+	require.NoError(t, err)
+
+	article, err := repo.GetArticle(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, "Updated", article.Title)
+	assert.EqualValues(t, 2, fake.calls.Load())
+}
+
+func TestConcurrentMissesFetchOnce(t *testing.T) {
+	t.Parallel()
+
+	fake := &fakeArticleRepository{release: make(chan struct{})}
+	fake.title.Store("Original")
+	repo := newArticleRepository(fake)
+	id := uuid.New()
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			article, err := repo.GetArticle(context.Background(), id)
+			assert.NoError(t, err)
+			assert.Equal(t, "Original", article.Title)
+		}()
+	}
+
+	// let the callers pile up behind the first fetch
+	require.Eventually(t, func() bool { return fake.calls.Load() == 1 }, time.Second, time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+	close(fake.release)
+	wg.Wait()
+
+	assert.EqualValues(t, 1, fake.calls.Load())
+}
+
+func TestMemoryStoreExpires(t *testing.T) {
+	t.Parallel()
+
+	store := cache.NewMemoryStore()
+	ctx := context.Background()
+
+	require.NoError(t, store.Set(ctx, "kept", []byte("value"), time.Minute))
+	require.NoError(t, store.Set(ctx, "expired", []byte("value"), -time.Second))
+
+	value, err := store.Get(ctx, "kept")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value"), value)
+
+	_, err = store.Get(ctx, "expired")
+	assert.ErrorIs(t, err, cache.ErrMiss)
+
+	require.NoError(t, store.Incr(ctx, "counter", time.Minute))
+	require.NoError(t, store.Incr(ctx, "counter", time.Minute))
+	counters, err := store.Counters(ctx, "counter", "missing")
+	require.NoError(t, err)
+	assert.Equal(t, []int64{2, 0}, counters)
+}
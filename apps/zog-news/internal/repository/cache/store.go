@@ -0,0 +1,169 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrMiss is returned by Store.Get when the key holds no value
+var ErrMiss = errors.New("cache miss")
+
+// Store is the key value store cached reads are kept in
+type Store interface {
+	// Get returns the value of key or ErrMiss
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Set stores value under key until ttl passes
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Counters returns the value of each counter, missing counters are zero
+	Counters(ctx context.Context, keys ...string) ([]int64, error)
+	// Incr increments the counter at key and keeps it for at least ttl
+	Incr(ctx context.Context, key string, ttl time.Duration) error
+}
+
+// ValkeyStore keeps cached reads in Valkey, so every instance of the service
+// shares them
+type ValkeyStore struct {
+	client redis.UniversalClient
+}
+
+func NewValkeyStore(client redis.UniversalClient) *ValkeyStore {
+	return &ValkeyStore{client: client}
+}
+
+func (s *ValkeyStore) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := s.client.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrMiss
+	}
+	return value, err
+}
+
+func (s *ValkeyStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return s.client.Set(ctx, key, value, ttl).Err()
+}
+
+func (s *ValkeyStore) Counters(ctx context.Context, keys ...string) ([]int64, error) {
+	values, err := s.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	counters := make([]int64, len(values))
+	for i, value := range values {
+		if value == nil {
+			continue
+		}
+		s, _ := value.(string)
+		if counters[i], err = strconv.ParseInt(s, 10, 64); err != nil {
+			return nil, err
+		}
+	}
+	return counters, nil
+}
+
+func (s *ValkeyStore) Incr(ctx context.Context, key string, ttl time.Duration) error {
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Incr(ctx, key)
+		pipe.Expire(ctx, key, ttl)
+		return nil
+	})
+	return err
+}
+
+// sweepEvery is how many writes MemoryStore takes between removing expired
+// entries
+const sweepEvery = 1024
+
+// MemoryStore keeps cached reads in process memory. It is used when no Valkey
+// is configured and in tests, invalidation only reaches the same process.
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+	writes  int
+	now     func() time.Time
+}
+
+type memoryEntry struct {
+	value   []byte
+	counter int64
+	expires time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		entries: make(map[string]memoryEntry),
+		now:     time.Now,
+	}
+}
+
+func (s *MemoryStore) Get(_ context.Context, key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.lookup(key)
+	if !ok || entry.value == nil {
+		return nil, ErrMiss
+	}
+	return entry.value, nil
+}
+
+func (s *MemoryStore) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.store(key, memoryEntry{value: value, expires: s.now().Add(ttl)})
+	return nil
+}
+
+func (s *MemoryStore) Counters(_ context.Context, keys ...string) ([]int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	counters := make([]int64, len(keys))
+	for i, key := range keys {
+		entry, _ := s.lookup(key)
+		counters[i] = entry.counter
+	}
+	return counters, nil
+}
+
+func (s *MemoryStore) Incr(_ context.Context, key string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, _ := s.lookup(key)
+	s.store(key, memoryEntry{counter: entry.counter + 1, expires: s.now().Add(ttl)})
+	return nil
+}
+
+// lookup returns the entry at key unless it expired, callers hold mu
+func (s *MemoryStore) lookup(key string) (memoryEntry, bool) {
+	entry, ok := s.entries[key]
+	if !ok || !s.now().Before(entry.expires) {
+		return memoryEntry{}, false
+	}
+	return entry, true
+}
+
+// store saves entry at key and every sweepEvery writes drops expired entries,
+// callers hold mu
+func (s *MemoryStore) store(key string, entry memoryEntry) {
+	s.entries[key] = entry
+
+	s.writes++
+	if s.writes < sweepEvery {
+		return
+	}
+	s.writes = 0
+	now := s.now()
+	for key, entry := range s.entries {
+		if !now.Before(entry.expires) {
+			delete(s.entries, key)
+		}
+	}
+}
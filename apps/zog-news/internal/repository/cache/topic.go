@@ -0,0 +1,129 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"zog-news/domain"
+	"zog-news/service"
+
+	"github.com/google/uuid"
+)
+
+// TopicRepository caches topic reads of the wrapped repository and
+// invalidates them, along with every cached article, on every write going
+// through it. The trash is read straight from the wrapped repository.
+type TopicRepository struct {
+	next  service.TopicRepository
+	cache *Cache
+}
+
+func NewTopicRepository(next service.TopicRepository, cache *Cache) *TopicRepository {
+	return &TopicRepository{
+		next:  next,
+		cache: cache,
+	}
+}
+
+func (t *TopicRepository) GetTopic(ctx context.Context, id uuid.UUID) (*domain.Topic, error) {
+	gen, err := t.cache.generations(ctx, topicsGeneration)
+	if err != nil {
+		return t.next.GetTopic(ctx, id)
+	}
+
+	return load(ctx, t.cache, key("topic", gen, id.String()), t.cache.config.TopicTTL,
+		func(ctx context.Context) (*domain.Topic, error) {
+			return t.next.GetTopic(ctx, id)
+		})
+}
+
+func (t *TopicRepository) GetTopicList(ctx context.Context, filter *domain.TopicFilter) ([]domain.Topic, error) {
+	gen, err := t.cache.generations(ctx, topicsGeneration)
+	if err != nil {
+		return t.next.GetTopicList(ctx, filter)
+	}
+	params, err := json.Marshal(filter)
+	if err != nil {
+		return t.next.GetTopicList(ctx, filter)
+	}
+
+	return load(ctx, t.cache, key("topics", gen, string(params)), t.cache.config.TopicTTL,
+		func(ctx context.Context) ([]domain.Topic, error) {
+			return t.next.GetTopicList(ctx, filter)
+		})
+}
+
+func (t *TopicRepository) GetTopicIDBySlug(ctx context.Context, slug string) (uuid.UUID, error) {
+	gen, err := t.cache.generations(ctx, topicsGeneration)
+	if err != nil {
+		return t.next.GetTopicIDBySlug(ctx, slug)
+	}
+
+	return load(ctx, t.cache, key("topic-slug", gen, slug), t.cache.config.TopicTTL,
+		func(ctx context.Context) (uuid.UUID, error) {
+			return t.next.GetTopicIDBySlug(ctx, slug)
+		})
+}
+
+func (t *TopicRepository) GetTopicChildren(ctx context.Context, id uuid.UUID) ([]domain.Topic, error) {
+	gen, err := t.cache.generations(ctx, topicsGeneration)
+	if err != nil {
+		return t.next.GetTopicChildren(ctx, id)
+	}
+
+	return load(ctx, t.cache, key("topic-children", gen, id.String()), t.cache.config.TopicTTL,
+		func(ctx context.Context) ([]domain.Topic, error) {
+			return t.next.GetTopicChildren(ctx, id)
+		})
+}
+
+// GetTopicArticles is keyed by both counters, articles move between topics
+// without any topic changing and the other way around
+func (t *TopicRepository) GetTopicArticles(ctx context.Context, id uuid.UUID, filter *domain.TopicArticlesFilter) ([]domain.Article, error) {
+	gen, err := t.cache.generations(ctx, topicsGeneration, articlesGeneration)
+	if err != nil {
+		return t.next.GetTopicArticles(ctx, id, filter)
+	}
+	params, err := json.Marshal(filter)
+	if err != nil {
+		return t.next.GetTopicArticles(ctx, id, filter)
+	}
+
+	return load(ctx, t.cache, key("topic-articles", gen, id.String(), string(params)), t.cache.config.ArticleTTL,
+		func(ctx context.Context) ([]domain.Article, error) {
+			return t.next.GetTopicArticles(ctx, id, filter)
+		})
+}
+
+func (t *TopicRepository) GetDeletedTopics(ctx context.Context) ([]domain.Topic, error) {
+	return t.next.GetDeletedTopics(ctx)
+}
+
+func (t *TopicRepository) CreateTopic(ctx context.Context, topic *domain.CreateTopicRequest) (*domain.Topic, error) {
+	defer t.cache.invalidateTopics(ctx)
+	return t.next.CreateTopic(ctx, topic)
+}
+
+func (t *TopicRepository) UpdateTopic(ctx context.Context, id uuid.UUID, topic *domain.Topic) (*domain.Topic, error) {
+	defer t.cache.invalidateTopics(ctx)
+	return t.next.UpdateTopic(ctx, id, topic)
+}
+
+func (t *TopicRepository) DeleteTopic(ctx context.Context, id uuid.UUID, version int) error {
+	defer t.cache.invalidateTopics(ctx)
+	return t.next.DeleteTopic(ctx, id, version)
+}
+
+func (t *TopicRepository) MergeTopics(ctx context.Context, targetID uuid.UUID, sourceIDs []string) (*domain.Topic, error) {
+	defer t.cache.invalidateTopics(ctx)
+	return t.next.MergeTopics(ctx, targetID, sourceIDs)
+}
+
+func (t *TopicRepository) RestoreTopic(ctx context.Context, id uuid.UUID) (*domain.Topic, error) {
+	defer t.cache.invalidateTopics(ctx)
+	return t.next.RestoreTopic(ctx, id)
+}
+
+func (t *TopicRepository) PurgeTopic(ctx context.Context, id uuid.UUID) error {
+	defer t.cache.invalidateTopics(ctx)
+	return t.next.PurgeTopic(ctx, id)
+}
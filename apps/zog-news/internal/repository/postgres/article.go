@@ -3,11 +3,16 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"strings"
+	"time"
+	"unicode"
 	"zog-news/domain"
+	"zog-news/render"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
@@ -23,92 +28,243 @@ func NewArticleRepository(conn *pgxpool.Pool) *ArticleRepository {
 	}
 }
 
-func (a *ArticleRepository) CreateArticle(ctx context.Context, article *domain.CreateArticleRequest) (*domain.Article, error) {
+func (a *ArticleRepository) CreateArticle(ctx context.Context, article *domain.Article) (*domain.Article, error) {
 
 	query := `
-		INSERT INTO articles (title, content, author, status, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, NOW(), NOW())
+		INSERT INTO articles (
+			title, slug, content, content_format, content_html, excerpt, reading_time,
+			cover_media_id, author_id, status, created_at, updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NOW(), NOW())
 		RETURNING id`
 
 	var id uuid.UUID
-	var err = a.Conn.QueryRow(ctx, query, article.Title, article.Content, article.Author, article.Status).Scan(&id)
+	err := pgx.BeginFunc(ctx, a.Conn, func(tx pgx.Tx) error {
+		slug, err := articleSlugs.unique(ctx, tx, article.Title, nil)
+		if err != nil {
+			return err
+		}
+		if err := checkCoverMedia(ctx, tx, article.CoverMediaID); err != nil {
+			return err
+		}
+		err = tx.QueryRow(ctx, query,
+			article.Title,
+			slug,
+			article.Content,
+			article.ContentFormat,
+			article.ContentHTML,
+			article.Excerpt,
+			article.ReadingTimeMinutes,
+			article.CoverMediaID,
+			article.AuthorID,
+			article.Status,
+		).Scan(&id)
+		if err != nil {
+			return err
+		}
+		if err := insertArticleRevision(ctx, tx, id, article, article.AuthorID); err != nil {
+			return err
+		}
+		if err := insertArticleEvents(ctx, tx, domain.EventArticleCreated, id); err != nil {
+			return err
+		}
+		if err := setArticleMedia(ctx, tx, id, article.AttachmentIDs); err != nil {
+			return err
+		}
+		return setArticleTopics(ctx, tx, id, article)
+	})
 	if err != nil {
-		return nil, err
+		return nil, dbError(err)
 	}
 
-	return &domain.Article{
-		ID:    id.String(),
-		Title:  article.Title,
-		Author: article.Author,
-		Content:  article.Content,
-		Status: article.Status,
-	}, nil
+	return a.GetArticle(ctx, id)
 }
 
-func (a *ArticleRepository) GetArticleList(ctx context.Context, filter *domain.ArticleFilter) ([]domain.Article, error) {
-    // TODO: is this good??
+// insertArticleRevision snapshots the article as its next revision. Callers
+// update the article row first in the same transaction, the row lock keeps
+// concurrent updates from taking the same revision number.
+func insertArticleRevision(ctx context.Context, tx pgx.Tx, articleID uuid.UUID, article *domain.Article, editorID string) error {
 	query := `
-		SELECT
-            a.id,
-            a.title,
-            a.content,
-            a.author,
-            a.status,
-            a.created_at,
-            a.updated_at,
-            t.id AS topic_id,
-            t.name AS topic_name,
-            t.created_at AS topic_created_at,
-            t.updated_at AS topic_updated_at
-		FROM articles a
-        LEFT JOIN article_topics at ON a.id = at.article_id
-        LEFT JOIN topics t ON at.topic_id = t.id
-        WHERE a.deleted_at is NULL`
+		INSERT INTO article_revisions (article_id, revision, title, content, content_format, editor_id)
+		SELECT $1, COALESCE(MAX(revision), 0) + 1, $2, $3, $4, NULLIF($5, '')::uuid
+		FROM article_revisions
+		WHERE article_id = $1`
+
+	_, err := tx.Exec(ctx, query, articleID, article.Title, article.Content, article.ContentFormat, editorID)
+	return err
+}
+
+// articleSortColumns whitelists the columns an article list may be ordered by
+var articleSortColumns = map[domain.ArticleSort]string{
+    domain.SortCreatedAt: "a.created_at",
+    domain.SortUpdatedAt: "a.updated_at",
+    // the cursor of an unpublished article holds its creation time
+    domain.SortPublishedAt: "COALESCE(a.published_at, a.created_at)",
+    domain.SortTitle:     "a.title",
+}
 
-	var args []interface{}
-	var conditions []string
+func (a *ArticleRepository) GetArticleList(ctx context.Context, filter *domain.ArticleFilter) ([]domain.Article, domain.CursorMeta, error) {
+    var meta domain.CursorMeta
+    if filter == nil {
+        filter = &domain.ArticleFilter{}
+    }
+    if err := filter.Normalize(); err != nil {
+        return nil, meta, err
+    }
+
+    var args []interface{}
+    var conditions []string
     var argIndex int = 1
 
-	if filter != nil {
-        if filter.Search != "" {
-            condition := fmt.Sprintf(
-                `(a.title ILIKE $%d OR u.content ILIKE $%d)`,
-                argIndex,
-                argIndex,
+    if filter.Search != "" {
+        websearch, prefixes := splitSearchQuery(filter.Search)
+        condition := fmt.Sprintf(
+            `(a.search_vector @@ (websearch_to_tsquery('simple', $%d) && to_tsquery('simple', $%d)))`,
+            argIndex,
+            argIndex+1,
+        )
+        conditions = append(conditions, condition)
+        args = append(args, websearch, prefixes)
+        argIndex += 2
+    }
+    if filter.Status != "" {
+        condition := fmt.Sprintf(`(a.status = $%d)`, argIndex)
+        conditions = append(conditions, condition)
+        args = append(args, filter.Status)
+        argIndex++
+    }
+    if filter.Topic != "" {
+        // filter in a subquery so the article keeps all of its topics
+        condition := fmt.Sprintf(`EXISTS (
+            SELECT 1 FROM article_topics ft
+            JOIN topics t ON ft.topic_id = t.id
+            WHERE ft.article_id = a.id AND (t.name = $%[1]d OR t.slug = $%[1]d) AND t.deleted_at IS NULL
+        )`, argIndex)
+        conditions = append(conditions, condition)
+        args = append(args, filter.Topic)
+        argIndex++
+    }
+    if filter.TopicID != "" {
+        // the subtree doesn't depend on the article, so it is walked once
+        condition := fmt.Sprintf(`a.id IN (
+            WITH RECURSIVE subtree AS (
+                SELECT id FROM topics WHERE id = $%d
+                UNION
+                SELECT t.id FROM topics t
+                JOIN subtree s ON t.parent_id = s.id
+                WHERE $%d::boolean AND t.deleted_at IS NULL
             )
-            conditions = append(conditions, condition)
-            args = append(args, "%"+filter.Search+"%")
-            argIndex++
+            SELECT ft.article_id FROM article_topics ft
+            WHERE ft.topic_id IN (SELECT id FROM subtree)
+        )`, argIndex, argIndex+1)
+        conditions = append(conditions, condition)
+        args = append(args, filter.TopicID, filter.IncludeDescendants)
+        argIndex += 2
+    }
+    if condition, scopeArgs := scopeCondition(filter.Scope, argIndex); condition != "" {
+        conditions = append(conditions, condition)
+        args = append(args, scopeArgs...)
+        argIndex += len(scopeArgs)
+    }
+
+    column := articleSortColumns[filter.Sort]
+    direction, comparator := "DESC", "<"
+    if filter.Order == domain.OrderAsc {
+        direction, comparator = "ASC", ">"
+    }
+
+    // keyset pagination, the id breaks ties between equal sort values
+    if filter.Cursor != "" {
+        cursor, err := domain.DecodeArticleCursor(filter.Cursor)
+        if err != nil {
+            return nil, meta, err
+        }
+        if cursor.Sort != filter.Sort {
+            return nil, meta, fmt.Errorf("%w: cursor does not match sort %q", domain.ErrBadParamInput, filter.Sort)
         }
-        if filter.Status != "" {
-            condition := fmt.Sprintf(`(a.status = $%d)`, argIndex)
-            conditions = append(conditions, condition)
-            args = append(args, filter.Status)
-            argIndex++
+        cursorID, err := uuid.Parse(cursor.ID)
+        if err != nil {
+            return nil, meta, fmt.Errorf("%w: malformed cursor", domain.ErrBadParamInput)
         }
-        if filter.Topic != "" {
-            condition := fmt.Sprintf(`(t.name = $%d)`, argIndex)
-            conditions = append(conditions, condition)
-            args = append(args, filter.Topic)
-            argIndex++
+
+        var value interface{} = cursor.Value
+        if filter.Sort != domain.SortTitle {
+            if value, err = cursor.TimeValue(); err != nil {
+                return nil, meta, err
+            }
         }
+
+        condition := fmt.Sprintf(`((%s, a.id) %s ($%d, $%d))`, column, comparator, argIndex, argIndex+1)
+        conditions = append(conditions, condition)
+        args = append(args, value, cursorID)
+        argIndex += 2
     }
 
+    where := "a.deleted_at IS NULL"
     if len(conditions) > 0 {
-        query += " AND" + strings.Join(conditions, " AND ")
+        where += " AND " + strings.Join(conditions, " AND ")
     }
 
-    // distinct changed the order of results, so we need to order by created_at
-    query += " ORDER BY a.created_at DESC"
+    // one extra row tells whether there is a next page
+    args = append(args, filter.Limit+1)
+
+    // paginate the articles first, joining topics afterwards keeps the limit
+    // counting articles instead of article-topic rows
+    query := fmt.Sprintf(`
+        WITH page AS (
+            SELECT
+                a.id, a.title, a.slug, a.content, a.content_format, a.content_html, a.excerpt, a.reading_time, a.cover_media_id,
+                a.status, a.published_at, a.scheduled_at, a.version, a.created_at, a.updated_at,
+                COALESCE(a.author_id::text, '') AS author_id,
+                COALESCE(u.name, '') AS author
+            FROM articles a
+            LEFT JOIN users u ON a.author_id = u.id
+            WHERE %s
+            ORDER BY %s %s, a.id %s
+            LIMIT $%d
+        )
+        SELECT
+            a.id,
+            a.title,
+            a.slug,
+            a.content,
+            a.content_format,
+            a.content_html,
+            a.excerpt,
+            a.reading_time,
+            a.cover_media_id,
+            a.author_id,
+            a.author,
+            a.status,
+            a.published_at,
+            a.scheduled_at,
+            a.version,
+            a.created_at,
+            a.updated_at,
+            t.id AS topic_id,
+            t.name AS topic_name,
+            t.created_at AS topic_created_at,
+            t.updated_at AS topic_updated_at
+        FROM page a
+        LEFT JOIN article_topics at ON a.id = at.article_id
+        LEFT JOIN topics t ON at.topic_id = t.id AND t.deleted_at IS NULL
+        ORDER BY %s %s, a.id %s`,
+        where,
+        column, direction, direction,
+        argIndex,
+        column, direction, direction,
+    )
 
     rows, err := a.Conn.Query(ctx, query, args...)
     if err != nil {
-        return nil, err
+        return nil, meta, dbError(err)
     }
     defer rows.Close()
 
-    // must collapse the topics into their own article
-    articlesMap := make(map[string]domain.Article)
+    // must collapse the topics into their own article, the index keeps the
+    // order returned by the database
+    var articles []domain.Article
+    articleIndex := make(map[string]int)
 
     for rows.Next() {
         // cant use topic struct here because their fields might be null
@@ -119,9 +275,19 @@ func (a *ArticleRepository) GetArticleList(ctx context.Context, filter *domain.A
         err := rows.Scan(
             &article.ID,
             &article.Title,
+            &article.Slug,
             &article.Content,
+            &article.ContentFormat,
+            &article.ContentHTML,
+            &article.Excerpt,
+            &article.ReadingTimeMinutes,
+            &article.CoverMediaID,
+            &article.AuthorID,
             &article.Author,
             &article.Status,
+            &article.PublishedAt,
+            &article.ScheduledAt,
+            &article.Version,
             &article.CreatedAt,
             &article.UpdatedAt,
             &topicID,
@@ -130,15 +296,16 @@ func (a *ArticleRepository) GetArticleList(ctx context.Context, filter *domain.A
             &topicUpdatedAt,
         )
         if err != nil {
-            return nil, err
+            return nil, meta, err
         }
 
-        // store article if not exists, else update
-        existingArticle, exists := articlesMap[article.ID]
+        // store article if not exists
+        index, exists := articleIndex[article.ID]
         if !exists {
             article.Topics = []domain.Topic{}
-            articlesMap[article.ID] = article
-            existingArticle = article
+            articles = append(articles, article)
+            index = len(articles) - 1
+            articleIndex[article.ID] = index
         }
 
         // add topic to article if not null
@@ -149,17 +316,23 @@ func (a *ArticleRepository) GetArticleList(ctx context.Context, filter *domain.A
                 CreatedAt: topicCreatedAt.Time,
                 UpdatedAt: topicUpdatedAt.Time,
             }
-            existingArticle.Topics = append(existingArticle.Topics, topic)
-            articlesMap[article.ID] = existingArticle
+            articles[index].Topics = append(articles[index].Topics, topic)
         }
     }
+    if err := rows.Err(); err != nil {
+        return nil, meta, dbError(err)
+    }
 
-    var articles []domain.Article
-    for _, article := range articlesMap {
-        articles = append(articles, article)
+    if len(articles) > filter.Limit {
+        articles = articles[:filter.Limit]
+        meta.HasMore = true
+    }
+    if meta.HasMore {
+        last := articles[len(articles)-1]
+        meta.NextCursor = domain.NewArticleCursor(&last, filter.Sort).Encode()
     }
 
-    return articles, nil
+    return articles, meta, nil
 }
 
 func (a *ArticleRepository) GetArticle(ctx context.Context, id uuid.UUID) (*domain.Article, error) {
@@ -172,9 +345,19 @@ func (a *ArticleRepository) GetArticle(ctx context.Context, id uuid.UUID) (*doma
 		SELECT
             a.id,
             a.title,
+            a.slug,
             a.content,
-            a.author,
+            a.content_format,
+            a.content_html,
+            a.excerpt,
+            a.reading_time,
+            a.cover_media_id,
+            COALESCE(a.author_id::text, '') AS author_id,
+            COALESCE(u.name, '') AS author,
             a.status,
+            a.published_at,
+            a.scheduled_at,
+            a.version,
             a.created_at,
             a.updated_at,
             t.id AS topic_id,
@@ -182,15 +365,16 @@ func (a *ArticleRepository) GetArticle(ctx context.Context, id uuid.UUID) (*doma
             t.created_at AS topic_created_at,
             t.updated_at AS topic_updated_at
 		FROM articles a
+        LEFT JOIN users u ON a.author_id = u.id
         LEFT JOIN article_topics at ON a.id = at.article_id
-        LEFT JOIN topics t ON at.topic_id = t.id
+        LEFT JOIN topics t ON at.topic_id = t.id AND t.deleted_at IS NULL
         WHERE a.id = $1 AND a.deleted_at IS NULL`
 
     span.SetAttributes(attribute.String("query.statement", query))
     span.SetAttributes(attribute.String("query.parameter", id.String()))
     rows, err := a.Conn.Query(ctx, query, id)
     if err != nil {
-        return nil, err
+        return nil, dbError(err)
     }
     defer rows.Close()
 
@@ -205,9 +389,19 @@ func (a *ArticleRepository) GetArticle(ctx context.Context, id uuid.UUID) (*doma
         err := rows.Scan(
             &article.ID,
             &article.Title,
+            &article.Slug,
             &article.Content,
+            &article.ContentFormat,
+            &article.ContentHTML,
+            &article.Excerpt,
+            &article.ReadingTimeMinutes,
+            &article.CoverMediaID,
+            &article.AuthorID,
             &article.Author,
             &article.Status,
+            &article.PublishedAt,
+            &article.ScheduledAt,
+            &article.Version,
             &article.CreatedAt,
             &article.UpdatedAt,
             &topicID,
@@ -231,6 +425,19 @@ func (a *ArticleRepository) GetArticle(ctx context.Context, id uuid.UUID) (*doma
             article.Topics = append(article.Topics, topic)
         }
     }
+    if err := rows.Err(); err != nil {
+        span.RecordError(err)
+        return nil, dbError(err)
+    }
+    if article.ID == "" {
+        return nil, domain.ErrArticleNotFound
+    }
+
+    article.Attachments, err = getArticleMedia(ctx, a.Conn, id)
+    if err != nil {
+        span.RecordError(err)
+        return nil, err
+    }
 
     // TODO: should topics be fetched here?
     // topics, err := a.GetTopicsByArticleID(ctx, id)
@@ -243,19 +450,81 @@ func (a *ArticleRepository) GetArticle(ctx context.Context, id uuid.UUID) (*doma
     return &article, nil
 }
 
-func (a *ArticleRepository) UpdateArticle(ctx context.Context, id uuid.UUID, article *domain.Article) (*domain.Article, error) {
+// GetArticleIDBySlug resolves a current or previous slug to the live article
+// using it
+func (a *ArticleRepository) GetArticleIDBySlug(ctx context.Context, slug string) (uuid.UUID, error) {
+    id, err := articleSlugs.resolve(ctx, a.Conn, slug)
+    if errors.Is(err, pgx.ErrNoRows) {
+        return uuid.Nil, domain.ErrArticleNotFound
+    }
+    return id, err
+}
+
+// UpdateArticle overwrites the title and content of an article and records the
+// change as a new revision made by editorID. A new title moves the article to
+// a new slug and keeps the old one as a redirect. The update only applies
+// while the article is still at article.Version.
+func (a *ArticleRepository) UpdateArticle(ctx context.Context, id uuid.UUID, article *domain.Article, editorID string) (*domain.Article, error) {
     query := `
     UPDATE articles
     SET title = $1,
-    content = $2,
-    author = $3,
-    status = $4,
+    slug = $2,
+    content = $3,
+    content_format = $4,
+    content_html = $5,
+    excerpt = $6,
+    reading_time = $7,
+    cover_media_id = $8,
+    version = version + 1,
     updated_at = NOW()
-    WHERE id = $5 AND deleted_at IS NULL`
+    WHERE id = $9 AND version = $10 AND deleted_at IS NULL`
 
-    _, err := a.Conn.Exec(ctx, query, article.Title, article.Content, article.Author, article.Status, id)
+    err := pgx.BeginFunc(ctx, a.Conn, func(tx pgx.Tx) error {
+        slug, err := articleSlugs.rename(ctx, tx, id, article.Title)
+        if err != nil {
+            return err
+        }
+        if err := checkCoverMedia(ctx, tx, article.CoverMediaID); err != nil {
+            return err
+        }
+        tag, err := tx.Exec(ctx, query,
+            article.Title,
+            slug,
+            article.Content,
+            article.ContentFormat,
+            article.ContentHTML,
+            article.Excerpt,
+            article.ReadingTimeMinutes,
+            article.CoverMediaID,
+            id,
+            article.Version,
+        )
+        if err != nil {
+            return err
+        }
+        if tag.RowsAffected() == 0 {
+            return domain.ErrPreconditionFailed
+        }
+        if err := insertArticleRevision(ctx, tx, id, article, editorID); err != nil {
+            return err
+        }
+        if err := insertArticleEvents(ctx, tx, domain.EventArticleUpdated, id); err != nil {
+            return err
+        }
+
+        // attachments and topics are only replaced when the request sent them
+        if article.AttachmentIDs != nil {
+            if err := setArticleMedia(ctx, tx, id, article.AttachmentIDs); err != nil {
+                return err
+            }
+        }
+        if article.TopicIDs == nil && article.TopicNames == nil {
+            return nil
+        }
+        return setArticleTopics(ctx, tx, id, article)
+    })
     if err != nil {
-        return nil, err
+        return nil, dbError(err)
     }
 
     updatedArticle, err := a.GetArticle(ctx, id)
@@ -265,30 +534,399 @@ func (a *ArticleRepository) UpdateArticle(ctx context.Context, id uuid.UUID, art
     return updatedArticle, nil
 }
 
-func (a *ArticleRepository) DeleteArticle(ctx context.Context, id uuid.UUID) error {
+// GetArticleRevisions lists the revisions of an article, newest first
+func (a *ArticleRepository) GetArticleRevisions(ctx context.Context, articleID uuid.UUID) ([]domain.ArticleRevision, error) {
+    tracer := otel.Tracer("repo.article")
+    ctx, span := tracer.Start(ctx, "ArticleRepository.GetArticleRevisions")
+    defer span.End()
+
+    query := articleRevisionSelect + `
+    WHERE r.article_id = $1
+    ORDER BY r.revision DESC`
+
+    rows, err := a.Conn.Query(ctx, query, articleID)
+    if err != nil {
+        span.RecordError(err)
+        return nil, err
+    }
+    defer rows.Close()
+
+    var revisions []domain.ArticleRevision
+    for rows.Next() {
+        revision, err := scanArticleRevision(rows)
+        if err != nil {
+            span.RecordError(err)
+            return nil, err
+        }
+        revisions = append(revisions, *revision)
+    }
+
+    return revisions, rows.Err()
+}
+
+// GetArticleRevision fetches a single revision, zero means the latest one
+func (a *ArticleRepository) GetArticleRevision(ctx context.Context, articleID uuid.UUID, revision int) (*domain.ArticleRevision, error) {
+    tracer := otel.Tracer("repo.article")
+    ctx, span := tracer.Start(ctx, "ArticleRepository.GetArticleRevision")
+    defer span.End()
+
+    query := articleRevisionSelect + `
+    WHERE r.article_id = $1 AND ($2 = 0 OR r.revision = $2)
+    ORDER BY r.revision DESC
+    LIMIT 1`
+
+    row := a.Conn.QueryRow(ctx, query, articleID, revision)
+    result, err := scanArticleRevision(row)
+    if errors.Is(err, pgx.ErrNoRows) {
+        return nil, domain.ErrRevisionNotFound
+    }
+    if err != nil {
+        span.RecordError(err)
+        return nil, err
+    }
+    return result, nil
+}
+
+const articleRevisionSelect = `
+    SELECT
+        r.id,
+        r.article_id,
+        r.revision,
+        r.title,
+        r.content,
+        r.content_format,
+        COALESCE(r.editor_id::text, ''),
+        COALESCE(u.name, ''),
+        r.created_at
+    FROM article_revisions r
+    LEFT JOIN users u ON r.editor_id = u.id`
+
+func scanArticleRevision(row pgx.Row) (*domain.ArticleRevision, error) {
+    var revision domain.ArticleRevision
+    err := row.Scan(
+        &revision.ID,
+        &revision.ArticleID,
+        &revision.Revision,
+        &revision.Title,
+        &revision.Content,
+        &revision.ContentFormat,
+        &revision.EditorID,
+        &revision.Editor,
+        &revision.CreatedAt,
+    )
+    if err != nil {
+        return nil, err
+    }
+    return &revision, nil
+}
+
+// TransitionArticle moves an article to a new status. The update only applies
+// while the article still has the expected status, so two editors acting at
+// the same time can't both win.
+func (a *ArticleRepository) TransitionArticle(
+    ctx context.Context,
+    id uuid.UUID,
+    transition *domain.ArticleTransition,
+) (*domain.Article, error) {
+    tracer := otel.Tracer("repo.article")
+    ctx, span := tracer.Start(ctx, "ArticleRepository.TransitionArticle")
+    defer span.End()
+
     query := `
     UPDATE articles
-    SET deleted_at = NOW()
-    WHERE id = $1 AND deleted_at IS NULL`
+    SET status = $1,
+    published_at = COALESCE($2, published_at),
+    scheduled_at = $3,
+    version = version + 1,
+    updated_at = NOW()
+    WHERE id = $4 AND status = $5 AND deleted_at IS NULL`
+
+    err := pgx.BeginFunc(ctx, a.Conn, func(tx pgx.Tx) error {
+        tag, err := tx.Exec(ctx, query,
+            transition.To,
+            transition.PublishedAt,
+            transition.ScheduledAt,
+            id,
+            transition.From,
+        )
+        if err != nil {
+            return err
+        }
+        if tag.RowsAffected() == 0 {
+            return domain.ErrInvalidTransition
+        }
+
+        if err := insertArticleEvents(ctx, tx, transition.EventType(), id); err != nil {
+            return err
+        }
+
+        // the emails are only queued when the transition commits
+        switch {
+        case transition.From == domain.StatusDraft && transition.To == domain.StatusInReview:
+            return enqueueArticleSubmittedEmails(ctx, tx, id)
+        case transition.To == domain.StatusPublished:
+            return enqueueArticlePublishedEmails(ctx, tx, []uuid.UUID{id})
+        }
+        return nil
+    })
+    if err != nil {
+        span.RecordError(err)
+        return nil, dbError(err)
+    }
+
+    return a.GetArticle(ctx, id)
+}
+
+// PublishScheduledArticles publishes every reviewed article whose scheduled
+// time has passed and returns how many were published.
+func (a *ArticleRepository) PublishScheduledArticles(ctx context.Context, now time.Time) (int64, error) {
+    tracer := otel.Tracer("repo.article")
+    ctx, span := tracer.Start(ctx, "ArticleRepository.PublishScheduledArticles")
+    defer span.End()
+
+    query := `
+    UPDATE articles
+    SET status = 'published',
+    published_at = scheduled_at,
+    scheduled_at = NULL,
+    version = version + 1,
+    updated_at = NOW()
+    WHERE status = 'in_review'
+    AND scheduled_at IS NOT NULL
+    AND scheduled_at <= $1
+    AND deleted_at IS NULL
+    RETURNING id`
+
+    var published []uuid.UUID
+    err := pgx.BeginFunc(ctx, a.Conn, func(tx pgx.Tx) error {
+        rows, err := tx.Query(ctx, query, now)
+        if err != nil {
+            return err
+        }
+        published, err = pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+        if err != nil || len(published) == 0 {
+            return err
+        }
+        if err := insertArticleEvents(ctx, tx, domain.EventArticlePublished, published...); err != nil {
+            return err
+        }
+        return enqueueArticlePublishedEmails(ctx, tx, published)
+    })
+    if err != nil {
+        span.RecordError(err)
+        return 0, err
+    }
+    return int64(len(published)), nil
+}
+
+// DeleteArticle soft deletes an article while it is still at version
+func (a *ArticleRepository) DeleteArticle(ctx context.Context, id uuid.UUID, version int) error {
+    query := `
+    UPDATE articles
+    SET deleted_at = NOW(),
+    version = version + 1
+    WHERE id = $1 AND version = $2 AND deleted_at IS NULL`
 
-    _, err := a.Conn.Exec(ctx, query, id)
-    return err
+    err := pgx.BeginFunc(ctx, a.Conn, func(tx pgx.Tx) error {
+        tag, err := tx.Exec(ctx, query, id, version)
+        if err != nil {
+            return err
+        }
+        if tag.RowsAffected() == 0 {
+            return domain.ErrPreconditionFailed
+        }
+        return insertArticleEvents(ctx, tx, domain.EventArticleDeleted, id)
+    })
+    return dbError(err)
 }
 
+// GetDeletedArticles lists the articles in the trash, most recently deleted first
+func (a *ArticleRepository) GetDeletedArticles(ctx context.Context) ([]domain.Article, error) {
+    tracer := otel.Tracer("repo.article")
+    ctx, span := tracer.Start(ctx, "ArticleRepository.GetDeletedArticles")
+    defer span.End()
+
+    query := `
+    SELECT
+        a.id,
+        a.title,
+        a.slug,
+        a.content,
+        a.content_format,
+        a.excerpt,
+        a.reading_time,
+        a.cover_media_id,
+        COALESCE(a.author_id::text, ''),
+        COALESCE(u.name, ''),
+        a.status,
+        a.published_at,
+        a.scheduled_at,
+        a.version,
+        a.deleted_at,
+        a.created_at,
+        a.updated_at
+    FROM articles a
+    LEFT JOIN users u ON a.author_id = u.id
+    WHERE a.deleted_at IS NOT NULL
+    ORDER BY a.deleted_at DESC, a.id`
+
+    rows, err := a.Conn.Query(ctx, query)
+    if err != nil {
+        span.RecordError(err)
+        return nil, err
+    }
+    defer rows.Close()
+
+    var articles []domain.Article
+    for rows.Next() {
+        var article domain.Article
+        if err := rows.Scan(
+            &article.ID,
+            &article.Title,
+            &article.Slug,
+            &article.Content,
+            &article.ContentFormat,
+            &article.Excerpt,
+            &article.ReadingTimeMinutes,
+            &article.CoverMediaID,
+            &article.AuthorID,
+            &article.Author,
+            &article.Status,
+            &article.PublishedAt,
+            &article.ScheduledAt,
+            &article.Version,
+            &article.DeletedAt,
+            &article.CreatedAt,
+            &article.UpdatedAt,
+        ); err != nil {
+            span.RecordError(err)
+            return nil, err
+        }
+        articles = append(articles, article)
+    }
+
+    return articles, rows.Err()
+}
+
+// RestoreArticle takes an article out of the trash
+func (a *ArticleRepository) RestoreArticle(ctx context.Context, id uuid.UUID) (*domain.Article, error) {
+    query := `
+    UPDATE articles
+    SET deleted_at = NULL,
+    version = version + 1,
+    updated_at = NOW()
+    WHERE id = $1 AND deleted_at IS NOT NULL`
+
+    err := pgx.BeginFunc(ctx, a.Conn, func(tx pgx.Tx) error {
+        tag, err := tx.Exec(ctx, query, id)
+        if err != nil {
+            return err
+        }
+        if tag.RowsAffected() == 0 {
+            return domain.ErrArticleNotFound
+        }
+        return insertArticleEvents(ctx, tx, domain.EventArticleRestored, id)
+    })
+    if err != nil {
+        return nil, dbError(err)
+    }
+
+    return a.GetArticle(ctx, id)
+}
+
+// PurgeArticle permanently removes an article in the trash with its topic
+// links, revisions go with it through the foreign key
+func (a *ArticleRepository) PurgeArticle(ctx context.Context, id uuid.UUID) error {
+    return pgx.BeginFunc(ctx, a.Conn, func(tx pgx.Tx) error {
+        query := `
+        SELECT id FROM articles
+        WHERE id = $1 AND deleted_at IS NOT NULL
+        FOR UPDATE`
+        if err := tx.QueryRow(ctx, query, id).Scan(&id); err != nil {
+            if errors.Is(err, pgx.ErrNoRows) {
+                return domain.ErrArticleNotFound
+            }
+            return err
+        }
+        // the event keeps the last snapshot of the row
+        if err := insertArticleEvents(ctx, tx, domain.EventArticlePurged, id); err != nil {
+            return err
+        }
+
+        query = `
+        DELETE FROM article_topics
+        WHERE article_id = $1`
+        if _, err := tx.Exec(ctx, query, id); err != nil {
+            return err
+        }
+
+        query = `
+        DELETE FROM articles
+        WHERE id = $1`
+        _, err := tx.Exec(ctx, query, id)
+        return err
+    })
+}
+
+// PurgeDeletedArticles permanently removes every article deleted before the
+// given time and returns how many were removed
+func (a *ArticleRepository) PurgeDeletedArticles(ctx context.Context, before time.Time) (int64, error) {
+    var purged int64
+    err := pgx.BeginFunc(ctx, a.Conn, func(tx pgx.Tx) error {
+        query := `
+        SELECT id FROM articles
+        WHERE deleted_at < $1
+        FOR UPDATE`
+        rows, err := tx.Query(ctx, query, before)
+        if err != nil {
+            return err
+        }
+        ids, err := pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+        if err != nil || len(ids) == 0 {
+            return err
+        }
+        if err := insertArticleEvents(ctx, tx, domain.EventArticlePurged, ids...); err != nil {
+            return err
+        }
+
+        query = `
+        DELETE FROM article_topics
+        WHERE article_id = ANY($1)`
+        if _, err := tx.Exec(ctx, query, ids); err != nil {
+            return err
+        }
+
+        query = `
+        DELETE FROM articles
+        WHERE id = ANY($1)`
+        tag, err := tx.Exec(ctx, query, ids)
+        if err != nil {
+            return err
+        }
+        purged = tag.RowsAffected()
+        return nil
+    })
+    return purged, err
+}
+
+// AddTopicToArticle links a topic to an article, a topic already linked is
+// left as is. The topic must exist and not be deleted.
 func (a *ArticleRepository) AddTopicToArticle(
     ctx context.Context,
     articleID uuid.UUID,
     topicID string,
 ) error {
-    // Do nothing on conflict?
-    query := `
-    INSERT INTO article_topics (article_id, topic_id)
-    VALUES ($1, $2)`
-
-    _, err := a.Conn.Exec(ctx, query, articleID, topicID)
-    return err
+    err := pgx.BeginFunc(ctx, a.Conn, func(tx pgx.Tx) error {
+        if err := touchArticle(ctx, tx, articleID); err != nil {
+            return err
+        }
+        return insertArticleTopics(ctx, tx, articleID, []string{topicID})
+    })
+    return dbError(err)
 }
 
+// AddTopicsToArticle links several topics at once, topics already linked are
+// skipped. Every topic must exist and not be deleted.
 func (a *ArticleRepository) AddTopicsToArticle(
     ctx context.Context,
     articleID uuid.UUID,
@@ -298,14 +936,199 @@ func (a *ArticleRepository) AddTopicsToArticle(
         return nil // No topics to add
     }
 
-    // https://www.w3resource.com/PostgreSQL/postgresql_unnest-function.php
-    // Do nothing on conflict?
+    err := pgx.BeginFunc(ctx, a.Conn, func(tx pgx.Tx) error {
+        if err := touchArticle(ctx, tx, articleID); err != nil {
+            return err
+        }
+        return insertArticleTopics(ctx, tx, articleID, topicIDs)
+    })
+    return dbError(err)
+}
+
+// ReplaceArticleTopics swaps the whole topic set of an article in one
+// transaction
+func (a *ArticleRepository) ReplaceArticleTopics(
+    ctx context.Context,
+    articleID uuid.UUID,
+    topicIDs []string,
+) error {
+    err := pgx.BeginFunc(ctx, a.Conn, func(tx pgx.Tx) error {
+        if err := touchArticle(ctx, tx, articleID); err != nil {
+            return err
+        }
+        return replaceArticleTopics(ctx, tx, articleID, topicIDs)
+    })
+    return dbError(err)
+}
+
+// UpdateArticleTopics adds and removes topics of an article in one transaction
+func (a *ArticleRepository) UpdateArticleTopics(
+    ctx context.Context,
+    articleID uuid.UUID,
+    add []string,
+    remove []string,
+) error {
+    err := pgx.BeginFunc(ctx, a.Conn, func(tx pgx.Tx) error {
+        if err := touchArticle(ctx, tx, articleID); err != nil {
+            return err
+        }
+        if len(remove) > 0 {
+            query := `
+            DELETE FROM article_topics
+            WHERE article_id = $1 AND topic_id = ANY($2::uuid[])
+            RETURNING topic_id::text`
+            if err := deleteArticleTopics(ctx, tx, articleID, query, articleID, remove); err != nil {
+                return err
+            }
+        }
+        return insertArticleTopics(ctx, tx, articleID, add)
+    })
+    return dbError(err)
+}
+
+// touchArticle moves a live article to its next version when only its topics
+// change, so ETags, If-Match updates and feeds see the new topic set. The row
+// lock also queues concurrent topic changes of the article.
+func touchArticle(ctx context.Context, tx pgx.Tx, articleID uuid.UUID) error {
     query := `
-    INSERT INTO article_topics (article_id, topic_id)
-    VALUES ($1, unnest($2::text[]))`
+    UPDATE articles
+    SET version = version + 1,
+    updated_at = NOW()
+    WHERE id = $1 AND deleted_at IS NULL`
+
+    tag, err := tx.Exec(ctx, query, articleID)
+    if err != nil {
+        return err
+    }
+    if tag.RowsAffected() == 0 {
+        return domain.ErrArticleNotFound
+    }
+    return nil
+}
+
+// setArticleTopics replaces the topics of an article with the ones requested
+// inline, topic names are resolved to IDs first and created when missing
+func setArticleTopics(ctx context.Context, tx pgx.Tx, articleID uuid.UUID, article *domain.Article) error {
+    topicIDs := article.TopicIDs
+    seen := make(map[string]bool, len(topicIDs))
+    for _, id := range topicIDs {
+        seen[id] = true
+    }
+
+    for _, name := range article.TopicNames {
+        var id string
+        lookup := `
+        SELECT id::text FROM topics
+        WHERE lower(name) = lower($1) AND deleted_at IS NULL`
+        err := tx.QueryRow(ctx, lookup, name).Scan(&id)
+        if errors.Is(err, pgx.ErrNoRows) {
+            var slug string
+            slug, err = topicSlugs.unique(ctx, tx, name, nil)
+            if err != nil {
+                return err
+            }
+            query := `
+            INSERT INTO topics (name, slug, created_at, updated_at)
+            VALUES ($1, $2, NOW(), NOW())
+            ON CONFLICT (lower(name)) WHERE deleted_at IS NULL DO NOTHING
+            RETURNING id::text`
+            err = tx.QueryRow(ctx, query, name, slug).Scan(&id)
+            if err == nil {
+                err = insertTopicEvents(ctx, tx, domain.EventTopicCreated, nil, uuid.MustParse(id))
+            } else if errors.Is(err, pgx.ErrNoRows) {
+                // another request created the topic since the lookup
+                err = tx.QueryRow(ctx, lookup, name).Scan(&id)
+            }
+        }
+        if err != nil {
+            return err
+        }
+
+        if !seen[id] {
+            seen[id] = true
+            topicIDs = append(topicIDs, id)
+        }
+    }
+
+    return replaceArticleTopics(ctx, tx, articleID, topicIDs)
+}
+
+// replaceArticleTopics unlinks the topics of an article missing from topicIDs
+// and links the new ones, topics in both stay untouched
+func replaceArticleTopics(ctx context.Context, tx pgx.Tx, articleID uuid.UUID, topicIDs []string) error {
+    query := `
+    DELETE FROM article_topics
+    WHERE article_id = $1 AND NOT (topic_id = ANY($2::uuid[]))
+    RETURNING topic_id::text`
+    if err := deleteArticleTopics(ctx, tx, articleID, query, articleID, topicIDs); err != nil {
+        return err
+    }
+    return insertArticleTopics(ctx, tx, articleID, topicIDs)
+}
+
+// deleteArticleTopics runs a DELETE on article_topics returning topic_id and
+// records an unlinked event for every removed link
+func deleteArticleTopics(ctx context.Context, tx pgx.Tx, articleID uuid.UUID, query string, args ...any) error {
+    rows, err := tx.Query(ctx, query, args...)
+    if err != nil {
+        return err
+    }
+    unlinked, err := pgx.CollectRows(rows, pgx.RowTo[string])
+    if err != nil {
+        return err
+    }
+    return insertTopicLinkEvents(ctx, tx, domain.EventTopicUnlinked, articleID, unlinked)
+}
+
+// insertArticleTopics links deduplicated topics to an article inside a
+// transaction, failing with ErrBadParamInput when a topic is missing or deleted
+func insertArticleTopics(ctx context.Context, tx pgx.Tx, articleID uuid.UUID, topicIDs []string) error {
+    if len(topicIDs) == 0 {
+        return nil
+    }
+
+    // lock the topics so they can't be deleted before the links are written
+    query := `
+    SELECT id::text FROM topics
+    WHERE id = ANY($1::uuid[]) AND deleted_at IS NULL
+    FOR SHARE`
+    rows, err := tx.Query(ctx, query, topicIDs)
+    if err != nil {
+        return err
+    }
+    found, err := pgx.CollectRows(rows, pgx.RowTo[string])
+    if err != nil {
+        return err
+    }
+    if len(found) != len(topicIDs) {
+        existing := make(map[string]bool, len(found))
+        for _, id := range found {
+            existing[id] = true
+        }
+        var missing []string
+        for _, id := range topicIDs {
+            if !existing[id] {
+                missing = append(missing, id)
+            }
+        }
+        return fmt.Errorf("%w: unknown topics %s", domain.ErrBadParamInput, strings.Join(missing, ", "))
+    }
 
-    _, err := a.Conn.Exec(ctx, query, articleID, topicIDs)
-    return err
+    // https://www.w3resource.com/PostgreSQL/postgresql_unnest-function.php
+    query = `
+    INSERT INTO article_topics (article_id, topic_id)
+    SELECT $1, unnest($2::uuid[])
+    ON CONFLICT DO NOTHING
+    RETURNING topic_id::text`
+    rows, err = tx.Query(ctx, query, articleID, topicIDs)
+    if err != nil {
+        return err
+    }
+    linked, err := pgx.CollectRows(rows, pgx.RowTo[string])
+    if err != nil {
+        return err
+    }
+    return insertTopicLinkEvents(ctx, tx, domain.EventTopicLinked, articleID, linked)
 }
 
 func (a *ArticleRepository) RemoveTopicFromArticle(
@@ -316,10 +1139,16 @@ func (a *ArticleRepository) RemoveTopicFromArticle(
     // Use deleted_at??
     query := `
     DELETE FROM article_topics
-    WHERE article_id = $1 AND topic_id = $2`
+    WHERE article_id = $1 AND topic_id = $2
+    RETURNING topic_id::text`
 
-    _, err := a.Conn.Exec(ctx, query, articleID, topicID)
-    return err
+    err := pgx.BeginFunc(ctx, a.Conn, func(tx pgx.Tx) error {
+        if err := touchArticle(ctx, tx, articleID); err != nil {
+            return err
+        }
+        return deleteArticleTopics(ctx, tx, articleID, query, articleID, topicID)
+    })
+    return dbError(err)
 }
 
 func (a *ArticleRepository) GetTopicsByArticleID(
@@ -330,11 +1159,11 @@ func (a *ArticleRepository) GetTopicsByArticleID(
     SELECT t.id, t.name, t.created_at, t.updated_at
     FROM article_topics at
     JOIN topics t ON at.topic_id = t.id
-    WHERE at.article_id = $1`
+    WHERE at.article_id = $1 AND t.deleted_at IS NULL`
 
     rows, err := a.Conn.Query(ctx, query, articleID)
     if err != nil {
-        return nil, err
+        return nil, dbError(err)
     }
 
     defer rows.Close()
@@ -356,3 +1185,233 @@ func (a *ArticleRepository) GetTopicsByArticleID(
 
     return topics, nil
 }
+
+func (a *ArticleRepository) SearchArticles(ctx context.Context, filter *domain.ArticleSearchFilter) ([]domain.ArticleSearchResult, error) {
+    tracer := otel.Tracer("repo.article")
+    ctx, span := tracer.Start(ctx, "ArticleRepository.SearchArticles")
+    defer span.End()
+
+    websearch, prefixes := splitSearchQuery(filter.Query)
+    args := []interface{}{websearch, prefixes}
+    var conditions []string
+    argIndex := 3
+
+    if filter.Status != "" {
+        conditions = append(conditions, fmt.Sprintf(`(a.status = $%d)`, argIndex))
+        args = append(args, filter.Status)
+        argIndex++
+    }
+    if filter.Topic != "" {
+        condition := fmt.Sprintf(`EXISTS (
+            SELECT 1 FROM article_topics ft
+            JOIN topics t ON ft.topic_id = t.id
+            WHERE ft.article_id = a.id AND (t.name = $%[1]d OR t.slug = $%[1]d) AND t.deleted_at IS NULL
+        )`, argIndex)
+        conditions = append(conditions, condition)
+        args = append(args, filter.Topic)
+        argIndex++
+    }
+    if condition, scopeArgs := scopeCondition(filter.Scope, argIndex); condition != "" {
+        conditions = append(conditions, condition)
+        args = append(args, scopeArgs...)
+        argIndex += len(scopeArgs)
+    }
+
+    where := "a.deleted_at IS NULL AND a.search_vector @@ q.query"
+    if len(conditions) > 0 {
+        where += " AND " + strings.Join(conditions, " AND ")
+    }
+    // the headline is cut from the sanitized HTML between markers, which
+    // survive escaping its text and become <mark> afterwards
+    headlineOptions := fmt.Sprintf("StartSel=%s, StopSel=%s, MaxWords=35, MinWords=15, MaxFragments=2",
+        render.HighlightStart, render.HighlightStop)
+    args = append(args, filter.Limit, filter.Offset, headlineOptions)
+
+    // rank and paginate the matches first, then attach their topics
+    query := fmt.Sprintf(`
+        WITH q AS (
+            SELECT websearch_to_tsquery('simple', $1) && to_tsquery('simple', $2) AS query
+        ), matches AS (
+            SELECT
+                a.id, a.title, a.slug, a.content, a.content_format, a.excerpt, a.reading_time, a.cover_media_id,
+                a.status, a.published_at, a.scheduled_at, a.version, a.created_at, a.updated_at,
+                COALESCE(a.author_id::text, '') AS author_id,
+                COALESCE(u.name, '') AS author,
+                ts_rank(a.search_vector, q.query) AS rank,
+                ts_headline('simple', a.content_html, q.query, $%d) AS headline
+            FROM articles a
+            CROSS JOIN q
+            LEFT JOIN users u ON a.author_id = u.id
+            WHERE %s
+            ORDER BY rank DESC, a.created_at DESC, a.id
+            LIMIT $%d OFFSET $%d
+        )
+        SELECT
+            a.id,
+            a.title,
+            a.slug,
+            a.content,
+            a.content_format,
+            a.excerpt,
+            a.reading_time,
+            a.cover_media_id,
+            a.author_id,
+            a.author,
+            a.status,
+            a.published_at,
+            a.scheduled_at,
+            a.version,
+            a.created_at,
+            a.updated_at,
+            a.rank,
+            a.headline,
+            t.id AS topic_id,
+            t.name AS topic_name,
+            t.created_at AS topic_created_at,
+            t.updated_at AS topic_updated_at
+        FROM matches a
+        LEFT JOIN article_topics at ON a.id = at.article_id
+        LEFT JOIN topics t ON at.topic_id = t.id AND t.deleted_at IS NULL
+        ORDER BY a.rank DESC, a.created_at DESC, a.id`,
+        argIndex+2,
+        where,
+        argIndex,
+        argIndex+1,
+    )
+
+    span.SetAttributes(attribute.String("query.statement", query))
+    span.SetAttributes(attribute.String("query.parameter", filter.Query))
+    rows, err := a.Conn.Query(ctx, query, args...)
+    if err != nil {
+        span.RecordError(err)
+        return nil, err
+    }
+    defer rows.Close()
+
+    var results []domain.ArticleSearchResult
+    resultIndex := make(map[string]int)
+
+    for rows.Next() {
+        var topicID, topicName sql.NullString
+        var topicCreatedAt, topicUpdatedAt sql.NullTime
+
+        var result domain.ArticleSearchResult
+        err := rows.Scan(
+            &result.ID,
+            &result.Title,
+            &result.Slug,
+            &result.Content,
+            &result.ContentFormat,
+            &result.Excerpt,
+            &result.ReadingTimeMinutes,
+            &result.CoverMediaID,
+            &result.AuthorID,
+            &result.Author,
+            &result.Status,
+            &result.PublishedAt,
+            &result.ScheduledAt,
+            &result.Version,
+            &result.CreatedAt,
+            &result.UpdatedAt,
+            &result.Rank,
+            &result.Headline,
+            &topicID,
+            &topicName,
+            &topicCreatedAt,
+            &topicUpdatedAt,
+        )
+        if err != nil {
+            span.RecordError(err)
+            return nil, err
+        }
+
+        index, exists := resultIndex[result.ID]
+        if !exists {
+            result.Headline = render.Highlight(result.Headline)
+            result.Topics = []domain.Topic{}
+            results = append(results, result)
+            index = len(results) - 1
+            resultIndex[result.ID] = index
+        }
+
+        if topicID.Valid {
+            topic := domain.Topic{
+                ID: topicID.String,
+                Name: topicName.String,
+                CreatedAt: topicCreatedAt.Time,
+                UpdatedAt: topicUpdatedAt.Time,
+            }
+            results[index].Topics = append(results[index].Topics, topic)
+        }
+    }
+
+    return results, rows.Err()
+}
+
+// scopeCondition limits a query on articles aliased a to the ones scope may
+// read, its parameters are numbered from argIndex
+func scopeCondition(scope domain.ArticleScope, argIndex int) (string, []any) {
+    if !scope.Restricted {
+        return "", nil
+    }
+    if scope.OwnerID == "" {
+        return fmt.Sprintf(`(a.status = $%d)`, argIndex), []any{domain.StatusPublished}
+    }
+    return fmt.Sprintf(`(a.status = $%d OR a.author_id = $%d)`, argIndex, argIndex+1),
+        []any{domain.StatusPublished, scope.OwnerID}
+}
+
+// splitSearchQuery separates prefix terms (tech*) from the rest of a web search
+// query, since websearch_to_tsquery drops the * operator. The prefixes are
+// returned as to_tsquery syntax; an empty tsquery is ignored by the && operator.
+func splitSearchQuery(q string) (websearch string, prefixes string) {
+    var rest, terms []string
+    var token strings.Builder
+    quoted := false
+
+    flush := func() {
+        word := token.String()
+        token.Reset()
+        if word == "" {
+            return
+        }
+
+        negated := strings.HasPrefix(word, "-")
+        term := strings.TrimPrefix(word, "-")
+        if !strings.HasSuffix(term, "*") {
+            rest = append(rest, word)
+            return
+        }
+
+        term = strings.Map(func(r rune) rune {
+            if unicode.IsLetter(r) || unicode.IsDigit(r) {
+                return unicode.ToLower(r)
+            }
+            return -1
+        }, term)
+        if term == "" {
+            return
+        }
+
+        lexeme := "'" + term + "':*"
+        if negated {
+            lexeme = "!" + lexeme
+        }
+        terms = append(terms, lexeme)
+    }
+
+    for _, r := range q {
+        switch {
+        case r == '"':
+            quoted = !quoted
+            token.WriteRune(r)
+        case unicode.IsSpace(r) && !quoted:
+            flush()
+        default:
+            token.WriteRune(r)
+        }
+    }
+    flush()
+
+    return strings.Join(rest, " "), strings.Join(terms, " & ")
+}
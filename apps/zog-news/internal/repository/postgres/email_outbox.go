@@ -0,0 +1,130 @@
+package postgres
+
+import (
+	"context"
+	"time"
+	"zog-news/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/otel"
+)
+
+type EmailOutboxRepository struct {
+	Conn *pgxpool.Pool
+}
+
+func NewEmailOutboxRepository(conn *pgxpool.Pool) *EmailOutboxRepository {
+	return &EmailOutboxRepository{
+		Conn: conn,
+	}
+}
+
+// articleEmailData is the template data shared by every article email
+const articleEmailData = `
+jsonb_build_object(
+    'recipient_name', r.name,
+    'article_id', a.id::text,
+    'article_title', a.title,
+    'article_slug', a.slug,
+    'author_name', COALESCE(au.name, '')
+)`
+
+// enqueueArticleSubmittedEmails queues a review request for every editor and
+// admin except the author of the article, inside the transaction submitting it
+func enqueueArticleSubmittedEmails(ctx context.Context, tx pgx.Tx, articleID uuid.UUID) error {
+	query := `
+    INSERT INTO email_outbox (template, recipient, data)
+    SELECT $1, r.email, ` + articleEmailData + `
+    FROM articles a
+    LEFT JOIN users au ON au.id = a.author_id
+    JOIN users r ON r.role IN ('editor', 'admin')
+    AND r.deleted_at IS NULL
+    AND r.id IS DISTINCT FROM a.author_id
+    WHERE a.id = $2`
+
+	_, err := tx.Exec(ctx, query, domain.EmailArticleSubmitted, articleID)
+	return err
+}
+
+// enqueueArticlePublishedEmails queues a notice for the authors of the given
+// articles, inside the transaction publishing them
+func enqueueArticlePublishedEmails(ctx context.Context, tx pgx.Tx, articleIDs []uuid.UUID) error {
+	query := `
+    INSERT INTO email_outbox (template, recipient, data)
+    SELECT $1, r.email, ` + articleEmailData + `
+    FROM articles a
+    JOIN users au ON au.id = a.author_id
+    JOIN users r ON r.id = au.id AND r.deleted_at IS NULL
+    WHERE a.id = ANY($2)`
+
+	_, err := tx.Exec(ctx, query, domain.EmailArticlePublished, articleIDs)
+	return err
+}
+
+// ClaimEmails returns up to limit emails due at now and holds them until
+// lease, so other workers skip them while they are being delivered. An email
+// whose worker dies is retried once the lease passes.
+func (o *EmailOutboxRepository) ClaimEmails(ctx context.Context, now, lease time.Time, limit int) ([]domain.OutboxEmail, error) {
+	tracer := otel.Tracer("repo.email_outbox")
+	ctx, span := tracer.Start(ctx, "EmailOutboxRepository.ClaimEmails")
+	defer span.End()
+
+	query := `
+    UPDATE email_outbox
+    SET attempts = attempts + 1,
+    next_attempt_at = $2
+    WHERE id IN (
+        SELECT id FROM email_outbox
+        WHERE sent_at IS NULL AND next_attempt_at <= $1
+        ORDER BY next_attempt_at
+        LIMIT $3
+        FOR UPDATE SKIP LOCKED
+    )
+    RETURNING id, template, recipient, data, attempts`
+
+	rows, err := o.Conn.Query(ctx, query, now, lease, limit)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var emails []domain.OutboxEmail
+	for rows.Next() {
+		var email domain.OutboxEmail
+		if err := rows.Scan(&email.ID, &email.Template, &email.Recipient, &email.Data, &email.Attempts); err != nil {
+			span.RecordError(err)
+			return nil, err
+		}
+		emails = append(emails, email)
+	}
+	return emails, rows.Err()
+}
+
+// MarkEmailSent records the delivery of an email
+func (o *EmailOutboxRepository) MarkEmailSent(ctx context.Context, id string, sentAt time.Time) error {
+	query := `
+    UPDATE email_outbox
+    SET sent_at = $2,
+    next_attempt_at = NULL,
+    last_error = NULL
+    WHERE id = $1`
+
+	_, err := o.Conn.Exec(ctx, query, id, sentAt)
+	return err
+}
+
+// MarkEmailFailed records a failed delivery, the email is tried again at
+// retryAt or never when retryAt is nil
+func (o *EmailOutboxRepository) MarkEmailFailed(ctx context.Context, id string, reason string, retryAt *time.Time) error {
+	query := `
+    UPDATE email_outbox
+    SET last_error = $2,
+    next_attempt_at = $3
+    WHERE id = $1`
+
+	_, err := o.Conn.Exec(ctx, query, id, reason, retryAt)
+	return err
+}
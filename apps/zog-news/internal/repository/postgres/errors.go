@@ -0,0 +1,57 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"zog-news/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// SQLSTATE codes translated into domain errors
+const (
+	uniqueViolation         = "23505"
+	foreignKeyViolation     = "23503"
+	notNullViolation        = "23502"
+	checkViolation          = "23514"
+	invalidTextRepresention = "22P02"
+)
+
+// isUniqueViolation reports whether err is a duplicate key on the given
+// constraint or unique index
+func isUniqueViolation(err error, constraint string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolation && pgErr.ConstraintName == constraint
+}
+
+// dbError translates the driver errors a client can cause into domain errors
+// so callers never have to know about pgx. Anything else, including errors
+// that are already domain errors, is returned unchanged.
+func dbError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.ErrNotFound
+	}
+
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+	switch pgErr.Code {
+	case uniqueViolation:
+		return fmt.Errorf("%w: duplicate value for %s", domain.ErrConflict, pgErr.ConstraintName)
+	case foreignKeyViolation:
+		return fmt.Errorf("%w: referenced record does not exist", domain.ErrBadParamInput)
+	case notNullViolation:
+		return fmt.Errorf("%w: %s is required", domain.ErrBadParamInput, pgErr.ColumnName)
+	case checkViolation:
+		return fmt.Errorf("%w: value violates %s", domain.ErrBadParamInput, pgErr.ConstraintName)
+	case invalidTextRepresention:
+		// malformed uuids and values outside an enum
+		return fmt.Errorf("%w: invalid value", domain.ErrBadParamInput)
+	}
+	return err
+}
@@ -0,0 +1,243 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"zog-news/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type MediaRepository struct {
+	Conn *pgxpool.Pool
+}
+
+func NewMediaRepository(conn *pgxpool.Pool) *MediaRepository {
+	return &MediaRepository{
+		Conn: conn,
+	}
+}
+
+const mediaColumns = `
+    m.id,
+    m.filename,
+    m.content_type,
+    m.size,
+    m.width,
+    m.height,
+    m.alt,
+    m.storage_key,
+    m.thumbnail_key,
+    m.uploaded_by,
+    m.created_at`
+
+func scanMedia(row pgx.Row) (*domain.Media, error) {
+	var media domain.Media
+	var width, height *int
+	var thumbnailKey, uploadedBy *string
+	err := row.Scan(
+		&media.ID,
+		&media.Filename,
+		&media.ContentType,
+		&media.Size,
+		&width,
+		&height,
+		&media.Alt,
+		&media.StorageKey,
+		&thumbnailKey,
+		&uploadedBy,
+		&media.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if width != nil && height != nil {
+		media.Width, media.Height = *width, *height
+	}
+	if thumbnailKey != nil {
+		media.ThumbnailKey = *thumbnailKey
+	}
+	if uploadedBy != nil {
+		media.UploadedBy = *uploadedBy
+	}
+	media.SetURLs()
+	return &media, nil
+}
+
+// CreateMedia records a file the service already stored, under the ID it
+// was stored with
+func (m *MediaRepository) CreateMedia(ctx context.Context, media *domain.Media) (*domain.Media, error) {
+	tracer := otel.Tracer("repo.media")
+	ctx, span := tracer.Start(ctx, "MediaRepository.CreateMedia")
+	defer span.End()
+
+	query := `
+    INSERT INTO media AS m (
+        id, filename, content_type, size, width, height, alt,
+        storage_key, thumbnail_key, uploaded_by, created_at
+    )
+    VALUES ($1, $2, $3, $4, NULLIF($5, 0), NULLIF($6, 0), $7, $8, NULLIF($9, ''), NULLIF($10, '')::uuid, $11)
+    RETURNING` + mediaColumns
+
+	created, err := scanMedia(m.Conn.QueryRow(ctx, query,
+		media.ID,
+		media.Filename,
+		media.ContentType,
+		media.Size,
+		media.Width,
+		media.Height,
+		media.Alt,
+		media.StorageKey,
+		media.ThumbnailKey,
+		media.UploadedBy,
+		media.CreatedAt,
+	))
+	if err != nil {
+		span.RecordError(err)
+		return nil, dbError(err)
+	}
+	return created, nil
+}
+
+func (m *MediaRepository) GetMedia(ctx context.Context, id uuid.UUID) (*domain.Media, error) {
+	tracer := otel.Tracer("repo.media")
+	ctx, span := tracer.Start(ctx, "MediaRepository.GetMedia")
+	defer span.End()
+
+	query := `SELECT` + mediaColumns + `
+    FROM media m
+    WHERE m.id = $1`
+
+	span.SetAttributes(attribute.String("query.parameter", id.String()))
+	media, err := scanMedia(m.Conn.QueryRow(ctx, query, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrMediaNotFound
+	}
+	if err != nil {
+		span.RecordError(err)
+		return nil, dbError(err)
+	}
+	return media, nil
+}
+
+// DeleteMedia removes the record of a file, failing with ErrConflict while
+// an article, trashed or not, uses it as cover or attachment
+func (m *MediaRepository) DeleteMedia(ctx context.Context, id uuid.UUID) error {
+	tracer := otel.Tracer("repo.media")
+	ctx, span := tracer.Start(ctx, "MediaRepository.DeleteMedia")
+	defer span.End()
+
+	tag, err := m.Conn.Exec(ctx, `DELETE FROM media WHERE id = $1`, id)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolation {
+		return fmt.Errorf("%w: media is used by an article", domain.ErrConflict)
+	}
+	if err != nil {
+		span.RecordError(err)
+		return dbError(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrMediaNotFound
+	}
+	return nil
+}
+
+// checkCoverMedia fails with ErrBadParamInput unless the cover is nil or an
+// existing image, locking the media so it can't be deleted before the
+// article refers to it
+func checkCoverMedia(ctx context.Context, tx pgx.Tx, coverID *string) error {
+	if coverID == nil {
+		return nil
+	}
+
+	var contentType string
+	query := `SELECT content_type FROM media WHERE id = $1 FOR SHARE`
+	err := tx.QueryRow(ctx, query, *coverID).Scan(&contentType)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("%w: unknown cover media %s", domain.ErrBadParamInput, *coverID)
+	}
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(contentType, "image/") {
+		return fmt.Errorf("%w: cover media must be an image", domain.ErrBadParamInput)
+	}
+	return nil
+}
+
+// setArticleMedia replaces the attachments of an article with mediaIDs, in
+// that order, failing with ErrBadParamInput when a media is missing
+func setArticleMedia(ctx context.Context, tx pgx.Tx, articleID uuid.UUID, mediaIDs []string) error {
+	if _, err := tx.Exec(ctx, `DELETE FROM article_media WHERE article_id = $1`, articleID); err != nil {
+		return err
+	}
+	if len(mediaIDs) == 0 {
+		return nil
+	}
+
+	query := `
+    SELECT id::text FROM media
+    WHERE id = ANY($1::uuid[])
+    FOR SHARE`
+	rows, err := tx.Query(ctx, query, mediaIDs)
+	if err != nil {
+		return err
+	}
+	found, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return err
+	}
+	if len(found) != len(mediaIDs) {
+		existing := make(map[string]bool, len(found))
+		for _, id := range found {
+			existing[id] = true
+		}
+		var missing []string
+		for _, id := range mediaIDs {
+			if !existing[id] {
+				missing = append(missing, id)
+			}
+		}
+		return fmt.Errorf("%w: unknown media %s", domain.ErrBadParamInput, strings.Join(missing, ", "))
+	}
+
+	query = `
+    INSERT INTO article_media (article_id, media_id, position)
+    SELECT $1, id, position
+    FROM unnest($2::uuid[]) WITH ORDINALITY AS u(id, position)`
+	_, err = tx.Exec(ctx, query, articleID, mediaIDs)
+	return err
+}
+
+// getArticleMedia returns the attachments of an article in their order
+func getArticleMedia(ctx context.Context, conn *pgxpool.Pool, articleID uuid.UUID) ([]domain.Media, error) {
+	query := `SELECT` + mediaColumns + `
+    FROM article_media am
+    JOIN media m ON m.id = am.media_id
+    WHERE am.article_id = $1
+    ORDER BY am.position`
+
+	rows, err := conn.Query(ctx, query, articleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var attachments []domain.Media
+	for rows.Next() {
+		media, err := scanMedia(rows)
+		if err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, *media)
+	}
+	return attachments, rows.Err()
+}
@@ -0,0 +1,173 @@
+package postgres
+
+import (
+	"context"
+	"time"
+	"zog-news/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/otel"
+)
+
+type OutboxRepository struct {
+	Conn *pgxpool.Pool
+}
+
+func NewOutboxRepository(conn *pgxpool.Pool) *OutboxRepository {
+	return &OutboxRepository{
+		Conn: conn,
+	}
+}
+
+// insertArticleEvents records an event for each article with a snapshot of
+// its current row. The content is left out and so are topics, their changes
+// are recorded as link events.
+func insertArticleEvents(ctx context.Context, tx pgx.Tx, eventType domain.EventType, articleIDs ...uuid.UUID) error {
+	query := `
+    INSERT INTO outbox (event_type, aggregate_type, aggregate_id, payload)
+    SELECT $1, $2, a.id, jsonb_build_object(
+        'id', a.id,
+        'title', a.title,
+        'slug', a.slug,
+        'status', a.status,
+        'content_format', a.content_format,
+        'excerpt', a.excerpt,
+        'reading_time_minutes', a.reading_time,
+        'cover_media_id', a.cover_media_id,
+        'author_id', a.author_id,
+        'version', a.version,
+        'published_at', a.published_at,
+        'scheduled_at', a.scheduled_at,
+        'deleted_at', a.deleted_at,
+        'created_at', a.created_at,
+        'updated_at', a.updated_at
+    )
+    FROM articles a
+    WHERE a.id = ANY($3)
+    ORDER BY a.id`
+
+	_, err := tx.Exec(ctx, query, eventType, domain.AggregateArticle, articleIDs)
+	return err
+}
+
+// insertTopicEvents records an event for each topic with a snapshot of its
+// current row, extra is nil or an object merged into every payload
+func insertTopicEvents(ctx context.Context, tx pgx.Tx, eventType domain.EventType, extra any, topicIDs ...uuid.UUID) error {
+	query := `
+    INSERT INTO outbox (event_type, aggregate_type, aggregate_id, payload)
+    SELECT $1, $2, t.id, jsonb_build_object(
+        'id', t.id,
+        'name', t.name,
+        'slug', t.slug,
+        'parent_id', t.parent_id,
+        'version', t.version,
+        'deleted_at', t.deleted_at,
+        'created_at', t.created_at,
+        'updated_at', t.updated_at
+    ) || COALESCE($4::jsonb, '{}')
+    FROM topics t
+    WHERE t.id = ANY($3)
+    ORDER BY t.id`
+
+	_, err := tx.Exec(ctx, query, eventType, domain.AggregateTopic, topicIDs, extra)
+	return err
+}
+
+// insertTopicLinkEvents records a linked or unlinked event on the article for
+// each topic
+func insertTopicLinkEvents(ctx context.Context, tx pgx.Tx, eventType domain.EventType, articleID uuid.UUID, topicIDs []string) error {
+	if len(topicIDs) == 0 {
+		return nil
+	}
+
+	query := `
+    INSERT INTO outbox (event_type, aggregate_type, aggregate_id, payload)
+    SELECT $1, $2, $3, jsonb_build_object('article_id', $3::uuid, 'topic_id', t)
+    FROM unnest($4::uuid[]) t
+    ORDER BY t`
+
+	_, err := tx.Exec(ctx, query, eventType, domain.AggregateArticle, articleID, topicIDs)
+	return err
+}
+
+// ClaimEvents returns up to limit events due at now, oldest first, and holds
+// them until lease so other relays skip them while they are being published.
+// An event whose relay dies is retried once the lease passes.
+func (o *OutboxRepository) ClaimEvents(ctx context.Context, now, lease time.Time, limit int) ([]domain.Event, error) {
+	tracer := otel.Tracer("repo.outbox")
+	ctx, span := tracer.Start(ctx, "OutboxRepository.ClaimEvents")
+	defer span.End()
+
+	query := `
+    WITH claimed AS (
+        UPDATE outbox
+        SET attempts = attempts + 1,
+        next_attempt_at = $2
+        WHERE id IN (
+            SELECT id FROM outbox
+            WHERE next_attempt_at <= $1
+            ORDER BY sequence
+            LIMIT $3
+            FOR UPDATE SKIP LOCKED
+        )
+        RETURNING id, sequence, event_type, aggregate_type, aggregate_id, payload, occurred_at, attempts
+    )
+    SELECT id, sequence, event_type, aggregate_type, aggregate_id::text, payload, occurred_at, attempts
+    FROM claimed
+    ORDER BY sequence`
+
+	rows, err := o.Conn.Query(ctx, query, now, lease, limit)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []domain.Event
+	for rows.Next() {
+		var event domain.Event
+		if err := rows.Scan(
+			&event.ID,
+			&event.Sequence,
+			&event.Type,
+			&event.AggregateType,
+			&event.AggregateID,
+			&event.Payload,
+			&event.OccurredAt,
+			&event.Attempts,
+		); err != nil {
+			span.RecordError(err)
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+// MarkEventPublished records that an event reached the sink
+func (o *OutboxRepository) MarkEventPublished(ctx context.Context, id string, publishedAt time.Time) error {
+	query := `
+    UPDATE outbox
+    SET published_at = $2,
+    next_attempt_at = NULL,
+    last_error = NULL
+    WHERE id = $1`
+
+	_, err := o.Conn.Exec(ctx, query, id, publishedAt)
+	return err
+}
+
+// MarkEventFailed records a failed publish, the event is tried again at
+// retryAt or never when retryAt is nil
+func (o *OutboxRepository) MarkEventFailed(ctx context.Context, id string, reason string, retryAt *time.Time) error {
+	query := `
+    UPDATE outbox
+    SET last_error = $2,
+    next_attempt_at = $3
+    WHERE id = $1`
+
+	_, err := o.Conn.Exec(ctx, query, id, reason, retryAt)
+	return err
+}
@@ -0,0 +1,115 @@
+package postgres
+
+import (
+	"context"
+	"time"
+	"zog-news/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/otel"
+)
+
+// SitemapRepository lists the slugs of everything search engines may index,
+// only published articles and live topics
+type SitemapRepository struct {
+	Conn *pgxpool.Pool
+}
+
+func NewSitemapRepository(conn *pgxpool.Pool) *SitemapRepository {
+	return &SitemapRepository{
+		Conn: conn,
+	}
+}
+
+func (s *SitemapRepository) CountSitemapArticles(ctx context.Context) (int, error) {
+	tracer := otel.Tracer("repo.sitemap")
+	ctx, span := tracer.Start(ctx, "SitemapRepository.CountSitemapArticles")
+	defer span.End()
+
+	query := `
+    SELECT COUNT(*)
+    FROM articles
+    WHERE status = $1 AND deleted_at IS NULL`
+
+	var count int
+	if err := s.Conn.QueryRow(ctx, query, domain.StatusPublished).Scan(&count); err != nil {
+		span.RecordError(err)
+		return 0, err
+	}
+	return count, nil
+}
+
+// GetSitemapArticles returns a page of published articles, oldest first so
+// new articles only ever extend the last page
+func (s *SitemapRepository) GetSitemapArticles(ctx context.Context, offset, limit int) ([]domain.SitemapEntry, error) {
+	tracer := otel.Tracer("repo.sitemap")
+	ctx, span := tracer.Start(ctx, "SitemapRepository.GetSitemapArticles")
+	defer span.End()
+
+	query := `
+    SELECT id, slug, title, published_at, updated_at
+    FROM articles
+    WHERE status = $1 AND deleted_at IS NULL
+    ORDER BY published_at, id
+    OFFSET $2
+    LIMIT $3`
+
+	entries, err := s.collect(ctx, query, domain.StatusPublished, offset, limit)
+	if err != nil {
+		span.RecordError(err)
+	}
+	return entries, err
+}
+
+// GetNewsSitemapArticles returns the articles published since, newest first
+func (s *SitemapRepository) GetNewsSitemapArticles(ctx context.Context, since time.Time, limit int) ([]domain.SitemapEntry, error) {
+	tracer := otel.Tracer("repo.sitemap")
+	ctx, span := tracer.Start(ctx, "SitemapRepository.GetNewsSitemapArticles")
+	defer span.End()
+
+	query := `
+    SELECT id, slug, title, published_at, updated_at
+    FROM articles
+    WHERE status = $1 AND deleted_at IS NULL AND published_at >= $2
+    ORDER BY published_at DESC, id
+    LIMIT $3`
+
+	entries, err := s.collect(ctx, query, domain.StatusPublished, since, limit)
+	if err != nil {
+		span.RecordError(err)
+	}
+	return entries, err
+}
+
+// GetSitemapTopics returns up to limit live topics by name
+func (s *SitemapRepository) GetSitemapTopics(ctx context.Context, limit int) ([]domain.SitemapEntry, error) {
+	tracer := otel.Tracer("repo.sitemap")
+	ctx, span := tracer.Start(ctx, "SitemapRepository.GetSitemapTopics")
+	defer span.End()
+
+	query := `
+    SELECT id, slug, name, NULL::timestamp, updated_at
+    FROM topics
+    WHERE deleted_at IS NULL
+    ORDER BY name, id
+    LIMIT $1`
+
+	entries, err := s.collect(ctx, query, limit)
+	if err != nil {
+		span.RecordError(err)
+	}
+	return entries, err
+}
+
+func (s *SitemapRepository) collect(ctx context.Context, query string, args ...any) ([]domain.SitemapEntry, error) {
+	rows, err := s.Conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.SitemapEntry, error) {
+		var entry domain.SitemapEntry
+		err := row.Scan(&entry.ID, &entry.Slug, &entry.Title, &entry.PublishedAt, &entry.LastModified)
+		return entry, err
+	})
+}
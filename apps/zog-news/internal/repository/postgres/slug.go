@@ -0,0 +1,119 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"zog-news/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// queryRower is implemented by both the pool and a transaction
+type queryRower interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// slugTable describes where an entity keeps its slug and its old slugs. The
+// names are constants below, never user input.
+type slugTable struct {
+	table    string
+	history  string
+	owner    string
+	source   string
+	fallback string
+}
+
+var (
+	articleSlugs = slugTable{table: "articles", history: "article_slug_history", owner: "article_id", source: "title", fallback: "article"}
+	topicSlugs   = slugTable{table: "topics", history: "topic_slug_history", owner: "topic_id", source: "name", fallback: "topic"}
+)
+
+// maxSlugSuffix bounds the numbered candidates tried before falling back to
+// an id based suffix
+const maxSlugSuffix = 100
+
+// unique returns a slug for text that no other row uses now or used before.
+// id is nil for rows that don't exist yet, their own old slugs are free to
+// take back.
+func (s slugTable) unique(ctx context.Context, tx pgx.Tx, text string, id *uuid.UUID) (string, error) {
+	base := domain.Slugify(text)
+	if base == "" {
+		base = s.fallback
+	}
+
+	query := fmt.Sprintf(`
+		SELECT EXISTS (SELECT 1 FROM %[1]s WHERE slug = $1 AND id IS DISTINCT FROM $2)
+			OR EXISTS (SELECT 1 FROM %[2]s WHERE slug = $1 AND %[3]s IS DISTINCT FROM $2)`,
+		s.table, s.history, s.owner)
+
+	candidate := base
+	for n := 2; n <= maxSlugSuffix; n++ {
+		var taken bool
+		if err := tx.QueryRow(ctx, query, candidate, id).Scan(&taken); err != nil {
+			return "", err
+		}
+		if !taken {
+			return candidate, nil
+		}
+		candidate = fmt.Sprintf("%s-%d", base, n)
+	}
+	return fmt.Sprintf("%s-%s", base, uuid.New().String()[:8]), nil
+}
+
+// rename gives a row a slug for its new title or name and moves the old slug
+// to the history. The row is locked until the transaction ends, so callers update it
+// in the same transaction.
+func (s slugTable) rename(ctx context.Context, tx pgx.Tx, id uuid.UUID, text string) (string, error) {
+	var current, currentText string
+	query := fmt.Sprintf(`SELECT slug, %s FROM %s WHERE id = $1 FOR UPDATE`, s.source, s.table)
+	if err := tx.QueryRow(ctx, query, id).Scan(&current, &currentText); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			// the guarded update that follows reports the missing row
+			return "", nil
+		}
+		return "", err
+	}
+	if currentText == text {
+		return current, nil
+	}
+
+	slug, err := s.unique(ctx, tx, text, &id)
+	if err != nil {
+		return "", err
+	}
+	if slug == current {
+		return current, nil
+	}
+
+	query = fmt.Sprintf(`
+		INSERT INTO %s (slug, %s) VALUES ($1, $2)
+		ON CONFLICT (slug) DO NOTHING`, s.history, s.owner)
+	if _, err := tx.Exec(ctx, query, current, id); err != nil {
+		return "", err
+	}
+
+	query = fmt.Sprintf(`DELETE FROM %s WHERE slug = $1`, s.history)
+	if _, err := tx.Exec(ctx, query, slug); err != nil {
+		return "", err
+	}
+	return slug, nil
+}
+
+// resolve finds the live row using slug, either as its current slug or one it
+// had before. It returns pgx.ErrNoRows when there is none.
+func (s slugTable) resolve(ctx context.Context, conn queryRower, slug string) (uuid.UUID, error) {
+	query := fmt.Sprintf(`
+		SELECT id FROM %[1]s
+		WHERE slug = $1 AND deleted_at IS NULL
+		UNION ALL
+		SELECT h.%[3]s FROM %[2]s h
+		JOIN %[1]s e ON e.id = h.%[3]s
+		WHERE h.slug = $1 AND e.deleted_at IS NULL
+		LIMIT 1`, s.table, s.history, s.owner)
+
+	var id uuid.UUID
+	err := conn.QueryRow(ctx, query, slug).Scan(&id)
+	return id, err
+}
@@ -0,0 +1,193 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"zog-news/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// StatsRepository keeps the daily view counts of articles and ranks them
+type StatsRepository struct {
+	Conn *pgxpool.Pool
+}
+
+func NewStatsRepository(conn *pgxpool.Pool) *StatsRepository {
+	return &StatsRepository{
+		Conn: conn,
+	}
+}
+
+// AddArticleViews adds a batch of counted views to the daily stats. Views of
+// articles purged since they were counted are dropped. Rows are upserted by
+// day and article, the same order for every batch, so concurrent batches
+// can't deadlock on each other.
+func (s *StatsRepository) AddArticleViews(ctx context.Context, views []domain.ArticleViews) error {
+	tracer := otel.Tracer("repo.stats")
+	ctx, span := tracer.Start(ctx, "StatsRepository.AddArticleViews")
+	defer span.End()
+
+	if len(views) == 0 {
+		return nil
+	}
+	articleIDs := make([]string, len(views))
+	days := make([]time.Time, len(views))
+	counts := make([]int64, len(views))
+	for i, v := range views {
+		articleIDs[i], days[i], counts[i] = v.ArticleID, v.Day, v.Views
+	}
+
+	query := `
+    INSERT INTO article_stats (article_id, day, views)
+    SELECT v.article_id, v.day, v.views
+    FROM unnest($1::uuid[], $2::date[], $3::bigint[]) AS v(article_id, day, views)
+    JOIN articles a ON a.id = v.article_id
+    ORDER BY v.day, v.article_id
+    ON CONFLICT (article_id, day) DO UPDATE
+    SET views = article_stats.views + EXCLUDED.views`
+
+	span.SetAttributes(attribute.Int("stats.batch_size", len(views)))
+	if _, err := s.Conn.Exec(ctx, query, articleIDs, days, counts); err != nil {
+		span.RecordError(err)
+		return dbError(err)
+	}
+	return nil
+}
+
+// GetTrendingArticles ranks the published articles viewed in the window by
+// their views, each weighed down by its age with the half life of the
+// filter. Buckets are whole days, so the window starts at midnight of the
+// first day it reaches into and a day counts as viewed at its noon.
+func (s *StatsRepository) GetTrendingArticles(ctx context.Context, filter *domain.TrendingFilter, now time.Time) ([]domain.TrendingArticle, error) {
+	tracer := otel.Tracer("repo.stats")
+	ctx, span := tracer.Start(ctx, "StatsRepository.GetTrendingArticles")
+	defer span.End()
+
+	since := now.Add(-domain.TrendingWindows[filter.Window])
+	args := []any{now, filter.HalfLife().Hours(), since, domain.StatusPublished, filter.Limit}
+
+	topicCondition := ""
+	if filter.Topic != "" {
+		// topics are addressed by ID, or by name ignoring case like their
+		// uniqueness, or by slug
+		match := "(lower(t.name) = lower($6) OR t.slug = lower($6))"
+		if _, err := uuid.Parse(filter.Topic); err == nil {
+			match = "t.id = $6::uuid"
+		}
+		topicCondition = fmt.Sprintf(`AND EXISTS (
+            SELECT 1 FROM article_topics ft
+            JOIN topics t ON ft.topic_id = t.id
+            WHERE ft.article_id = a.id AND %s AND t.deleted_at IS NULL
+        )`, match)
+		args = append(args, filter.Topic)
+	}
+
+	query := fmt.Sprintf(`
+        WITH scores AS (
+            SELECT
+                s.article_id,
+                SUM(s.views)::bigint AS views,
+                SUM(s.views * power(0.5,
+                    GREATEST(EXTRACT(EPOCH FROM $1::timestamp - (s.day + INTERVAL '12 hours'))::float8, 0) / 3600 / $2::float8
+                )) AS score
+            FROM article_stats s
+            WHERE s.day >= $3::date
+            GROUP BY s.article_id
+        )
+        SELECT
+            a.id,
+            a.title,
+            a.slug,
+            a.content,
+            a.content_format,
+            a.excerpt,
+            a.reading_time,
+            a.cover_media_id,
+            COALESCE(a.author_id::text, ''),
+            COALESCE(u.name, ''),
+            a.status,
+            a.published_at,
+            a.scheduled_at,
+            a.version,
+            a.created_at,
+            a.updated_at,
+            sc.views,
+            sc.score
+        FROM scores sc
+        JOIN articles a ON a.id = sc.article_id
+        LEFT JOIN users u ON a.author_id = u.id
+        WHERE a.status = $4 AND a.deleted_at IS NULL %s
+        ORDER BY sc.score DESC, a.published_at DESC, a.id
+        LIMIT $5`, topicCondition)
+
+	span.SetAttributes(attribute.String("trending.window", filter.Window))
+	rows, err := s.Conn.Query(ctx, query, args...)
+	if err != nil {
+		span.RecordError(err)
+		return nil, dbError(err)
+	}
+	defer rows.Close()
+
+	var articles []domain.TrendingArticle
+	for rows.Next() {
+		var article domain.TrendingArticle
+		if err := rows.Scan(
+			&article.ID,
+			&article.Title,
+			&article.Slug,
+			&article.Content,
+			&article.ContentFormat,
+			&article.Excerpt,
+			&article.ReadingTimeMinutes,
+			&article.CoverMediaID,
+			&article.AuthorID,
+			&article.Author,
+			&article.Status,
+			&article.PublishedAt,
+			&article.ScheduledAt,
+			&article.Version,
+			&article.CreatedAt,
+			&article.UpdatedAt,
+			&article.Views,
+			&article.Score,
+		); err != nil {
+			span.RecordError(err)
+			return nil, err
+		}
+		articles = append(articles, article)
+	}
+	return articles, rows.Err()
+}
+
+// GetArticleViews returns the days since the given one an article was
+// viewed on, oldest first
+func (s *StatsRepository) GetArticleViews(ctx context.Context, articleID uuid.UUID, since time.Time) ([]domain.DailyViews, error) {
+	tracer := otel.Tracer("repo.stats")
+	ctx, span := tracer.Start(ctx, "StatsRepository.GetArticleViews")
+	defer span.End()
+
+	query := `
+    SELECT to_char(day, 'YYYY-MM-DD'), views
+    FROM article_stats
+    WHERE article_id = $1 AND day >= $2::date
+    ORDER BY day`
+
+	span.SetAttributes(attribute.String("query.parameter", articleID.String()))
+	rows, err := s.Conn.Query(ctx, query, articleID, since)
+	if err != nil {
+		span.RecordError(err)
+		return nil, dbError(err)
+	}
+	views, err := pgx.CollectRows(rows, pgx.RowToStructByPos[domain.DailyViews])
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	return views, nil
+}
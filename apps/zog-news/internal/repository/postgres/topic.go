@@ -2,10 +2,14 @@ package postgres
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"strings"
+	"time"
 	"zog-news/domain"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
@@ -21,23 +25,57 @@ func NewTopicRepository(conn *pgxpool.Pool) *TopicRepository {
 	}
 }
 
+// topicNameKey is the unique index on the lowercased names of live topics
+const topicNameKey = "topics_name_key"
+
+// topicNameError turns a duplicate topic name into ErrConflict
+func topicNameError(err error, name string) error {
+	if isUniqueViolation(err, topicNameKey) {
+		return fmt.Errorf("%w: topic %q already exists", domain.ErrConflict, name)
+	}
+	return err
+}
+
 func (a *TopicRepository) CreateTopic(ctx context.Context, topic *domain.CreateTopicRequest) (*domain.Topic, error) {
+	var created *domain.Topic
+	err := pgx.BeginFunc(ctx, a.Conn, func(tx pgx.Tx) error {
+		if topic.ParentID != nil {
+			if err := checkTopicParent(ctx, tx, nil, *topic.ParentID); err != nil {
+				return err
+			}
+		}
 
-	query := `
-		INSERT INTO topics (name, created_at, updated_at)
-		VALUES ($1, NOW(), NOW())
-		RETURNING id`
+		slug, err := topicSlugs.unique(ctx, tx, topic.Name, nil)
+		if err != nil {
+			return err
+		}
+
+		query := `
+			INSERT INTO topics (name, slug, parent_id, created_at, updated_at)
+			VALUES ($1, $2, $3, NOW(), NOW())
+			RETURNING id`
 
-	var id uuid.UUID
-	var err = a.Conn.QueryRow(ctx, query, topic.Name).Scan(&id)
+		var id uuid.UUID
+		if err := tx.QueryRow(ctx, query, topic.Name, slug, topic.ParentID).Scan(&id); err != nil {
+			return topicNameError(err, topic.Name)
+		}
+		if err := insertTopicEvents(ctx, tx, domain.EventTopicCreated, nil, id); err != nil {
+			return err
+		}
+
+		created = &domain.Topic{
+			ID:       id.String(),
+			Name:     topic.Name,
+			Slug:     slug,
+			ParentID: topic.ParentID,
+			Version:  1,
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, err
+		return nil, dbError(err)
 	}
-
-	return &domain.Topic{
-		ID:    id.String(),
-		Name:  topic.Name,
-	}, nil
+	return created, nil
 }
 
 func (a *TopicRepository) GetTopicList(ctx context.Context, filter *domain.TopicFilter) ([]domain.Topic, error) {
@@ -45,6 +83,9 @@ func (a *TopicRepository) GetTopicList(ctx context.Context, filter *domain.Topic
 		SELECT
             a.id,
             a.name,
+            a.slug,
+            a.parent_id,
+            a.version,
             a.created_at,
             a.updated_at
 		FROM topics a
@@ -62,7 +103,7 @@ func (a *TopicRepository) GetTopicList(ctx context.Context, filter *domain.Topic
 	}
 	rows, err := a.Conn.Query(ctx, query, args...)
 	if err != nil {
-		return nil, err
+		return nil, dbError(err)
 	}
 	defer rows.Close()
 
@@ -73,6 +114,9 @@ func (a *TopicRepository) GetTopicList(ctx context.Context, filter *domain.Topic
 		err := rows.Scan(
 			&topic.ID,
 			&topic.Name,
+			&topic.Slug,
+			&topic.ParentID,
+			&topic.Version,
 			&topic.CreatedAt,
 			&topic.UpdatedAt,
 		)
@@ -94,6 +138,9 @@ func (a *TopicRepository) GetTopic(ctx context.Context, id uuid.UUID) (*domain.T
 		SELECT
 			id,
 			name,
+			slug,
+			parent_id,
+			version,
 			created_at,
 			updated_at
 		FROM topics
@@ -107,27 +154,63 @@ func (a *TopicRepository) GetTopic(ctx context.Context, id uuid.UUID) (*domain.T
 	err := row.Scan(
 		&topic.ID,
 		&topic.Name,
+		&topic.Slug,
+		&topic.ParentID,
+		&topic.Version,
 		&topic.CreatedAt,
 		&topic.UpdatedAt,
 	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrTopicNotFound
+	}
 	if err != nil {
 		span.RecordError(err)
-		return nil, err
+		return nil, dbError(err)
 	}
 
 	return &topic, nil
 }
 
+// UpdateTopic renames and moves a topic while it is still at topic.Version,
+// rejecting a parent that is missing or inside the topic's own subtree. A new
+// name moves the topic to a new slug and keeps the old one as a redirect
 func (a *TopicRepository) UpdateTopic(ctx context.Context, id uuid.UUID, topic *domain.Topic) (*domain.Topic, error) {
-	query := `
-		UPDATE topics
-		SET name = $1,
-			updated_at = NOW()
-		WHERE id = $2 AND deleted_at IS NULL`
+	err := pgx.BeginFunc(ctx, a.Conn, func(tx pgx.Tx) error {
+		if topic.ParentID != nil {
+			// Serialize moves so two concurrent ones can't close a loop
+			if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext('topics.parent_id'))`); err != nil {
+				return err
+			}
+			if err := checkTopicParent(ctx, tx, &id, *topic.ParentID); err != nil {
+				return err
+			}
+		}
+
+		slug, err := topicSlugs.rename(ctx, tx, id, topic.Name)
+		if err != nil {
+			return err
+		}
+
+		query := `
+			UPDATE topics
+			SET name = $1,
+				slug = $2,
+				parent_id = $3,
+				version = version + 1,
+				updated_at = NOW()
+			WHERE id = $4 AND version = $5 AND deleted_at IS NULL`
 
-	_, err := a.Conn.Exec(ctx, query, topic.Name, id)
+		tag, err := tx.Exec(ctx, query, topic.Name, slug, topic.ParentID, id, topic.Version)
+		if err != nil {
+			return topicNameError(err, topic.Name)
+		}
+		if tag.RowsAffected() == 0 {
+			return domain.ErrPreconditionFailed
+		}
+		return insertTopicEvents(ctx, tx, domain.EventTopicUpdated, nil, id)
+	})
 	if err != nil {
-		return nil, err
+		return nil, dbError(err)
 	}
 
 	updatedTopic, err := a.GetTopic(ctx, id)
@@ -137,28 +220,390 @@ func (a *TopicRepository) UpdateTopic(ctx context.Context, id uuid.UUID, topic *
 	return updatedTopic, nil
 }
 
-func (a *TopicRepository) DeleteTopic(ctx context.Context, id uuid.UUID) error {
+// checkTopicParent walks up from parentID and fails when the parent is not
+// a live topic, or when id is one of its ancestors so the move would loop
+func checkTopicParent(ctx context.Context, tx pgx.Tx, id *uuid.UUID, parentID string) error {
+	query := `
+		WITH RECURSIVE ancestors AS (
+			SELECT id, parent_id FROM topics
+			WHERE id = $1 AND deleted_at IS NULL
+			UNION
+			SELECT t.id, t.parent_id FROM topics t
+			JOIN ancestors a ON t.id = a.parent_id
+		)
+		SELECT count(*) > 0, COALESCE(bool_or(id = $2), false)
+		FROM ancestors`
+
+	var exists, cycle bool
+	if err := tx.QueryRow(ctx, query, parentID, id).Scan(&exists, &cycle); err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("%w: parent topic %s not found", domain.ErrBadParamInput, parentID)
+	}
+	if cycle {
+		return fmt.Errorf("%w: topic cannot be moved under its own subtree", domain.ErrBadParamInput)
+	}
+	return nil
+}
+
+// GetTopicChildren lists the direct subtopics of a topic by name
+func (a *TopicRepository) GetTopicChildren(ctx context.Context, id uuid.UUID) ([]domain.Topic, error) {
+	var exists bool
+	query := `SELECT EXISTS (SELECT 1 FROM topics WHERE id = $1 AND deleted_at IS NULL)`
+	if err := a.Conn.QueryRow(ctx, query, id).Scan(&exists); err != nil {
+		return nil, dbError(err)
+	}
+	if !exists {
+		return nil, domain.ErrTopicNotFound
+	}
+
+	query = `
+		SELECT
+			id,
+			name,
+			slug,
+			parent_id,
+			version,
+			created_at,
+			updated_at
+		FROM topics
+		WHERE parent_id = $1 AND deleted_at IS NULL
+		ORDER BY lower(name), id`
+
+	rows, err := a.Conn.Query(ctx, query, id)
+	if err != nil {
+		return nil, dbError(err)
+	}
+	defer rows.Close()
+
+	var topics []domain.Topic
+	for rows.Next() {
+		var topic domain.Topic
+		if err := rows.Scan(
+			&topic.ID,
+			&topic.Name,
+			&topic.Slug,
+			&topic.ParentID,
+			&topic.Version,
+			&topic.CreatedAt,
+			&topic.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		topics = append(topics, topic)
+	}
+
+	return topics, rows.Err()
+}
+
+// GetTopicIDBySlug resolves a current or previous slug to the live topic using it
+func (a *TopicRepository) GetTopicIDBySlug(ctx context.Context, slug string) (uuid.UUID, error) {
+	id, err := topicSlugs.resolve(ctx, a.Conn, slug)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return uuid.Nil, domain.ErrTopicNotFound
+	}
+	return id, err
+}
+
+// MergeTopics moves the articles, subtopics and slugs of the source topics to
+// the target and puts the sources in the trash, all in one transaction
+func (a *TopicRepository) MergeTopics(ctx context.Context, targetID uuid.UUID, sourceIDs []string) (*domain.Topic, error) {
+	err := pgx.BeginFunc(ctx, a.Conn, func(tx pgx.Tx) error {
+		// merging re-parents subtopics, serialize it with topic moves
+		if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext('topics.parent_id'))`); err != nil {
+			return err
+		}
+
+		query := `SELECT id FROM topics WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`
+		var id uuid.UUID
+		if err := tx.QueryRow(ctx, query, targetID).Scan(&id); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return domain.ErrTopicNotFound
+			}
+			return err
+		}
+
+		query = `
+			SELECT ARRAY(
+				SELECT s::text FROM unnest($1::uuid[]) s
+				WHERE NOT EXISTS (
+					SELECT 1 FROM topics WHERE id = s AND deleted_at IS NULL
+				)
+			)`
+		var missing []string
+		if err := tx.QueryRow(ctx, query, sourceIDs).Scan(&missing); err != nil {
+			return err
+		}
+		if len(missing) > 0 {
+			return fmt.Errorf("%w: unknown topics %s", domain.ErrBadParamInput, strings.Join(missing, ", "))
+		}
+
+		// the target would lose its parent if it sat below a source
+		query = `
+			WITH RECURSIVE ancestors AS (
+				SELECT parent_id FROM topics WHERE id = $1
+				UNION
+				SELECT t.parent_id FROM topics t
+				JOIN ancestors a ON t.id = a.parent_id
+			)
+			SELECT EXISTS (SELECT 1 FROM ancestors WHERE parent_id = ANY($2::uuid[]))`
+		var nested bool
+		if err := tx.QueryRow(ctx, query, targetID, sourceIDs).Scan(&nested); err != nil {
+			return err
+		}
+		if nested {
+			return fmt.Errorf("%w: topic cannot be merged into one of its subtopics", domain.ErrBadParamInput)
+		}
+
+		statements := []struct {
+			query string
+			args  []any
+		}{
+			{`INSERT INTO article_topics (article_id, topic_id)
+				SELECT DISTINCT article_id, $1::uuid FROM article_topics WHERE topic_id = ANY($2::uuid[])
+				ON CONFLICT DO NOTHING`, []any{targetID, sourceIDs}},
+			{`DELETE FROM article_topics WHERE topic_id = ANY($1::uuid[])`, []any{sourceIDs}},
+			{`UPDATE topics SET parent_id = $1 WHERE parent_id = ANY($2::uuid[])`, []any{targetID, sourceIDs}},
+			// the sources' slugs, old and current, redirect to the target
+			{`UPDATE topic_slug_history SET topic_id = $1 WHERE topic_id = ANY($2::uuid[])`, []any{targetID, sourceIDs}},
+			{`INSERT INTO topic_slug_history (slug, topic_id)
+				SELECT slug, $1::uuid FROM topics WHERE id = ANY($2::uuid[])
+				ON CONFLICT (slug) DO UPDATE SET topic_id = EXCLUDED.topic_id`, []any{targetID, sourceIDs}},
+			{`UPDATE topics SET deleted_at = NOW(), version = version + 1 WHERE id = ANY($1::uuid[])`, []any{sourceIDs}},
+			{`UPDATE topics SET version = version + 1, updated_at = NOW() WHERE id = $1`, []any{targetID}},
+		}
+		for _, statement := range statements {
+			if _, err := tx.Exec(ctx, statement.query, statement.args...); err != nil {
+				return err
+			}
+		}
+
+		sources := make([]uuid.UUID, len(sourceIDs))
+		for i, sourceID := range sourceIDs {
+			// the lookup of missing sources above already cast them to uuid
+			sources[i] = uuid.MustParse(sourceID)
+		}
+		if err := insertTopicEvents(ctx, tx, domain.EventTopicDeleted, nil, sources...); err != nil {
+			return err
+		}
+		merged := map[string]any{"source_ids": sourceIDs}
+		return insertTopicEvents(ctx, tx, domain.EventTopicMerged, merged, targetID)
+	})
+	if err != nil {
+		return nil, dbError(err)
+	}
+
+	return a.GetTopic(ctx, targetID)
+}
+
+// DeleteTopic soft deletes a topic while it is still at version
+func (a *TopicRepository) DeleteTopic(ctx context.Context, id uuid.UUID, version int) error {
 	query := `
 		UPDATE topics
-		SET deleted_at = NOW()
-		WHERE id = $1 AND deleted_at IS NULL`
+		SET deleted_at = NOW(),
+			version = version + 1
+		WHERE id = $1 AND version = $2 AND deleted_at IS NULL`
 
-	_, err := a.Conn.Exec(ctx, query, id)
-	return err
+	err := pgx.BeginFunc(ctx, a.Conn, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, query, id, version)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return domain.ErrPreconditionFailed
+		}
+		return insertTopicEvents(ctx, tx, domain.EventTopicDeleted, nil, id)
+	})
+	return dbError(err)
 }
 
+// GetDeletedTopics lists the topics in the trash, most recently deleted first
+func (a *TopicRepository) GetDeletedTopics(ctx context.Context) ([]domain.Topic, error) {
+	query := `
+		SELECT
+			id,
+			name,
+			slug,
+			parent_id,
+			version,
+			deleted_at,
+			created_at,
+			updated_at
+		FROM topics
+		WHERE deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC, id`
+
+	rows, err := a.Conn.Query(ctx, query)
+	if err != nil {
+		return nil, dbError(err)
+	}
+	defer rows.Close()
+
+	var topics []domain.Topic
+	for rows.Next() {
+		var topic domain.Topic
+		if err := rows.Scan(
+			&topic.ID,
+			&topic.Name,
+			&topic.Slug,
+			&topic.ParentID,
+			&topic.Version,
+			&topic.DeletedAt,
+			&topic.CreatedAt,
+			&topic.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		topics = append(topics, topic)
+	}
+
+	return topics, rows.Err()
+}
+
+// RestoreTopic takes a topic out of the trash
+func (a *TopicRepository) RestoreTopic(ctx context.Context, id uuid.UUID) (*domain.Topic, error) {
+	query := `
+		UPDATE topics
+		SET deleted_at = NULL,
+			version = version + 1,
+			updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NOT NULL`
+
+	err := pgx.BeginFunc(ctx, a.Conn, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, query, id)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return domain.ErrTopicNotFound
+		}
+		return insertTopicEvents(ctx, tx, domain.EventTopicRestored, nil, id)
+	})
+	if isUniqueViolation(err, topicNameKey) {
+		return nil, fmt.Errorf("%w: a live topic already uses this name", domain.ErrConflict)
+	}
+	if err != nil {
+		return nil, dbError(err)
+	}
+
+	return a.GetTopic(ctx, id)
+}
+
+// PurgeTopic permanently removes a topic in the trash with its article links
+func (a *TopicRepository) PurgeTopic(ctx context.Context, id uuid.UUID) error {
+	return pgx.BeginFunc(ctx, a.Conn, func(tx pgx.Tx) error {
+		query := `
+			SELECT id FROM topics
+			WHERE id = $1 AND deleted_at IS NOT NULL
+			FOR UPDATE`
+		if err := tx.QueryRow(ctx, query, id).Scan(&id); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return domain.ErrTopicNotFound
+			}
+			return err
+		}
+		// the event keeps the last snapshot of the row
+		if err := insertTopicEvents(ctx, tx, domain.EventTopicPurged, nil, id); err != nil {
+			return err
+		}
+
+		query = `
+			DELETE FROM article_topics
+			WHERE topic_id = $1`
+		if _, err := tx.Exec(ctx, query, id); err != nil {
+			return err
+		}
+
+		query = `
+			DELETE FROM topics
+			WHERE id = $1`
+		_, err := tx.Exec(ctx, query, id)
+		return err
+	})
+}
+
+// PurgeDeletedTopics permanently removes every topic deleted before the given
+// time and returns how many were removed
+func (a *TopicRepository) PurgeDeletedTopics(ctx context.Context, before time.Time) (int64, error) {
+	var purged int64
+	err := pgx.BeginFunc(ctx, a.Conn, func(tx pgx.Tx) error {
+		query := `
+			SELECT id FROM topics
+			WHERE deleted_at < $1
+			FOR UPDATE`
+		rows, err := tx.Query(ctx, query, before)
+		if err != nil {
+			return err
+		}
+		ids, err := pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+		if err != nil || len(ids) == 0 {
+			return err
+		}
+		if err := insertTopicEvents(ctx, tx, domain.EventTopicPurged, nil, ids...); err != nil {
+			return err
+		}
+
+		query = `
+			DELETE FROM article_topics
+			WHERE topic_id = ANY($1)`
+		if _, err := tx.Exec(ctx, query, ids); err != nil {
+			return err
+		}
+
+		query = `
+			DELETE FROM topics
+			WHERE id = ANY($1)`
+		tag, err := tx.Exec(ctx, query, ids)
+		if err != nil {
+			return err
+		}
+		purged = tag.RowsAffected()
+		return nil
+	})
+	return purged, err
+}
+
+// GetTopicArticles lists the articles tagged with a topic, and with any of
+// its live subtopics when IncludeDescendants is set, that the scope of the
+// filter may read
 func (a *TopicRepository) GetTopicArticles(
     ctx context.Context,
     id uuid.UUID,
+    filter *domain.TopicArticlesFilter,
 ) ([]domain.Article, error) {
-    query := `
-        SELECT a.id, a.title, a.content, a.author, a.status, a.created_at, a.updated_at
+    if filter == nil {
+        filter = &domain.TopicArticlesFilter{}
+    }
+    args := []any{id, filter.IncludeDescendants}
+    scope := ""
+    if condition, scopeArgs := scopeCondition(filter.Scope, 3); condition != "" {
+        scope = "AND " + condition
+        args = append(args, scopeArgs...)
+    }
+
+    query := fmt.Sprintf(`
+        WITH RECURSIVE subtree AS (
+            SELECT id FROM topics WHERE id = $1
+            UNION
+            SELECT t.id FROM topics t
+            JOIN subtree s ON t.parent_id = s.id
+            WHERE $2::boolean AND t.deleted_at IS NULL
+        )
+        SELECT
+            a.id, a.title, a.slug, a.content, a.content_format, a.excerpt, a.reading_time, a.cover_media_id,
+            COALESCE(a.author_id::text, ''), COALESCE(u.name, ''),
+            a.status, a.published_at, a.scheduled_at, a.version, a.created_at, a.updated_at
         FROM articles a
-        JOIN article_topics at ON a.id = at.article_id
-        WHERE at.topic_id = $1 AND a.deleted_at IS NULL`
-    rows, err := a.Conn.Query(ctx, query, id)
+        LEFT JOIN users u ON a.author_id = u.id
+        WHERE a.deleted_at IS NULL AND EXISTS (
+            SELECT 1 FROM article_topics at
+            WHERE at.article_id = a.id AND at.topic_id IN (SELECT id FROM subtree)
+        ) %s
+        ORDER BY a.created_at DESC, a.id`, scope)
+    rows, err := a.Conn.Query(ctx, query, args...)
     if err != nil {
-        return nil, err
+        return nil, dbError(err)
     }
 
     defer rows.Close()
@@ -170,9 +615,18 @@ func (a *TopicRepository) GetTopicArticles(
         if err := rows.Scan(
             &article.ID,
             &article.Title,
+            &article.Slug,
             &article.Content,
+            &article.ContentFormat,
+            &article.Excerpt,
+            &article.ReadingTimeMinutes,
+            &article.CoverMediaID,
+            &article.AuthorID,
             &article.Author,
             &article.Status,
+            &article.PublishedAt,
+            &article.ScheduledAt,
+            &article.Version,
             &article.CreatedAt,
             &article.UpdatedAt,
         ); err != nil {
@@ -0,0 +1,153 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"zog-news/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type UserRepository struct {
+	Conn *pgxpool.Pool
+}
+
+func NewUserRepository(conn *pgxpool.Pool) *UserRepository {
+	return &UserRepository{
+		Conn: conn,
+	}
+}
+
+func (u *UserRepository) CreateUser(ctx context.Context, user *domain.User) (*domain.User, error) {
+	query := `
+		INSERT INTO users (name, email, role, password_hash, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, NOW(), NOW())
+		RETURNING id, created_at, updated_at`
+
+	created := *user
+	err := u.Conn.QueryRow(ctx, query, user.Name, user.Email, user.Role, user.PasswordHash).
+		Scan(&created.ID, &created.CreatedAt, &created.UpdatedAt)
+	if isUniqueViolation(err, "users_email_key") {
+		return nil, fmt.Errorf("%w: email already registered", domain.ErrConflict)
+	}
+	if err != nil {
+		return nil, dbError(err)
+	}
+
+	return &created, nil
+}
+
+func (u *UserRepository) GetUser(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	tracer := otel.Tracer("repo.user")
+	ctx, span := tracer.Start(ctx, "UserRepository.GetUser")
+	defer span.End()
+
+	query := `
+		SELECT id, name, email, role, password_hash, created_at, updated_at
+		FROM users
+		WHERE id = $1 AND deleted_at IS NULL`
+
+	span.SetAttributes(attribute.String("query.statement", query))
+	span.SetAttributes(attribute.String("query.parameter", id.String()))
+	user, err := scanUser(u.Conn.QueryRow(ctx, query, id))
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	return user, nil
+}
+
+func (u *UserRepository) GetUserByEmail(ctx context.Context, email string) (*domain.User, error) {
+	query := `
+		SELECT id, name, email, role, password_hash, created_at, updated_at
+		FROM users
+		WHERE lower(email) = lower($1) AND deleted_at IS NULL`
+
+	return scanUser(u.Conn.QueryRow(ctx, query, email))
+}
+
+func (u *UserRepository) UpdateUserRole(ctx context.Context, id uuid.UUID, role domain.Role) (*domain.User, error) {
+	query := `
+		UPDATE users
+		SET role = $1,
+			updated_at = NOW()
+		WHERE id = $2 AND deleted_at IS NULL
+		RETURNING id, name, email, role, password_hash, created_at, updated_at`
+
+	return scanUser(u.Conn.QueryRow(ctx, query, role, id))
+}
+
+func (u *UserRepository) CreateRefreshToken(ctx context.Context, token *domain.RefreshToken) error {
+	query := `
+		INSERT INTO refresh_tokens (user_id, token_hash, expires_at)
+		VALUES ($1, $2, $3)`
+
+	_, err := u.Conn.Exec(ctx, query, token.UserID, token.TokenHash, token.ExpiresAt)
+	return dbError(err)
+}
+
+func (u *UserRepository) GetRefreshToken(ctx context.Context, tokenHash string) (*domain.RefreshToken, error) {
+	query := `
+		SELECT id, user_id, token_hash, expires_at, revoked_at
+		FROM refresh_tokens
+		WHERE token_hash = $1`
+
+	var token domain.RefreshToken
+	err := u.Conn.QueryRow(ctx, query, tokenHash).Scan(
+		&token.ID,
+		&token.UserID,
+		&token.TokenHash,
+		&token.ExpiresAt,
+		&token.RevokedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrInvalidToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// RevokeRefreshToken marks a refresh token as used, it reports ErrInvalidToken
+// when the token was already revoked so a replayed token can't be rotated twice.
+func (u *UserRepository) RevokeRefreshToken(ctx context.Context, id string) error {
+	query := `
+		UPDATE refresh_tokens
+		SET revoked_at = NOW()
+		WHERE id = $1 AND revoked_at IS NULL`
+
+	tag, err := u.Conn.Exec(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrInvalidToken
+	}
+	return nil
+}
+
+func scanUser(row pgx.Row) (*domain.User, error) {
+	var user domain.User
+	err := row.Scan(
+		&user.ID,
+		&user.Name,
+		&user.Email,
+		&user.Role,
+		&user.PasswordHash,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, dbError(err)
+	}
+	return &user, nil
+}
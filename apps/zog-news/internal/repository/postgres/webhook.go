@@ -0,0 +1,388 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+	"zog-news/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// deliveryLogLimit caps how many deliveries of a webhook are listed
+const deliveryLogLimit = 100
+
+type WebhookRepository struct {
+	Conn *pgxpool.Pool
+}
+
+func NewWebhookRepository(conn *pgxpool.Pool) *WebhookRepository {
+	return &WebhookRepository{
+		Conn: conn,
+	}
+}
+
+const webhookColumns = `
+    id,
+    url,
+    event_types,
+    failure_count,
+    disabled_at,
+    created_by,
+    created_at,
+    updated_at`
+
+func scanWebhook(row pgx.Row) (*domain.Webhook, error) {
+	var webhook domain.Webhook
+	var eventTypes []string
+	var createdBy *string
+	err := row.Scan(
+		&webhook.ID,
+		&webhook.URL,
+		&eventTypes,
+		&webhook.FailureCount,
+		&webhook.DisabledAt,
+		&createdBy,
+		&webhook.CreatedAt,
+		&webhook.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	webhook.EventTypes = make([]domain.EventType, len(eventTypes))
+	for i, t := range eventTypes {
+		webhook.EventTypes[i] = domain.EventType(t)
+	}
+	webhook.Active = webhook.DisabledAt == nil
+	if createdBy != nil {
+		webhook.CreatedBy = *createdBy
+	}
+	return &webhook, nil
+}
+
+func (w *WebhookRepository) CreateWebhook(ctx context.Context, webhook *domain.Webhook) (*domain.Webhook, error) {
+	tracer := otel.Tracer("repo.webhook")
+	ctx, span := tracer.Start(ctx, "WebhookRepository.CreateWebhook")
+	defer span.End()
+
+	eventTypes := make([]string, len(webhook.EventTypes))
+	for i, t := range webhook.EventTypes {
+		eventTypes[i] = string(t)
+	}
+
+	query := `
+    INSERT INTO webhooks (url, event_types, secret, created_by, created_at, updated_at)
+    VALUES ($1, $2, $3, $4, NOW(), NOW())
+    RETURNING` + webhookColumns
+
+	created, err := scanWebhook(w.Conn.QueryRow(ctx, query, webhook.URL, eventTypes, webhook.Secret, webhook.CreatedBy))
+	if err != nil {
+		span.RecordError(err)
+		return nil, dbError(err)
+	}
+	created.Secret = webhook.Secret
+
+	return created, nil
+}
+
+func (w *WebhookRepository) GetWebhookList(ctx context.Context) ([]domain.Webhook, error) {
+	tracer := otel.Tracer("repo.webhook")
+	ctx, span := tracer.Start(ctx, "WebhookRepository.GetWebhookList")
+	defer span.End()
+
+	query := `SELECT` + webhookColumns + `
+    FROM webhooks
+    ORDER BY created_at, id`
+
+	rows, err := w.Conn.Query(ctx, query)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var webhooks []domain.Webhook
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			span.RecordError(err)
+			return nil, err
+		}
+		webhooks = append(webhooks, *webhook)
+	}
+	return webhooks, rows.Err()
+}
+
+func (w *WebhookRepository) GetWebhook(ctx context.Context, id uuid.UUID) (*domain.Webhook, error) {
+	tracer := otel.Tracer("repo.webhook")
+	ctx, span := tracer.Start(ctx, "WebhookRepository.GetWebhook")
+	defer span.End()
+
+	query := `SELECT` + webhookColumns + `
+    FROM webhooks
+    WHERE id = $1`
+
+	span.SetAttributes(attribute.String("query.parameter", id.String()))
+	webhook, err := scanWebhook(w.Conn.QueryRow(ctx, query, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrWebhookNotFound
+	}
+	if err != nil {
+		span.RecordError(err)
+		return nil, dbError(err)
+	}
+
+	return webhook, nil
+}
+
+// DeleteWebhook removes a webhook together with its delivery log
+func (w *WebhookRepository) DeleteWebhook(ctx context.Context, id uuid.UUID) error {
+	tracer := otel.Tracer("repo.webhook")
+	ctx, span := tracer.Start(ctx, "WebhookRepository.DeleteWebhook")
+	defer span.End()
+
+	tag, err := w.Conn.Exec(ctx, `DELETE FROM webhooks WHERE id = $1`, id)
+	if err != nil {
+		span.RecordError(err)
+		return dbError(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrWebhookNotFound
+	}
+	return nil
+}
+
+// GetWebhookDeliveries returns the latest deliveries of a webhook, newest
+// first
+func (w *WebhookRepository) GetWebhookDeliveries(ctx context.Context, id uuid.UUID) ([]domain.WebhookDelivery, error) {
+	tracer := otel.Tracer("repo.webhook")
+	ctx, span := tracer.Start(ctx, "WebhookRepository.GetWebhookDeliveries")
+	defer span.End()
+
+	query := `
+    SELECT
+        id,
+        webhook_id,
+        event_id,
+        event_type,
+        status,
+        attempts,
+        response_status,
+        COALESCE(last_error, ''),
+        next_attempt_at,
+        delivered_at,
+        created_at
+    FROM webhook_deliveries
+    WHERE webhook_id = $1
+    ORDER BY created_at DESC, id
+    LIMIT $2`
+
+	span.SetAttributes(attribute.String("query.parameter", id.String()))
+	rows, err := w.Conn.Query(ctx, query, id, deliveryLogLimit)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []domain.WebhookDelivery
+	for rows.Next() {
+		var delivery domain.WebhookDelivery
+		if err := rows.Scan(
+			&delivery.ID,
+			&delivery.WebhookID,
+			&delivery.EventID,
+			&delivery.EventType,
+			&delivery.Status,
+			&delivery.Attempts,
+			&delivery.ResponseStatus,
+			&delivery.LastError,
+			&delivery.NextAttemptAt,
+			&delivery.DeliveredAt,
+			&delivery.CreatedAt,
+		); err != nil {
+			span.RecordError(err)
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, rows.Err()
+}
+
+// EnqueueDeliveries queues the event for every active webhook subscribed to
+// its type and returns how many were queued. An event queued before is
+// skipped, so relaying it again is harmless.
+func (w *WebhookRepository) EnqueueDeliveries(ctx context.Context, eventID string, eventType domain.EventType, body []byte) (int, error) {
+	tracer := otel.Tracer("repo.webhook")
+	ctx, span := tracer.Start(ctx, "WebhookRepository.EnqueueDeliveries")
+	defer span.End()
+
+	query := `
+    INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, body)
+    SELECT id, $1, $2, $3
+    FROM webhooks
+    WHERE disabled_at IS NULL
+    AND ($2 = ANY(event_types) OR $4 = ANY(event_types))
+    ON CONFLICT (webhook_id, event_id) DO NOTHING`
+
+	tag, err := w.Conn.Exec(ctx, query, eventID, string(eventType), body, string(domain.EventAll))
+	if err != nil {
+		span.RecordError(err)
+		return 0, err
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+// ClaimDeliveries returns up to limit deliveries due at now, oldest first,
+// and holds them until lease so other dispatchers skip them. Deliveries of
+// disabled webhooks aren't claimed.
+func (w *WebhookRepository) ClaimDeliveries(ctx context.Context, now, lease time.Time, limit int) ([]domain.PendingDelivery, error) {
+	tracer := otel.Tracer("repo.webhook")
+	ctx, span := tracer.Start(ctx, "WebhookRepository.ClaimDeliveries")
+	defer span.End()
+
+	query := `
+    WITH claimed AS (
+        UPDATE webhook_deliveries
+        SET attempts = attempts + 1,
+        next_attempt_at = $2
+        WHERE id IN (
+            SELECT d.id FROM webhook_deliveries d
+            JOIN webhooks w ON w.id = d.webhook_id AND w.disabled_at IS NULL
+            WHERE d.next_attempt_at <= $1
+            ORDER BY d.created_at, d.id
+            LIMIT $3
+            FOR UPDATE OF d SKIP LOCKED
+        )
+        RETURNING id, webhook_id, event_id, event_type, body, attempts, created_at
+    )
+    SELECT c.id, c.webhook_id, w.url, w.secret, c.event_id, c.event_type, c.body::text, c.attempts
+    FROM claimed c
+    JOIN webhooks w ON w.id = c.webhook_id
+    ORDER BY c.created_at, c.id`
+
+	rows, err := w.Conn.Query(ctx, query, now, lease, limit)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []domain.PendingDelivery
+	for rows.Next() {
+		var delivery domain.PendingDelivery
+		var body string
+		if err := rows.Scan(
+			&delivery.ID,
+			&delivery.WebhookID,
+			&delivery.URL,
+			&delivery.Secret,
+			&delivery.EventID,
+			&delivery.EventType,
+			&body,
+			&delivery.Attempts,
+		); err != nil {
+			span.RecordError(err)
+			return nil, err
+		}
+		delivery.Body = []byte(body)
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, rows.Err()
+}
+
+// MarkDeliverySucceeded records a 2xx response, which also clears the
+// webhook's failure count
+func (w *WebhookRepository) MarkDeliverySucceeded(ctx context.Context, id string, responseStatus int, deliveredAt time.Time) error {
+	return pgx.BeginFunc(ctx, w.Conn, func(tx pgx.Tx) error {
+		query := `
+        UPDATE webhook_deliveries
+        SET status = $2,
+        response_status = $3,
+        delivered_at = $4,
+        next_attempt_at = NULL,
+        last_error = NULL
+        WHERE id = $1
+        RETURNING webhook_id`
+
+		var webhookID string
+		err := tx.QueryRow(ctx, query, id, domain.DeliverySucceeded, responseStatus, deliveredAt).Scan(&webhookID)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(ctx, `UPDATE webhooks SET failure_count = 0 WHERE id = $1 AND failure_count > 0`, webhookID)
+		return err
+	})
+}
+
+// MarkDeliveryFailed records a failed attempt, responseStatus is nil when no
+// response came back. The delivery is tried again at retryAt or never when
+// retryAt is nil. The webhook is disabled once disableAfter attempts in a row
+// failed, its pending deliveries then fail too; disabled tells whether this
+// attempt disabled it.
+func (w *WebhookRepository) MarkDeliveryFailed(
+	ctx context.Context,
+	id string,
+	responseStatus *int,
+	reason string,
+	retryAt *time.Time,
+	disableAfter int,
+) (disabled bool, err error) {
+	status := domain.DeliveryPending
+	if retryAt == nil {
+		status = domain.DeliveryFailed
+	}
+
+	err = pgx.BeginFunc(ctx, w.Conn, func(tx pgx.Tx) error {
+		query := `
+        UPDATE webhook_deliveries
+        SET status = $2,
+        response_status = $3,
+        last_error = $4,
+        next_attempt_at = $5
+        WHERE id = $1
+        RETURNING webhook_id`
+
+		var webhookID string
+		err := tx.QueryRow(ctx, query, id, status, responseStatus, reason, retryAt).Scan(&webhookID)
+		if err != nil {
+			return err
+		}
+
+		query = `
+        UPDATE webhooks
+        SET failure_count = failure_count + 1,
+        disabled_at = CASE
+            WHEN failure_count + 1 >= $2 THEN NOW() AT TIME ZONE 'UTC'
+        END,
+        updated_at = NOW()
+        WHERE id = $1 AND disabled_at IS NULL
+        RETURNING disabled_at IS NOT NULL`
+
+		err = tx.QueryRow(ctx, query, webhookID, disableAfter).Scan(&disabled)
+		if errors.Is(err, pgx.ErrNoRows) {
+			// disabled by a concurrent attempt
+			return nil
+		}
+		if err != nil || !disabled {
+			return err
+		}
+
+		query = `
+        UPDATE webhook_deliveries
+        SET status = $2,
+        last_error = 'webhook disabled after repeated failures',
+        next_attempt_at = NULL
+        WHERE webhook_id = $1 AND status = $3`
+
+		_, err = tx.Exec(ctx, query, webhookID, domain.DeliveryFailed, domain.DeliveryPending)
+		return err
+	})
+	return disabled, err
+}
@@ -2,9 +2,6 @@ package rest
 
 import (
 	"context"
-	"database/sql"
-	"errors"
-	"fmt"
 	"net/http"
 	"zog-news/domain"
 
@@ -17,79 +14,183 @@ import (
 
 type ArticleService interface {
 	CreateArticle(ctx context.Context, article *domain.CreateArticleRequest) (*domain.Article, error)
-	GetArticleList(ctx context.Context, filter *domain.ArticleFilter) ([]domain.Article, error)
+	GetArticleList(ctx context.Context, filter *domain.ArticleFilter) ([]domain.Article, domain.CursorMeta, error)
 	GetArticle(ctx context.Context, id uuid.UUID) (*domain.Article, error)
+	GetArticleBySlug(ctx context.Context, slug string) (*domain.Article, error)
 	UpdateArticle(ctx context.Context, id uuid.UUID, article *domain.Article) (*domain.Article, error)
-	DeleteArticle(ctx context.Context, id uuid.UUID) error
+	DeleteArticle(ctx context.Context, id uuid.UUID, version int) error
+	SearchArticles(ctx context.Context, filter *domain.ArticleSearchFilter) ([]domain.ArticleSearchResult, error)
+
+	SubmitArticle(ctx context.Context, id uuid.UUID) (*domain.Article, error)
+	PublishArticle(ctx context.Context, id uuid.UUID, req *domain.PublishArticleRequest) (*domain.Article, error)
+	ArchiveArticle(ctx context.Context, id uuid.UUID) (*domain.Article, error)
+	RejectArticle(ctx context.Context, id uuid.UUID) (*domain.Article, error)
+
+	GetDeletedArticles(ctx context.Context) ([]domain.Article, error)
+	RestoreArticle(ctx context.Context, id uuid.UUID) (*domain.Article, error)
+	PurgeArticle(ctx context.Context, id uuid.UUID) error
+
+	GetArticleRevisions(ctx context.Context, id uuid.UUID) ([]domain.ArticleRevision, error)
+	GetArticleRevision(ctx context.Context, id uuid.UUID, revision int) (*domain.ArticleRevision, error)
+	DiffArticleRevisions(ctx context.Context, id uuid.UUID, filter *domain.RevisionDiffFilter) (*domain.ArticleRevisionDiff, error)
+	RestoreArticleRevision(ctx context.Context, id uuid.UUID, revision int) (*domain.Article, error)
 
 	GetTopicsByArticleID(ctx context.Context, articleID uuid.UUID) ([]domain.Topic, error)
 	AddTopicToArticle(ctx context.Context, articleID uuid.UUID, topicID string) error
 	RemoveTopicFromArticle(ctx context.Context, articleID uuid.UUID, topicID string) error
+	ReplaceArticleTopics(ctx context.Context, articleID uuid.UUID, topicIDs []string) ([]domain.Topic, error)
+	PatchArticleTopics(ctx context.Context, articleID uuid.UUID, req *domain.PatchArticleTopicsRequest) ([]domain.Topic, error)
+}
+
+// ViewRecorder counts the reads of published articles
+type ViewRecorder interface {
+	Record(articleID string)
 }
 
 type ArticleHandler struct {
 	Service ArticleService
+	// Views is nil when reads aren't counted
+	Views ViewRecorder
 }
 
-func NewArticleHandler(e *echo.Group, svc ArticleService) {
+// NewArticleHandler registers the article routes, write routes go through auth
+// and reads through readAuth, which lets anonymous callers in
+func NewArticleHandler(e *echo.Group, svc ArticleService, auth, readAuth echo.MiddlewareFunc, views ViewRecorder) {
 	handler := &ArticleHandler{
 		Service: svc,
+		Views:   views,
 	}
 	articleGroup := e.Group("/articles") // articles group
 
-	articleGroup.GET("", handler.GetArticleList)
-	articleGroup.GET("/:id", handler.GetArticle)
-	articleGroup.POST("", handler.CreateArticle)
-	articleGroup.PUT("/:id", handler.UpdateArticle)
-	articleGroup.DELETE("/:id", handler.DeleteArticle)
+	articleGroup.GET("", handler.GetArticleList, readAuth)
+	articleGroup.GET("/search", handler.SearchArticles, readAuth)
+	articleGroup.GET("/by-slug/:slug", handler.GetArticleBySlug, readAuth)
+	articleGroup.GET("/:id", handler.GetArticle, readAuth)
+	articleGroup.POST("", handler.CreateArticle, auth)
+	articleGroup.PUT("/:id", handler.UpdateArticle, auth)
+	articleGroup.PATCH("/:id", handler.PatchArticle, auth)
+	articleGroup.DELETE("/:id", handler.DeleteArticle, auth)
+	articleGroup.POST("/:id/restore", handler.RestoreArticle, auth)
+
+	// editorial workflow actions
+	articleGroup.POST("/:id/submit", handler.SubmitArticle, auth)
+	articleGroup.POST("/:id/publish", handler.PublishArticle, auth)
+	articleGroup.POST("/:id/archive", handler.ArchiveArticle, auth)
+	articleGroup.POST("/:id/reject", handler.RejectArticle, auth)
+
+	revisionGroup := articleGroup.Group("/:id/revisions", auth) // revision history
+	revisionGroup.GET("", handler.GetArticleRevisions)
+	revisionGroup.GET("/diff", handler.DiffArticleRevisions)
+	revisionGroup.GET("/:rev", handler.GetArticleRevision)
+	revisionGroup.POST("/:rev/restore", handler.RestoreArticleRevision)
 
 	topicGroup := articleGroup.Group("/:id/topics") // topics group under articles
 	topicGroup.GET("", handler.GetTopicsByArticleID)
-	topicGroup.POST("/:topic_id", handler.AddTopicToArticle)
-	topicGroup.DELETE("/:topic_id", handler.RemoveTopicFromArticle)
+	topicGroup.PUT("", handler.ReplaceArticleTopics, auth)
+	topicGroup.PATCH("", handler.PatchArticleTopics, auth)
+	topicGroup.POST("/:topic_id", handler.AddTopicToArticle, auth)
+	topicGroup.DELETE("/:topic_id", handler.RemoveTopicFromArticle, auth)
 }
 
 // GetArticleList retrieves a list of articles with optional filtering
 //
 //	@Summary		Get articles list
-//	@Description	Get a paginated list of articles with optional filtering by search, status, and topic
+//	@Description	Get a cursor paginated list of articles with optional filtering by search, status, and topic
 //	@Tags			articles
 //	@Accept			json
 //	@Produce		json
 //	@Param			search	query		string										false	"Search in title and content"
-//	@Param			status	query		string										false	"Filter by status"	Enums(draft,published,archived)
-//	@Param			topic	query		string										false	"Filter by topic name"
-//	@Success		200		{object}	domain.ResponseMultipleData[domain.Article]	"Successfully retrieved articles list"
-//	@Failure		500		{object}	domain.ResponseMultipleData[domain.Empty]	"Internal server error"
+//	@Param			status	query		string										false	"Filter by status"	Enums(draft,in_review,published,archived)
+//	@Param			topic	query		string										false	"Filter by topic name or slug"
+//	@Param			limit	query		int											false	"Page size (max 100)"	default(20)
+//	@Param			cursor	query		string										false	"Cursor returned as next_cursor by the previous page"
+//	@Param			sort	query		string										false	"Sort column"		Enums(created_at,updated_at,published_at,title)	default(created_at)
+//	@Param			order	query		string										false	"Sort direction"	Enums(asc,desc)						default(desc)
+//	@Success		200		{object}	domain.ResponseCursorData[domain.Article]	"Successfully retrieved articles list"
+//	@Failure		400		{object}	domain.Problem								"Invalid pagination parameters"
+//	@Failure		422		{object}	domain.Problem								"Invalid status filter"
+//	@Failure		500		{object}	domain.Problem								"Internal server error"
 //	@Router			/articles [get]
 func (h *ArticleHandler) GetArticleList(c echo.Context) error {
 	filter := new(domain.ArticleFilter)
 	if err := c.Bind(filter); err != nil {
-		fmt.Println(err)
+		return badRequest("invalid query parameters")
+	}
+	if err := c.Validate(filter); err != nil {
+		return err
 	}
 
 	ctx := c.Request().Context()
-	articles, err := h.Service.GetArticleList(ctx, filter)
+	articles, meta, err := h.Service.GetArticleList(ctx, filter)
 	if err != nil {
-		fmt.Println(err)
-		return c.JSON(http.StatusInternalServerError, domain.ResponseMultipleData[domain.Empty]{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Failed to list articles: " + err.Error(),
-		})
+		return err
 	}
 	if articles == nil {
 		articles = []domain.Article{}
 	}
+	// lists send the source only, like single reads without ?render=html
+	for i := range articles {
+		articles[i].ContentHTML = ""
+	}
 
-	return c.JSON(http.StatusOK, domain.ResponseMultipleData[domain.Article]{
+	return c.JSON(http.StatusOK, domain.ResponseCursorData[domain.Article]{
 		Data:    articles,
+		Meta:    meta,
 		Code:    http.StatusOK,
 		Status:  "Success",
 		Message: "Successfully retrieve article list",
 	})
 }
 
+// SearchArticles runs a full-text search over articles
+//
+//	@Summary		Search articles
+//	@Description	Full-text search over article titles and content, ranked by relevance with highlighted snippets. Supports "quoted phrases", -negation, OR and prefix* terms
+//	@Tags			articles
+//	@Accept			json
+//	@Produce		json
+//	@Param			q		query		string													true	"Search query"
+//	@Param			status	query		string													false	"Filter by status"	Enums(draft,in_review,published,archived)
+//	@Param			topic	query		string													false	"Filter by topic name or slug"
+//	@Param			limit	query		int														false	"Page size (max 100)"		default(20)
+//	@Param			offset	query		int														false	"Number of results to skip"	default(0)
+//	@Success		200		{object}	domain.ResponseMultipleData[domain.ArticleSearchResult]	"Successfully searched articles"
+//	@Failure		400		{object}	domain.Problem											"Missing search query"
+//	@Failure		422		{object}	domain.Problem											"Invalid status filter"
+//	@Failure		500		{object}	domain.Problem											"Internal server error"
+//	@Router			/articles/search [get]
+func (h *ArticleHandler) SearchArticles(c echo.Context) error {
+	tracer := otel.Tracer("http.handler.article")
+	ctx, span := tracer.Start(c.Request().Context(), "SearchArticlesHandler")
+	defer span.End()
+
+	filter := new(domain.ArticleSearchFilter)
+	if err := c.Bind(filter); err != nil {
+		return badRequest("invalid query parameters")
+	}
+	if err := c.Validate(filter); err != nil {
+		return err
+	}
+
+	span.SetAttributes(attribute.String("search.query", filter.Query))
+	results, err := h.Service.SearchArticles(ctx, filter)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "service error")
+		return err
+	}
+	if results == nil {
+		results = []domain.ArticleSearchResult{}
+	}
+
+	return c.JSON(http.StatusOK, domain.ResponseMultipleData[domain.ArticleSearchResult]{
+		Data:    results,
+		Code:    http.StatusOK,
+		Status:  "success",
+		Message: "Successfully searched articles",
+	})
+}
+
 // GetArticle retrieves a single article by ID
 //
 //	@Summary		Get article by ID
@@ -97,51 +198,47 @@ func (h *ArticleHandler) GetArticleList(c echo.Context) error {
 //	@Tags			articles
 //	@Accept			json
 //	@Produce		json
-//	@Param			id	path		string										true	"Article ID"	format(uuid)
-//	@Success		200	{object}	domain.ResponseSingleData[domain.Article]	"Successfully retrieved article"
-//	@Failure		400	{object}	domain.ResponseSingleData[domain.Empty]		"Invalid article ID format"
-//	@Failure		404	{object}	domain.ResponseSingleData[domain.Empty]		"Article not found"
-//	@Failure		500	{object}	domain.ResponseSingleData[domain.Empty]		"Internal server error"
+//	@Param			id				path		string										true	"Article ID"	format(uuid)
+//	@Param			render			query		string										false	"Also send the sanitized HTML rendered from the content"	Enums(html)
+//	@Param			If-None-Match	header		string										false	"ETag of a cached copy"
+//	@Success		200				{object}	domain.ResponseSingleData[domain.Article]	"Successfully retrieved article"
+//	@Header			200				{string}	ETag										"Current version of the article"
+//	@Success		304				"The cached copy is still current"
+//	@Failure		400				{object}	domain.Problem	"Unknown render option"
+//	@Failure		422				{object}	domain.Problem	"Invalid article ID"
+//	@Failure		404				{object}	domain.Problem	"Article not found"
+//	@Failure		500				{object}	domain.Problem	"Internal server error"
 //	@Router			/articles/{id} [get]
 func (h *ArticleHandler) GetArticle(c echo.Context) error {
 	tracer := otel.Tracer("http.handler.article")
 	ctx, span := tracer.Start(c.Request().Context(), "GetArticleHandler")
 	defer span.End()
 
-	idParam := c.Param("id")
-	id, err := uuid.Parse(idParam)
+	id, err := paramUUID(c, "id")
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, "invalid UUID")
-		return c.JSON(http.StatusBadRequest, domain.ResponseSingleData[domain.Empty]{
-			Code:    http.StatusBadRequest,
-			Status:  "error",
-			Message: "Invalid article ID format",
-		})
+		return err
 	}
 
 	span.SetAttributes(attribute.String("article.id", id.String()))
 	article, err := h.Service.GetArticle(ctx, id)
 	if err != nil {
 		span.RecordError(err)
-		if errors.Is(err, sql.ErrNoRows) {
-			span.SetStatus(codes.Error, "not found")
-			return c.JSON(http.StatusNotFound, domain.ResponseSingleData[domain.Empty]{
-				Code:    http.StatusNotFound,
-				Status:  "error",
-				Message: "Article not found",
-			})
-		}
-
 		span.SetStatus(codes.Error, "service error")
-		fmt.Println("GetArticle error:", err)
-		return c.JSON(http.StatusInternalServerError, domain.ResponseSingleData[domain.Empty]{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Failed to get article: " + err.Error(),
-		})
+		return err
+	}
+	if err := renderArticle(c, article); err != nil {
+		return err
+	}
+	h.recordView(article)
+
+	tag := articleETag(c, article.Version)
+	if notModified(c, tag) {
+		return c.NoContent(http.StatusNotModified)
 	}
 
+	c.Response().Header().Set(headerETag, tag)
 	return c.JSON(http.StatusOK, domain.ResponseSingleData[domain.Article]{
 		Data:    *article,
 		Code:    http.StatusOK,
@@ -150,37 +247,101 @@ func (h *ArticleHandler) GetArticle(c echo.Context) error {
 	})
 }
 
+// GetArticleBySlug retrieves a single article by its slug
+//
+//	@Summary		Get article by slug
+//	@Description	Get a single article by its slug. A slug the article had before a rename answers with a redirect to the current one
+//	@Tags			articles
+//	@Accept			json
+//	@Produce		json
+//	@Param			slug			path		string										true	"Article slug"
+//	@Param			render			query		string										false	"Also send the sanitized HTML rendered from the content"	Enums(html)
+//	@Param			If-None-Match	header		string										false	"ETag of a cached copy"
+//	@Success		200				{object}	domain.ResponseSingleData[domain.Article]	"Successfully retrieved article"
+//	@Header			200				{string}	ETag										"Current version of the article"
+//	@Success		301				"The article moved to the slug in Location"
+//	@Success		304				"The cached copy is still current"
+//	@Failure		400				{object}	domain.Problem	"Unknown render option"
+//	@Failure		404				{object}	domain.Problem	"Article not found"
+//	@Failure		500				{object}	domain.Problem	"Internal server error"
+//	@Router			/articles/by-slug/{slug} [get]
+func (h *ArticleHandler) GetArticleBySlug(c echo.Context) error {
+	slug := c.Param("slug")
+
+	ctx := c.Request().Context()
+	article, err := h.Service.GetArticleBySlug(ctx, slug)
+	if err != nil {
+		return err
+	}
+
+	if article.Slug != slug {
+		return c.Redirect(http.StatusMovedPermanently, slugLocation(c, article.Slug))
+	}
+	if err := renderArticle(c, article); err != nil {
+		return err
+	}
+	h.recordView(article)
+	tag := articleETag(c, article.Version)
+	if notModified(c, tag) {
+		return c.NoContent(http.StatusNotModified)
+	}
+
+	c.Response().Header().Set(headerETag, tag)
+	return c.JSON(http.StatusOK, domain.ResponseSingleData[domain.Article]{
+		Data:    *article,
+		Code:    http.StatusOK,
+		Status:  "success",
+		Message: "Successfully retrieved article",
+	})
+}
+
+// recordView counts a read of a published article, a reader revalidating a
+// cached copy read it again too
+func (h *ArticleHandler) recordView(article *domain.Article) {
+	if h.Views != nil && article.Status == domain.StatusPublished {
+		h.Views.Record(article.ID)
+	}
+}
+
+// renderArticle applies the render query parameter of the article reads,
+// the rendered HTML is only sent with render=html
+func renderArticle(c echo.Context, article *domain.Article) error {
+	switch c.QueryParam("render") {
+	case "":
+		article.ContentHTML = ""
+	case "html":
+	default:
+		return badRequest("render must be html")
+	}
+	return nil
+}
+
 // CreateArticle creates a new article
 //
 //	@Summary		Create new article
-//	@Description	Create a new article with the provided information
+//	@Description	Create a new draft article, optionally attaching topics by ID or by name in the same transaction
 //	@Tags			articles
 //	@Accept			json
 //	@Produce		json
 //	@Param			article	body		domain.CreateArticleRequest					true	"Article creation data"
 //	@Success		201		{object}	domain.ResponseSingleData[domain.Article]	"Article successfully created"
-//	@Failure		400		{object}	domain.ResponseSingleData[domain.Empty]		"Invalid request payload"
-//	@Failure		500		{object}	domain.ResponseSingleData[domain.Empty]		"Internal server error"
+//	@Failure		400		{object}	domain.Problem								"Malformed request payload"
+//	@Failure		422		{object}	domain.Problem								"Invalid request fields"
+//	@Failure		401		{object}	domain.Problem								"Missing or invalid access token"
+//	@Failure		403		{object}	domain.Problem								"Not allowed for the caller's role"
+//	@Failure		500		{object}	domain.Problem								"Internal server error"
+//	@Security		BearerAuth
 //	@Router			/articles [post]
 func (h *ArticleHandler) CreateArticle(c echo.Context) error {
 	var article domain.CreateArticleRequest
-	if err := c.Bind(&article); err != nil {
-		return c.JSON(http.StatusBadRequest, domain.ResponseSingleData[domain.Empty]{
-			Code:    http.StatusBadRequest,
-			Status:  "error",
-			Message: "Invalid request payload",
-		})
+	if err := bind(c, &article); err != nil {
+		return err
 	}
 
 	ctx := c.Request().Context()
 	createdArticle, err := h.Service.CreateArticle(ctx, &article)
 	if err != nil {
-		fmt.Println("CreateArticle error:", err)
-		return c.JSON(http.StatusInternalServerError, domain.ResponseSingleData[domain.Empty]{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Failed to create article: " + err.Error(),
-		})
+		return err
 	}
 
 	return c.JSON(http.StatusCreated, domain.ResponseSingleData[domain.Article]{
@@ -194,47 +355,131 @@ func (h *ArticleHandler) CreateArticle(c echo.Context) error {
 // UpdateArticle updates an existing article
 //
 //	@Summary		Update article
-//	@Description	Update an existing article by ID with new information
+//	@Description	Update an existing article by ID with new information. Sending topic_ids or topic_names replaces the article's topics, sending attachment_ids replaces its attachments. Leaving out cover_media_id keeps the cover and an empty one removes it
 //	@Tags			articles
 //	@Accept			json
 //	@Produce		json
-//	@Param			id		path		string										true	"Article ID"	format(uuid)
-//	@Param			article	body		domain.UpdateArticleRequest					true	"Article update data"
-//	@Success		200		{object}	domain.ResponseSingleData[domain.Article]	"Article successfully updated"
-//	@Failure		400		{object}	domain.ResponseSingleData[domain.Empty]		"Invalid request payload or article ID"
-//	@Failure		500		{object}	domain.ResponseSingleData[domain.Empty]		"Internal server error"
+//	@Param			id			path		string										true	"Article ID"	format(uuid)
+//	@Param			article		body		domain.UpdateArticleRequest					true	"Article update data"
+//	@Param			If-Match	header		string										false	"ETag the update is based on"
+//	@Success		200			{object}	domain.ResponseSingleData[domain.Article]	"Article successfully updated"
+//	@Header			200			{string}	ETag										"New version of the article"
+//	@Failure		400			{object}	domain.Problem								"Malformed request payload"
+//	@Failure		422			{object}	domain.Problem								"Invalid request fields or article ID"
+//	@Failure		401			{object}	domain.Problem								"Missing or invalid access token"
+//	@Failure		403			{object}	domain.Problem								"Not allowed for the caller's role"
+//	@Failure		404			{object}	domain.Problem								"Article not found"
+//	@Failure		409			{object}	domain.Problem								"Status changes must use the workflow actions"
+//	@Failure		412			{object}	domain.Problem								"The article changed since it was read"
+//	@Failure		500			{object}	domain.Problem								"Internal server error"
+//	@Security		BearerAuth
 //	@Router			/articles/{id} [put]
 func (h *ArticleHandler) UpdateArticle(c echo.Context) error {
-	idParam := c.Param("id")
-	id, err := uuid.Parse(idParam)
+	id, err := paramUUID(c, "id")
+	if err != nil {
+		return err
+	}
+
+	var req domain.UpdateArticleRequest
+	if err := bind(c, &req); err != nil {
+		return err
+	}
+
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		return err
+	}
+
+	return h.saveArticle(c, id, &req, version)
+}
+
+// PatchArticle changes only the fields sent in a JSON merge patch
+//
+//	@Summary		Patch article
+//	@Description	Change some fields of an article with a JSON merge patch (RFC 7396). Members left out keep their value and null removes them. Sending topic_ids or topic_names replaces the article's topics, sending attachment_ids replaces its attachments
+//	@Tags			articles
+//	@Accept			application/merge-patch+json
+//	@Produce		json
+//	@Param			id			path		string										true	"Article ID"	format(uuid)
+//	@Param			article		body		domain.UpdateArticleRequest					true	"Fields to change"
+//	@Param			If-Match	header		string										false	"ETag the patch is based on"
+//	@Success		200			{object}	domain.ResponseSingleData[domain.Article]	"Article successfully updated"
+//	@Header			200			{string}	ETag										"New version of the article"
+//	@Failure		400			{object}	domain.Problem								"Malformed merge patch or unknown topics"
+//	@Failure		401			{object}	domain.Problem								"Missing or invalid access token"
+//	@Failure		403			{object}	domain.Problem								"Not allowed for the caller's role"
+//	@Failure		404			{object}	domain.Problem								"Article not found"
+//	@Failure		409			{object}	domain.Problem								"Status changes must use the workflow actions"
+//	@Failure		412			{object}	domain.Problem								"The article changed since it was read"
+//	@Failure		415			{object}	domain.Problem								"Body is not a merge patch"
+//	@Failure		422			{object}	domain.Problem								"Invalid fields after applying the patch or article ID"
+//	@Failure		500			{object}	domain.Problem								"Internal server error"
+//	@Security		BearerAuth
+//	@Router			/articles/{id} [patch]
+func (h *ArticleHandler) PatchArticle(c echo.Context) error {
+	id, err := paramUUID(c, "id")
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, domain.ResponseSingleData[domain.Empty]{
-			Code:    http.StatusBadRequest,
-			Status:  "error",
-			Message: "Invalid article ID format",
-		})
+		return err
 	}
 
-	var article domain.Article
-	if err := c.Bind(&article); err != nil {
-		return c.JSON(http.StatusBadRequest, domain.ResponseSingleData[domain.Empty]{
-			Code:    http.StatusBadRequest,
-			Status:  "error",
-			Message: "Invalid request payload",
-		})
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	current, err := h.Service.GetArticle(ctx, id)
+	if err != nil {
+		return err
+	}
+	if version == 0 {
+		// the patch applies to what was just read, a concurrent change fails
+		version = current.Version
+	}
+
+	// topics and attachments are left out so they are only replaced when the
+	// patch sends them
+	req := domain.UpdateArticleRequest{
+		Title:         current.Title,
+		Content:       current.Content,
+		Status:        current.Status,
+		ContentFormat: current.ContentFormat,
+		CoverMediaID:  current.CoverMediaID,
+	}
+	if err := bindMergePatch(c, &req); err != nil {
+		return err
+	}
+	if req.CoverMediaID == nil && current.CoverMediaID != nil {
+		// null removed the cover, an empty one tells the service so
+		removed := ""
+		req.CoverMediaID = &removed
+	}
+
+	return h.saveArticle(c, id, &req, version)
+}
+
+// saveArticle applies a full update of an article, a non-zero version must
+// match the stored one
+func (h *ArticleHandler) saveArticle(c echo.Context, id uuid.UUID, req *domain.UpdateArticleRequest, version int) error {
+	article := domain.Article{
+		Title:         req.Title,
+		Content:       req.Content,
+		ContentFormat: req.ContentFormat,
+		Status:        req.Status,
+		TopicIDs:      req.TopicIDs,
+		TopicNames:    req.TopicNames,
+		CoverMediaID:  req.CoverMediaID,
+		AttachmentIDs: req.AttachmentIDs,
+		Version:       version,
 	}
 
 	ctx := c.Request().Context()
 	updatedArticle, err := h.Service.UpdateArticle(ctx, id, &article)
 	if err != nil {
-		fmt.Println("UpdateArticle error:", err)
-		return c.JSON(http.StatusInternalServerError, domain.ResponseSingleData[domain.Empty]{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Failed to update article: " + err.Error(),
-		})
+		return err
 	}
 
+	c.Response().Header().Set(headerETag, etag(updatedArticle.Version))
 	return c.JSON(http.StatusOK, domain.ResponseSingleData[domain.Article]{
 		Data:    *updatedArticle,
 		Code:    http.StatusOK,
@@ -250,37 +495,175 @@ func (h *ArticleHandler) UpdateArticle(c echo.Context) error {
 //	@Tags			articles
 //	@Accept			json
 //	@Produce		json
-//	@Param			id	path		string									true	"Article ID"	format(uuid)
-//	@Success		204	{object}	domain.ResponseSingleData[domain.Empty]	"Article successfully deleted"
-//	@Failure		400	{object}	domain.ResponseSingleData[domain.Empty]	"Invalid article ID format"
-//	@Failure		404	{object}	domain.ResponseSingleData[domain.Empty]	"Article not found"
-//	@Failure		500	{object}	domain.ResponseSingleData[domain.Empty]	"Internal server error"
+//	@Param			id			path		string									true	"Article ID"	format(uuid)
+//	@Param			If-Match	header		string									false	"ETag the deletion is based on"
+//	@Success		204			{object}	domain.ResponseSingleData[domain.Empty]	"Article successfully deleted"
+//	@Failure		422			{object}	domain.Problem							"Invalid article ID"
+//	@Failure		404			{object}	domain.Problem							"Article not found"
+//	@Failure		401			{object}	domain.Problem							"Missing or invalid access token"
+//	@Failure		403			{object}	domain.Problem							"Not allowed for the caller's role"
+//	@Failure		412			{object}	domain.Problem							"The article changed since it was read"
+//	@Failure		500			{object}	domain.Problem							"Internal server error"
+//	@Security		BearerAuth
 //	@Router			/articles/{id} [delete]
 func (h *ArticleHandler) DeleteArticle(c echo.Context) error {
-	idParam := c.Param("id")
-	id, err := uuid.Parse(idParam)
+	id, err := paramUUID(c, "id")
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, domain.ResponseSingleData[domain.Empty]{
-			Code:    http.StatusBadRequest,
-			Status:  "error",
-			Message: "Invalid article ID format",
-		})
+		return err
+	}
+
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		return err
 	}
 
 	ctx := c.Request().Context()
-	if err := h.Service.DeleteArticle(ctx, id); err != nil {
-		fmt.Println("DeleteArticle error:", err)
-		return c.JSON(http.StatusInternalServerError, domain.ResponseSingleData[domain.Empty]{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Failed to delete article: " + err.Error(),
-		})
+	if err := h.Service.DeleteArticle(ctx, id, version); err != nil {
+		return err
 	}
 
-	return c.JSON(http.StatusNoContent, domain.ResponseSingleData[domain.Empty]{
-		Code:    http.StatusNoContent,
+	return c.NoContent(http.StatusNoContent)
+}
+
+// RestoreArticle takes an article out of the trash
+//
+//	@Summary		Restore deleted article
+//	@Description	Restore a soft-deleted article together with its topics. Editors only
+//	@Tags			trash
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string										true	"Article ID"	format(uuid)
+//	@Success		200	{object}	domain.ResponseSingleData[domain.Article]	"Article successfully restored"
+//	@Failure		422	{object}	domain.Problem								"Invalid article ID"
+//	@Failure		401	{object}	domain.Problem								"Missing or invalid access token"
+//	@Failure		403	{object}	domain.Problem								"Not allowed for the caller's role"
+//	@Failure		404	{object}	domain.Problem								"Article not in the trash"
+//	@Failure		500	{object}	domain.Problem								"Internal server error"
+//	@Security		BearerAuth
+//	@Router			/articles/{id}/restore [post]
+func (h *ArticleHandler) RestoreArticle(c echo.Context) error {
+	return h.transitionArticle(c, "Article successfully restored", h.Service.RestoreArticle)
+}
+
+// SubmitArticle sends a draft article for review
+//
+//	@Summary		Submit article for review
+//	@Description	Move a draft article to in_review. Authors may submit their own drafts
+//	@Tags			articles
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string										true	"Article ID"	format(uuid)
+//	@Success		200	{object}	domain.ResponseSingleData[domain.Article]	"Article submitted for review"
+//	@Failure		422	{object}	domain.Problem								"Invalid article ID"
+//	@Failure		401	{object}	domain.Problem								"Missing or invalid access token"
+//	@Failure		403	{object}	domain.Problem								"Not allowed for the caller's role"
+//	@Failure		404	{object}	domain.Problem								"Article not found"
+//	@Failure		409	{object}	domain.Problem								"Article is not a draft"
+//	@Failure		500	{object}	domain.Problem								"Internal server error"
+//	@Security		BearerAuth
+//	@Router			/articles/{id}/submit [post]
+func (h *ArticleHandler) SubmitArticle(c echo.Context) error {
+	return h.transitionArticle(c, "Article submitted for review", h.Service.SubmitArticle)
+}
+
+// PublishArticle publishes a reviewed article now or at a scheduled time
+//
+//	@Summary		Publish article
+//	@Description	Publish an article in review. When scheduled_at is in the future the article stays in review and is published by the scheduler at that time
+//	@Tags			articles
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string										true	"Article ID"	format(uuid)
+//	@Param			publish	body		domain.PublishArticleRequest				false	"Optional publishing time"
+//	@Success		200		{object}	domain.ResponseSingleData[domain.Article]	"Article published or scheduled"
+//	@Failure		400		{object}	domain.Problem								"Malformed request payload"
+//	@Failure		422		{object}	domain.Problem								"Invalid request fields or article ID"
+//	@Failure		401		{object}	domain.Problem								"Missing or invalid access token"
+//	@Failure		403		{object}	domain.Problem								"Not allowed for the caller's role"
+//	@Failure		404		{object}	domain.Problem								"Article not found"
+//	@Failure		409		{object}	domain.Problem								"Article is not in review"
+//	@Failure		500		{object}	domain.Problem								"Internal server error"
+//	@Security		BearerAuth
+//	@Router			/articles/{id}/publish [post]
+func (h *ArticleHandler) PublishArticle(c echo.Context) error {
+	var req domain.PublishArticleRequest
+	if c.Request().ContentLength != 0 {
+		if err := bind(c, &req); err != nil {
+			return err
+		}
+	}
+
+	return h.transitionArticle(c, "Article successfully published", func(ctx context.Context, id uuid.UUID) (*domain.Article, error) {
+		return h.Service.PublishArticle(ctx, id, &req)
+	})
+}
+
+// ArchiveArticle archives a published article
+//
+//	@Summary		Archive article
+//	@Description	Move a published article to archived
+//	@Tags			articles
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string										true	"Article ID"	format(uuid)
+//	@Success		200	{object}	domain.ResponseSingleData[domain.Article]	"Article successfully archived"
+//	@Failure		422	{object}	domain.Problem								"Invalid article ID"
+//	@Failure		401	{object}	domain.Problem								"Missing or invalid access token"
+//	@Failure		403	{object}	domain.Problem								"Not allowed for the caller's role"
+//	@Failure		404	{object}	domain.Problem								"Article not found"
+//	@Failure		409	{object}	domain.Problem								"Article is not published"
+//	@Failure		500	{object}	domain.Problem								"Internal server error"
+//	@Security		BearerAuth
+//	@Router			/articles/{id}/archive [post]
+func (h *ArticleHandler) ArchiveArticle(c echo.Context) error {
+	return h.transitionArticle(c, "Article successfully archived", h.Service.ArchiveArticle)
+}
+
+// RejectArticle sends an article in review back to draft
+//
+//	@Summary		Reject article
+//	@Description	Move an article in review back to draft, clearing any scheduled publishing time
+//	@Tags			articles
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string										true	"Article ID"	format(uuid)
+//	@Success		200	{object}	domain.ResponseSingleData[domain.Article]	"Article sent back to draft"
+//	@Failure		422	{object}	domain.Problem								"Invalid article ID"
+//	@Failure		401	{object}	domain.Problem								"Missing or invalid access token"
+//	@Failure		403	{object}	domain.Problem								"Not allowed for the caller's role"
+//	@Failure		404	{object}	domain.Problem								"Article not found"
+//	@Failure		409	{object}	domain.Problem								"Article is not in review"
+//	@Failure		500	{object}	domain.Problem								"Internal server error"
+//	@Security		BearerAuth
+//	@Router			/articles/{id}/reject [post]
+func (h *ArticleHandler) RejectArticle(c echo.Context) error {
+	return h.transitionArticle(c, "Article sent back to draft", h.Service.RejectArticle)
+}
+
+// transitionArticle runs a workflow or restore action and writes its
+// response, the actions only differ in the service call and the success message
+func (h *ArticleHandler) transitionArticle(
+	c echo.Context,
+	message string,
+	action func(ctx context.Context, id uuid.UUID) (*domain.Article, error),
+) error {
+	id, err := paramUUID(c, "id")
+	if err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	article, err := action(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	c.Response().Header().Set(headerETag, etag(article.Version))
+	return c.JSON(http.StatusOK, domain.ResponseSingleData[domain.Article]{
+		Data:    *article,
+		Code:    http.StatusOK,
 		Status:  "success",
-		Message: "Article successfully deleted",
+		Message: message,
 	})
 }
 
@@ -293,27 +676,18 @@ func (h *ArticleHandler) DeleteArticle(c echo.Context) error {
 //	@Produce		json
 //	@Param			id	path		string										true	"Article ID"	format(uuid)
 //	@Success		200	{object}	domain.ResponseMultipleData[domain.Topic]	"Successfully retrieved topics for article"
-//	@Failure		400	{object}	domain.ResponseSingleData[domain.Empty]		"Invalid article ID format"
-//	@Failure		500	{object}	domain.ResponseSingleData[domain.Empty]		"Internal server error"
+//	@Failure		422	{object}	domain.Problem								"Invalid article ID"
+//	@Failure		500	{object}	domain.Problem								"Internal server error"
 //	@Router			/articles/{id}/topics [get]
 func (h *ArticleHandler) GetTopicsByArticleID(c echo.Context) error {
-	idParam := c.Param("id")
-	id, err := uuid.Parse(idParam)
+	id, err := paramUUID(c, "id")
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, domain.ResponseSingleData[domain.Empty]{
-			Code:    http.StatusBadRequest,
-			Status:  "error",
-			Message: "Invalid article ID format",
-		})
+		return err
 	}
 	ctx := c.Request().Context()
 	topics, err := h.Service.GetTopicsByArticleID(ctx, id)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, domain.ResponseSingleData[domain.Empty]{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Failed to get topics for article: " + err.Error(),
-		})
+		return err
 	}
 	if topics == nil {
 		topics = []domain.Topic{}
@@ -327,6 +701,92 @@ func (h *ArticleHandler) GetTopicsByArticleID(c echo.Context) error {
 	})
 }
 
+// ReplaceArticleTopics replaces every topic of an article
+//
+//	@Summary		Replace article topics
+//	@Description	Replace the full topic set of an article in one transaction. Every topic must exist and not be deleted
+//	@Tags			articles
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string										true	"Article ID"	format(uuid)
+//	@Param			topics	body		domain.ReplaceArticleTopicsRequest			true	"New topic set"
+//	@Success		200		{object}	domain.ResponseMultipleData[domain.Topic]	"Topics successfully replaced"
+//	@Failure		400		{object}	domain.Problem								"Malformed request payload or unknown topics"
+//	@Failure		422		{object}	domain.Problem								"Invalid request fields or article ID"
+//	@Failure		401		{object}	domain.Problem								"Missing or invalid access token"
+//	@Failure		403		{object}	domain.Problem								"Not allowed for the caller's role"
+//	@Failure		404		{object}	domain.Problem								"Article not found"
+//	@Failure		500		{object}	domain.Problem								"Internal server error"
+//	@Security		BearerAuth
+//	@Router			/articles/{id}/topics [put]
+func (h *ArticleHandler) ReplaceArticleTopics(c echo.Context) error {
+	var req domain.ReplaceArticleTopicsRequest
+	if err := bind(c, &req); err != nil {
+		return err
+	}
+
+	return h.changeArticleTopics(c, "Topics successfully replaced", func(ctx context.Context, id uuid.UUID) ([]domain.Topic, error) {
+		return h.Service.ReplaceArticleTopics(ctx, id, req.TopicIDs)
+	})
+}
+
+// PatchArticleTopics adds and removes topics of an article
+//
+//	@Summary		Change article topics
+//	@Description	Add and remove topics of an article in one transaction. Every added topic must exist and not be deleted
+//	@Tags			articles
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string										true	"Article ID"	format(uuid)
+//	@Param			topics	body		domain.PatchArticleTopicsRequest			true	"Topics to add and remove"
+//	@Success		200		{object}	domain.ResponseMultipleData[domain.Topic]	"Topics successfully changed"
+//	@Failure		400		{object}	domain.Problem								"Malformed request payload or unknown topics"
+//	@Failure		422		{object}	domain.Problem								"Invalid request fields or article ID"
+//	@Failure		401		{object}	domain.Problem								"Missing or invalid access token"
+//	@Failure		403		{object}	domain.Problem								"Not allowed for the caller's role"
+//	@Failure		404		{object}	domain.Problem								"Article not found"
+//	@Failure		500		{object}	domain.Problem								"Internal server error"
+//	@Security		BearerAuth
+//	@Router			/articles/{id}/topics [patch]
+func (h *ArticleHandler) PatchArticleTopics(c echo.Context) error {
+	var req domain.PatchArticleTopicsRequest
+	if err := bind(c, &req); err != nil {
+		return err
+	}
+
+	return h.changeArticleTopics(c, "Topics successfully changed", func(ctx context.Context, id uuid.UUID) ([]domain.Topic, error) {
+		return h.Service.PatchArticleTopics(ctx, id, &req)
+	})
+}
+
+// changeArticleTopics runs a bulk topic change and writes the resulting topics
+func (h *ArticleHandler) changeArticleTopics(
+	c echo.Context,
+	message string,
+	change func(ctx context.Context, id uuid.UUID) ([]domain.Topic, error),
+) error {
+	id, err := paramUUID(c, "id")
+	if err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	topics, err := change(ctx, id)
+	if err != nil {
+		return err
+	}
+	if topics == nil {
+		topics = []domain.Topic{}
+	}
+
+	return c.JSON(http.StatusOK, domain.ResponseMultipleData[domain.Topic]{
+		Data:    topics,
+		Code:    http.StatusOK,
+		Status:  "success",
+		Message: message,
+	})
+}
+
 // AddTopicToArticle adds a topic to an article
 //
 //	@Summary		Add topic to article
@@ -337,52 +797,34 @@ func (h *ArticleHandler) GetTopicsByArticleID(c echo.Context) error {
 //	@Param			id			path		string										true	"Article ID"	format(uuid)
 //	@Param			topic_id	path		string										true	"Topic ID"		format(uuid)
 //	@Success		200			{object}	domain.ResponseSingleData[domain.Article]	"Topic successfully added to article"
-//	@Failure		400			{object}	domain.ResponseSingleData[domain.Empty]		"Invalid article ID or topic ID"
-//	@Failure		404			{object}	domain.ResponseSingleData[domain.Empty]		"Article not found"
-//	@Failure		500			{object}	domain.ResponseSingleData[domain.Empty]		"Internal server error"
+//	@Header			200			{string}	ETag										"New version of the article"
+//	@Failure		422			{object}	domain.Problem								"Invalid article ID or topic ID"
+//	@Failure		404			{object}	domain.Problem								"Article not found"
+//	@Failure		401			{object}	domain.Problem								"Missing or invalid access token"
+//	@Failure		403			{object}	domain.Problem								"Not allowed for the caller's role"
+//	@Failure		500			{object}	domain.Problem								"Internal server error"
+//	@Security		BearerAuth
 //	@Router			/articles/{id}/topics/{topic_id} [post]
 func (h *ArticleHandler) AddTopicToArticle(c echo.Context) error {
-	idParam := c.Param("id")
-	id, err := uuid.Parse(idParam)
+	id, err := paramUUID(c, "id")
+	if err != nil {
+		return err
+	}
+	topicID, err := paramUUID(c, "topic_id")
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, domain.ResponseSingleData[domain.Empty]{
-			Code:    http.StatusBadRequest,
-			Status:  "error",
-			Message: "Invalid article ID format",
-		})
-	}
-	topicID := c.Param("topic_id")
-	if topicID == "" {
-		return c.JSON(http.StatusBadRequest, domain.ResponseSingleData[domain.Empty]{
-			Code:    http.StatusBadRequest,
-			Status:  "error",
-			Message: "Topic ID is required",
-		})
+		return err
 	}
 	ctx := c.Request().Context()
+	if err := h.Service.AddTopicToArticle(ctx, id, topicID.String()); err != nil {
+		return err
+	}
+
+	// linking bumps the version, send the article as it is now
 	article, err := h.Service.GetArticle(ctx, id)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, domain.ResponseSingleData[domain.Empty]{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Failed to get article: " + err.Error(),
-		})
-	}
-	if article == nil {
-		return c.JSON(http.StatusNotFound, domain.ResponseSingleData[domain.Empty]{
-			Code:    http.StatusNotFound,
-			Status:  "error",
-			Message: "Article not found",
-		})
-	}
-	if err := h.Service.AddTopicToArticle(ctx, id, topicID); err != nil {
-		return c.JSON(http.StatusInternalServerError, domain.ResponseSingleData[domain.Empty]{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Failed to update article with new topic: " + err.Error(),
-		})
-	}
-	// TODO: return article with topics??
+		return err
+	}
+	c.Response().Header().Set(headerETag, etag(article.Version))
 	return c.JSON(http.StatusOK, domain.ResponseSingleData[domain.Article]{
 		Data:    *article,
 		Code:    http.StatusOK,
@@ -398,57 +840,40 @@ func (h *ArticleHandler) AddTopicToArticle(c echo.Context) error {
 //	@Tags			articles
 //	@Accept			json
 //	@Produce		json
-//	@Param			id			path		string									true	"Article ID"	format(uuid)
-//	@Param			topic_id	path		string									true	"Topic ID"		format(uuid)
-//	@Success		204			{object}	domain.ResponseSingleData[domain.Empty]	"Topic successfully removed from article"
-//	@Failure		400			{object}	domain.ResponseSingleData[domain.Empty]	"Invalid article ID or topic ID"
-//	@Failure		404			{object}	domain.ResponseSingleData[domain.Empty]	"Article not found"
-//	@Failure		500			{object}	domain.ResponseSingleData[domain.Empty]	"Internal server error"
+//	@Param			id			path		string										true	"Article ID"	format(uuid)
+//	@Param			topic_id	path		string										true	"Topic ID"		format(uuid)
+//	@Success		200			{object}	domain.ResponseSingleData[domain.Article]	"Topic successfully removed from article"
+//	@Header			200			{string}	ETag										"New version of the article"
+//	@Failure		422			{object}	domain.Problem								"Invalid article ID or topic ID"
+//	@Failure		404			{object}	domain.Problem								"Article not found"
+//	@Failure		401			{object}	domain.Problem								"Missing or invalid access token"
+//	@Failure		403			{object}	domain.Problem								"Not allowed for the caller's role"
+//	@Failure		500			{object}	domain.Problem								"Internal server error"
+//	@Security		BearerAuth
 //	@Router			/articles/{id}/topics/{topic_id} [delete]
 func (h *ArticleHandler) RemoveTopicFromArticle(c echo.Context) error {
-	idParam := c.Param("id")
-	id, err := uuid.Parse(idParam)
+	id, err := paramUUID(c, "id")
+	if err != nil {
+		return err
+	}
+	topicID, err := paramUUID(c, "topic_id")
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, domain.ResponseSingleData[domain.Empty]{
-			Code:    http.StatusBadRequest,
-			Status:  "error",
-			Message: "Invalid article ID format",
-		})
-	}
-	topicID := c.Param("topic_id")
-	if topicID == "" {
-		return c.JSON(http.StatusBadRequest, domain.ResponseSingleData[domain.Empty]{
-			Code:    http.StatusBadRequest,
-			Status:  "error",
-			Message: "Topic ID is required",
-		})
+		return err
 	}
 	ctx := c.Request().Context()
+	if err := h.Service.RemoveTopicFromArticle(ctx, id, topicID.String()); err != nil {
+		return err
+	}
+
 	article, err := h.Service.GetArticle(ctx, id)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, domain.ResponseSingleData[domain.Empty]{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Failed to get article: " + err.Error(),
-		})
-	}
-	if article == nil {
-		return c.JSON(http.StatusNotFound, domain.ResponseSingleData[domain.Empty]{
-			Code:    http.StatusNotFound,
-			Status:  "error",
-			Message: "Article not found",
-		})
-	}
-	if err := h.Service.RemoveTopicFromArticle(ctx, id, topicID); err != nil {
-		return c.JSON(http.StatusInternalServerError, domain.ResponseSingleData[domain.Empty]{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Failed to update article with removed topic: " + err.Error(),
-		})
-	}
-	return c.JSON(http.StatusNoContent, domain.ResponseSingleData[domain.Empty]{
-		Code:    http.StatusNoContent,
+		return err
+	}
+	c.Response().Header().Set(headerETag, etag(article.Version))
+	return c.JSON(http.StatusOK, domain.ResponseSingleData[domain.Article]{
+		Data:    *article,
+		Code:    http.StatusOK,
 		Status:  "success",
-		Message: "Article successfully deleted",
+		Message: "Topic successfully removed from article",
 	})
 }
@@ -2,14 +2,20 @@ package rest_test
 
 import (
     "bytes"
-    "database/sql"
+    "context"
     "encoding/json"
+    "fmt"
     "net/http"
     "net/http/httptest"
+    "strings"
+    "sync"
     "testing"
+    "time"
     "zog-news/domain"
     "zog-news/internal/rest"
+    "zog-news/internal/rest/middleware"
     "zog-news/internal/rest/mocks"
+    "zog-news/service"
 
     "github.com/google/uuid"
     "github.com/labstack/echo/v4"
@@ -26,6 +32,7 @@ func TestArticleHappyPath(t *testing.T) {
     newArticle := domain.Article{
         ID:    "d4b8583d-5038-4838-bcd7-3d8dddfedd6a",
         Title: "Test judul",
+        AuthorID: "7f1c2a9e-3b4d-4c5e-8f6a-9b0c1d2e3f4a",
         Author: "John Doe",
         Content: "Test content",
         Status: "draft",
@@ -33,10 +40,15 @@ func TestArticleHappyPath(t *testing.T) {
 
     // data for update
     updatedArticle := newArticle
-    updatedArticle.Author = "Jane Doe"
     updatedArticle.Title = "Test judul 2"
     updatedArticle.Content = "Test content update"
-    updatedArticle.Status = "published"
+    updatedArticle.Version = 2
+
+    // data for publishing
+    scheduledAt := time.Date(2030, 1, 2, 8, 0, 0, 0, time.UTC)
+    scheduledArticle := updatedArticle
+    scheduledArticle.Status = domain.StatusInReview
+    scheduledArticle.ScheduledAt = &scheduledAt
 
     handler := rest.ArticleHandler{
         Service: mockArticleService,
@@ -46,9 +58,7 @@ func TestArticleHappyPath(t *testing.T) {
     t.Run("CreateArticle", func(t *testing.T) {
         createReq := domain.CreateArticleRequest{
             Title: newArticle.Title,
-            Author: newArticle.Author,
             Content: newArticle.Content,
-            Status: newArticle.Status,
         }
         mockArticleService.
             On("CreateArticle", mock.Anything, &createReq).
@@ -58,7 +68,7 @@ func TestArticleHappyPath(t *testing.T) {
         body, err := json.Marshal(createReq)
         require.NoError(t, err)
 
-        e := echo.New()
+        e := newEcho()
         req := httptest.NewRequest(http.MethodPost, "/api/v1/articles", bytes.NewReader(body))
         req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
         rec := httptest.NewRecorder()
@@ -86,7 +96,6 @@ func TestArticleHappyPath(t *testing.T) {
         mockArticleService.
             On("UpdateArticle", mock.Anything, id, mock.MatchedBy(func(u *domain.Article) bool {
                 return u.Title == updatedArticle.Title &&
-                u.Author == updatedArticle.Author &&
                 u.Content == updatedArticle.Content &&
                 u.Status == updatedArticle.Status
             })).
@@ -96,7 +105,7 @@ func TestArticleHappyPath(t *testing.T) {
         body, err := json.Marshal(updatedArticle)
         require.NoError(t, err)
 
-        e := echo.New()
+        e := newEcho()
         req := httptest.NewRequest(http.MethodPut, "/api/v1/articles/"+newArticle.ID, bytes.NewReader(body))
         req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
         rec := httptest.NewRecorder()
@@ -118,6 +127,43 @@ func TestArticleHappyPath(t *testing.T) {
         mockArticleService.AssertExpectations(t)
     })
 
+    // --- Schedule Article
+    t.Run("PublishArticle", func(t *testing.T) {
+        id, err := uuid.Parse(newArticle.ID)
+        require.NoError(t, err)
+        mockArticleService.
+            On("PublishArticle", mock.Anything, id, mock.MatchedBy(func(r *domain.PublishArticleRequest) bool {
+                return r.ScheduledAt != nil && r.ScheduledAt.Equal(scheduledAt)
+            })).
+            Return(&scheduledArticle, nil).
+            Once()
+
+        body := []byte(`{"scheduled_at": "2030-01-02T08:00:00Z"}`)
+
+        e := newEcho()
+        req := httptest.NewRequest(http.MethodPost, "/api/v1/articles/"+newArticle.ID+"/publish", bytes.NewReader(body))
+        req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+        rec := httptest.NewRecorder()
+        c := e.NewContext(req, rec)
+        c.SetParamNames("id")
+        c.SetParamValues(newArticle.ID)
+
+        err = handler.PublishArticle(c)
+        require.NoError(t, err)
+
+        assert.Equal(t, http.StatusOK, rec.Code)
+
+        var resp domain.ResponseSingleData[domain.Article]
+        err = json.Unmarshal(rec.Body.Bytes(), &resp)
+        require.NoError(t, err)
+
+        assert.Equal(t, domain.StatusInReview, resp.Data.Status)
+        require.NotNil(t, resp.Data.ScheduledAt)
+        assert.True(t, resp.Data.ScheduledAt.Equal(scheduledAt))
+
+        mockArticleService.AssertExpectations(t)
+    })
+
     // 	// --- Get Article
     t.Run("GetArticle", func(t *testing.T) {
         id, err := uuid.Parse(newArticle.ID)
@@ -128,7 +174,7 @@ func TestArticleHappyPath(t *testing.T) {
             Return(&updatedArticle, nil).
             Once()
 
-        e := echo.New()
+        e := newEcho()
         req := httptest.NewRequest(http.MethodGet, "/api/v1/articles/"+newArticle.ID, nil)
         rec := httptest.NewRecorder()
         c := e.NewContext(req, rec)
@@ -145,6 +191,95 @@ func TestArticleHappyPath(t *testing.T) {
         require.NoError(t, err)
         assert.Equal(t, "success", resp.Status)
         assert.Equal(t, updatedArticle, resp.Data)
+        assert.Equal(t, `"2"`, rec.Header().Get("ETag"))
+
+        mockArticleService.AssertExpectations(t)
+    })
+
+    // --- Get Unchanged Article
+    t.Run("GetArticle_NotModified", func(t *testing.T) {
+        id, err := uuid.Parse(newArticle.ID)
+        require.NoError(t, err)
+
+        mockArticleService.
+            On("GetArticle", mock.Anything, id).
+            Return(&updatedArticle, nil).
+            Once()
+
+        e := newEcho()
+        req := httptest.NewRequest(http.MethodGet, "/api/v1/articles/"+newArticle.ID, nil)
+        req.Header.Set("If-None-Match", `W/"2"`)
+        rec := httptest.NewRecorder()
+        c := e.NewContext(req, rec)
+        c.SetParamNames("id")
+        c.SetParamValues(newArticle.ID)
+
+        err = handler.GetArticle(c)
+        require.NoError(t, err)
+
+        assert.Equal(t, http.StatusNotModified, rec.Code)
+        assert.Empty(t, rec.Body.Bytes())
+
+        mockArticleService.AssertExpectations(t)
+    })
+
+    // --- List Articles
+    t.Run("GetArticleList", func(t *testing.T) {
+        meta := domain.CursorMeta{NextCursor: "next-page", HasMore: true}
+        mockArticleService.
+            On("GetArticleList", mock.Anything, mock.MatchedBy(func(f *domain.ArticleFilter) bool {
+                return f.Limit == 1 && f.Sort == domain.SortTitle && f.Order == domain.OrderAsc
+            })).
+            Return([]domain.Article{updatedArticle}, meta, nil).
+            Once()
+
+        e := newEcho()
+        req := httptest.NewRequest(http.MethodGet, "/api/v1/articles?limit=1&sort=title&order=asc", nil)
+        rec := httptest.NewRecorder()
+        c := e.NewContext(req, rec)
+
+        err := handler.GetArticleList(c)
+        require.NoError(t, err)
+
+        assert.Equal(t, http.StatusOK, rec.Code)
+
+        var resp domain.ResponseCursorData[domain.Article]
+        err = json.Unmarshal(rec.Body.Bytes(), &resp)
+        require.NoError(t, err)
+        assert.Equal(t, []domain.Article{updatedArticle}, resp.Data)
+        assert.Equal(t, meta, resp.Meta)
+
+        mockArticleService.AssertExpectations(t)
+    })
+
+    // --- Search Articles
+    t.Run("SearchArticles", func(t *testing.T) {
+        result := domain.ArticleSearchResult{
+            Article:  updatedArticle,
+            Rank:     0.6,
+            Headline: "Test <mark>content</mark> update",
+        }
+        mockArticleService.
+            On("SearchArticles", mock.Anything, mock.MatchedBy(func(f *domain.ArticleSearchFilter) bool {
+                return f.Query == `"test content" -draft` && f.Status == domain.StatusPublished
+            })).
+            Return([]domain.ArticleSearchResult{result}, nil).
+            Once()
+
+        e := newEcho()
+        req := httptest.NewRequest(http.MethodGet, `/api/v1/articles/search?q=%22test+content%22+-draft&status=published`, nil)
+        rec := httptest.NewRecorder()
+        c := e.NewContext(req, rec)
+
+        err := handler.SearchArticles(c)
+        require.NoError(t, err)
+
+        assert.Equal(t, http.StatusOK, rec.Code)
+
+        var resp domain.ResponseMultipleData[domain.ArticleSearchResult]
+        err = json.Unmarshal(rec.Body.Bytes(), &resp)
+        require.NoError(t, err)
+        assert.Equal(t, []domain.ArticleSearchResult{result}, resp.Data)
 
         mockArticleService.AssertExpectations(t)
     })
@@ -154,11 +289,11 @@ func TestArticleHappyPath(t *testing.T) {
         id, err := uuid.Parse(newArticle.ID)
         require.NoError(t, err)
         mockArticleService.
-            On("DeleteArticle", mock.Anything, id).
+            On("DeleteArticle", mock.Anything, id, 0).
             Return(nil).
             Once()
 
-        e := echo.New()
+        e := newEcho()
         req := httptest.NewRequest(http.MethodDelete, "/api/v1/articles/"+newArticle.ID, nil)
         rec := httptest.NewRecorder()
         c := e.NewContext(req, rec)
@@ -178,10 +313,10 @@ func TestArticleHappyPath(t *testing.T) {
         require.NoError(t, err)
         mockArticleService.
             On("GetArticle", mock.Anything, id).
-            Return(nil, sql.ErrNoRows).
+            Return(nil, domain.ErrArticleNotFound).
             Once()
 
-        e := echo.New()
+        e := newEcho()
         req := httptest.NewRequest(http.MethodGet, "/api/v1/articles/"+newArticle.ID, nil)
         rec := httptest.NewRecorder()
         c := e.NewContext(req, rec)
@@ -189,17 +324,18 @@ func TestArticleHappyPath(t *testing.T) {
         c.SetParamValues(newArticle.ID)
 
         err = handler.GetArticle(c)
-        require.NoError(t, err)
+        require.Error(t, err)
+        rest.HTTPErrorHandler(err, c)
 
         assert.Equal(t, http.StatusNotFound, rec.Code)
 
-        var resp domain.ResponseSingleData[domain.Empty]
+        var resp domain.Problem
         err = json.Unmarshal(rec.Body.Bytes(), &resp)
         require.NoError(t, err)
 
-        assert.Equal(t, "error", resp.Status)
-        assert.Equal(t, "Article not found", resp.Message)
-        assert.Equal(t, http.StatusNotFound, resp.Code)
+        assert.Equal(t, "article_not_found", resp.Code)
+        assert.Equal(t, "article not found", resp.Detail)
+        assert.Equal(t, http.StatusNotFound, resp.Status)
 
         mockArticleService.AssertExpectations(t)
     })
@@ -213,6 +349,7 @@ func TestArticleUnhappyPath(t *testing.T) {
     newArticle := domain.Article{
         ID:    "d4b8583d-5038-4838-bcd7-3d8dddfedd6a",
         Title: "Test judul",
+        AuthorID: "7f1c2a9e-3b4d-4c5e-8f6a-9b0c1d2e3f4a",
         Author: "John Doe",
         Content: "Test content",
         Status: "published",
@@ -229,10 +366,10 @@ func TestArticleUnhappyPath(t *testing.T) {
         require.NoError(t, err)
         mockArticleService.
             On("GetArticle", mock.Anything, id).
-            Return(nil, sql.ErrNoRows).
+            Return(nil, domain.ErrArticleNotFound).
             Once()
 
-        e := echo.New()
+        e := newEcho()
         req := httptest.NewRequest(http.MethodGet, "/api/v1/articles/"+newArticle.ID, nil)
         rec := httptest.NewRecorder()
         c := e.NewContext(req, rec)
@@ -240,21 +377,169 @@ func TestArticleUnhappyPath(t *testing.T) {
         c.SetParamValues(newArticle.ID)
 
         err = handler.GetArticle(c)
-        require.NoError(t, err)
+        require.Error(t, err)
+        rest.HTTPErrorHandler(err, c)
 
         assert.Equal(t, http.StatusNotFound, rec.Code)
 
-        var resp domain.ResponseSingleData[domain.Empty]
+        var resp domain.Problem
+        err = json.Unmarshal(rec.Body.Bytes(), &resp)
+        require.NoError(t, err)
+
+        assert.Equal(t, "article_not_found", resp.Code)
+        assert.Equal(t, "article not found", resp.Detail)
+        assert.Equal(t, http.StatusNotFound, resp.Status)
+
+        mockArticleService.AssertExpectations(t)
+    })
+
+    // --- Update Without Permission
+    t.Run("UpdateArticle_Forbidden", func(t *testing.T) {
+        id, err := uuid.Parse(newArticle.ID)
+        require.NoError(t, err)
+        mockArticleService.
+            On("UpdateArticle", mock.Anything, id, mock.Anything).
+            Return(nil, domain.ErrForbidden).
+            Once()
+
+        body, err := json.Marshal(newArticle)
+        require.NoError(t, err)
+
+        e := newEcho()
+        req := httptest.NewRequest(http.MethodPut, "/api/v1/articles/"+newArticle.ID, bytes.NewReader(body))
+        req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+        rec := httptest.NewRecorder()
+        c := e.NewContext(req, rec)
+        c.SetParamNames("id")
+        c.SetParamValues(newArticle.ID)
+
+        err = handler.UpdateArticle(c)
+        require.Error(t, err)
+        rest.HTTPErrorHandler(err, c)
+
+        assert.Equal(t, http.StatusForbidden, rec.Code)
+
+        var resp domain.Problem
+        err = json.Unmarshal(rec.Body.Bytes(), &resp)
+        require.NoError(t, err)
+        assert.Equal(t, "forbidden", resp.Code)
+        assert.Equal(t, http.StatusForbidden, resp.Status)
+
+        mockArticleService.AssertExpectations(t)
+    })
+
+    // --- Archive Article Still In Draft
+    t.Run("ArchiveArticle_InvalidTransition", func(t *testing.T) {
+        id, err := uuid.Parse(newArticle.ID)
+        require.NoError(t, err)
+        mockArticleService.
+            On("ArchiveArticle", mock.Anything, id).
+            Return(nil, domain.ErrInvalidTransition).
+            Once()
+
+        e := newEcho()
+        req := httptest.NewRequest(http.MethodPost, "/api/v1/articles/"+newArticle.ID+"/archive", nil)
+        rec := httptest.NewRecorder()
+        c := e.NewContext(req, rec)
+        c.SetParamNames("id")
+        c.SetParamValues(newArticle.ID)
+
+        err = handler.ArchiveArticle(c)
+        require.Error(t, err)
+        rest.HTTPErrorHandler(err, c)
+
+        assert.Equal(t, http.StatusConflict, rec.Code)
+
+        var resp domain.Problem
         err = json.Unmarshal(rec.Body.Bytes(), &resp)
         require.NoError(t, err)
+        assert.Equal(t, "invalid_transition", resp.Code)
+        assert.Equal(t, http.StatusConflict, resp.Status)
+
+        mockArticleService.AssertExpectations(t)
+    })
+
+    // --- Update With Stale ETag
+    t.Run("UpdateArticle_PreconditionFailed", func(t *testing.T) {
+        id, err := uuid.Parse(newArticle.ID)
+        require.NoError(t, err)
+        mockArticleService.
+            On("UpdateArticle", mock.Anything, id, mock.MatchedBy(func(u *domain.Article) bool {
+                return u.Version == 1
+            })).
+            Return(nil, domain.ErrPreconditionFailed).
+            Once()
+
+        body, err := json.Marshal(newArticle)
+        require.NoError(t, err)
+
+        e := newEcho()
+        req := httptest.NewRequest(http.MethodPut, "/api/v1/articles/"+newArticle.ID, bytes.NewReader(body))
+        req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+        req.Header.Set("If-Match", `"1"`)
+        rec := httptest.NewRecorder()
+        c := e.NewContext(req, rec)
+        c.SetParamNames("id")
+        c.SetParamValues(newArticle.ID)
+
+        err = handler.UpdateArticle(c)
+        require.Error(t, err)
+        rest.HTTPErrorHandler(err, c)
+
+        assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+
+        mockArticleService.AssertExpectations(t)
+    })
+
+    // --- Update Based On A Rendered Read
+    t.Run("UpdateArticle_RenderedETag", func(t *testing.T) {
+        id, err := uuid.Parse(newArticle.ID)
+        require.NoError(t, err)
+        mockArticleService.
+            On("UpdateArticle", mock.Anything, id, mock.MatchedBy(func(u *domain.Article) bool {
+                return u.Version == 1
+            })).
+            Return(nil, domain.ErrPreconditionFailed).
+            Once()
+
+        body, err := json.Marshal(newArticle)
+        require.NoError(t, err)
 
-        assert.Equal(t, "error", resp.Status)
-        assert.Equal(t, "Article not found", resp.Message)
-        assert.Equal(t, http.StatusNotFound, resp.Code)
+        e := newEcho()
+        req := httptest.NewRequest(http.MethodPut, "/api/v1/articles/"+newArticle.ID, bytes.NewReader(body))
+        req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+        req.Header.Set("If-Match", `"1-html"`)
+        rec := httptest.NewRecorder()
+        c := e.NewContext(req, rec)
+        c.SetParamNames("id")
+        c.SetParamValues(newArticle.ID)
+
+        err = handler.UpdateArticle(c)
+        require.Error(t, err)
+        rest.HTTPErrorHandler(err, c)
+
+        assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
 
         mockArticleService.AssertExpectations(t)
     })
 
+    // --- Delete With Malformed ETag
+    t.Run("DeleteArticle_MalformedIfMatch", func(t *testing.T) {
+        e := newEcho()
+        req := httptest.NewRequest(http.MethodDelete, "/api/v1/articles/"+newArticle.ID, nil)
+        req.Header.Set("If-Match", `W/"1"`)
+        rec := httptest.NewRecorder()
+        c := e.NewContext(req, rec)
+        c.SetParamNames("id")
+        c.SetParamValues(newArticle.ID)
+
+        err := handler.DeleteArticle(c)
+        require.Error(t, err)
+        rest.HTTPErrorHandler(err, c)
+
+        assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+    })
+
     // // --- Create Invalid JSON
     t.Run("CreateArticle_InvalidNameType", func(t *testing.T) {
         body := []byte(`{
@@ -264,21 +549,883 @@ func TestArticleUnhappyPath(t *testing.T) {
         "Status": "",
         }`)
 
-        e := echo.New()
+        e := newEcho()
         req := httptest.NewRequest(http.MethodPost, "/api/v1/articles", bytes.NewReader(body))
         req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
         rec := httptest.NewRecorder()
         c := e.NewContext(req, rec)
 
         err := handler.CreateArticle(c)
-        require.NoError(t, err)
+        require.Error(t, err)
+        rest.HTTPErrorHandler(err, c)
 
         assert.Equal(t, http.StatusBadRequest, rec.Code)
 
-        var resp domain.ResponseSingleData[domain.Empty]
+        var resp domain.Problem
+        err = json.Unmarshal(rec.Body.Bytes(), &resp)
+        require.NoError(t, err)
+        assert.Equal(t, "bad_request", resp.Code)
+        assert.NotEmpty(t, resp.Detail)
+    })
+}
+
+func TestArticleTopics(t *testing.T) {
+    t.Parallel()
+
+    mockArticleService := new(mocks.ArticleService)
+
+    articleID := "d4b8583d-5038-4838-bcd7-3d8dddfedd6a"
+    id := uuid.MustParse(articleID)
+    topics := []domain.Topic{
+        {ID: "550e8400-e29b-41d4-a716-446655440000", Name: "Technology"},
+        {ID: "6ba7b810-9dad-11d1-80b4-00c04fd430c8", Name: "Science"},
+    }
+
+    handler := rest.ArticleHandler{
+        Service: mockArticleService,
+    }
+
+    // --- Replace Topics
+    t.Run("ReplaceArticleTopics", func(t *testing.T) {
+        ids := []string{topics[0].ID, topics[1].ID}
+        mockArticleService.
+            On("ReplaceArticleTopics", mock.Anything, id, ids).
+            Return(topics, nil).
+            Once()
+
+        body, err := json.Marshal(domain.ReplaceArticleTopicsRequest{TopicIDs: ids})
+        require.NoError(t, err)
+
+        e := newEcho()
+        req := httptest.NewRequest(http.MethodPut, "/api/v1/articles/"+articleID+"/topics", bytes.NewReader(body))
+        req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+        rec := httptest.NewRecorder()
+        c := e.NewContext(req, rec)
+        c.SetParamNames("id")
+        c.SetParamValues(articleID)
+
+        err = handler.ReplaceArticleTopics(c)
+        require.NoError(t, err)
+
+        assert.Equal(t, http.StatusOK, rec.Code)
+
+        var resp domain.ResponseMultipleData[domain.Topic]
         err = json.Unmarshal(rec.Body.Bytes(), &resp)
         require.NoError(t, err)
-        assert.Equal(t, "error", resp.Status)
-        assert.NotEmpty(t, resp.Message)
+        assert.Equal(t, topics, resp.Data)
+
+        mockArticleService.AssertExpectations(t)
+    })
+
+    // --- Replacing Topics Moves The ETag
+    t.Run("ReplaceArticleTopics_ChangesETag", func(t *testing.T) {
+        before := domain.Article{ID: articleID, Title: "Test judul", Version: 3, Topics: topics[:1]}
+        after := domain.Article{ID: articleID, Title: "Test judul", Version: 4, Topics: topics}
+
+        get := func(t *testing.T, article domain.Article, ifNoneMatch string) *httptest.ResponseRecorder {
+            mockArticleService.
+                On("GetArticle", mock.Anything, id).
+                Return(&article, nil).
+                Once()
+
+            e := newEcho()
+            req := httptest.NewRequest(http.MethodGet, "/api/v1/articles/"+articleID, nil)
+            if ifNoneMatch != "" {
+                req.Header.Set("If-None-Match", ifNoneMatch)
+            }
+            rec := httptest.NewRecorder()
+            c := e.NewContext(req, rec)
+            c.SetParamNames("id")
+            c.SetParamValues(articleID)
+
+            require.NoError(t, handler.GetArticle(c))
+            return rec
+        }
+
+        cached := get(t, before, "").Header().Get("ETag")
+        assert.Equal(t, `"3"`, cached)
+
+        ids := []string{topics[0].ID, topics[1].ID}
+        mockArticleService.
+            On("ReplaceArticleTopics", mock.Anything, id, ids).
+            Return(topics, nil).
+            Once()
+
+        body, err := json.Marshal(domain.ReplaceArticleTopicsRequest{TopicIDs: ids})
+        require.NoError(t, err)
+
+        e := newEcho()
+        req := httptest.NewRequest(http.MethodPut, "/api/v1/articles/"+articleID+"/topics", bytes.NewReader(body))
+        req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+        rec := httptest.NewRecorder()
+        c := e.NewContext(req, rec)
+        c.SetParamNames("id")
+        c.SetParamValues(articleID)
+        require.NoError(t, handler.ReplaceArticleTopics(c))
+
+        // the copy cached before the replace is stale, the new topics are sent
+        rec = get(t, after, cached)
+        assert.Equal(t, http.StatusOK, rec.Code)
+        assert.Equal(t, `"4"`, rec.Header().Get("ETag"))
+
+        var resp domain.ResponseSingleData[domain.Article]
+        require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+        assert.Equal(t, topics, resp.Data.Topics)
+
+        mockArticleService.AssertExpectations(t)
+    })
+
+    // --- Linking A Topic Sends The Updated Article
+    t.Run("AddTopicToArticle", func(t *testing.T) {
+        updated := domain.Article{ID: articleID, Title: "Test judul", Version: 5, Topics: topics}
+        mockArticleService.
+            On("AddTopicToArticle", mock.Anything, id, topics[1].ID).
+            Return(nil).
+            Once()
+        mockArticleService.
+            On("GetArticle", mock.Anything, id).
+            Return(&updated, nil).
+            Once()
+
+        e := newEcho()
+        req := httptest.NewRequest(http.MethodPost, "/api/v1/articles/"+articleID+"/topics/"+topics[1].ID, nil)
+        rec := httptest.NewRecorder()
+        c := e.NewContext(req, rec)
+        c.SetParamNames("id", "topic_id")
+        c.SetParamValues(articleID, topics[1].ID)
+
+        require.NoError(t, handler.AddTopicToArticle(c))
+
+        assert.Equal(t, http.StatusOK, rec.Code)
+        assert.Equal(t, `"5"`, rec.Header().Get("ETag"))
+
+        var resp domain.ResponseSingleData[domain.Article]
+        require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+        assert.Equal(t, topics, resp.Data.Topics)
+
+        mockArticleService.AssertExpectations(t)
+    })
+
+    // --- Unlinking A Topic Sends The Updated Article
+    t.Run("RemoveTopicFromArticle", func(t *testing.T) {
+        updated := domain.Article{ID: articleID, Title: "Test judul", Version: 6, Topics: topics[:1]}
+        mockArticleService.
+            On("RemoveTopicFromArticle", mock.Anything, id, topics[1].ID).
+            Return(nil).
+            Once()
+        mockArticleService.
+            On("GetArticle", mock.Anything, id).
+            Return(&updated, nil).
+            Once()
+
+        e := newEcho()
+        req := httptest.NewRequest(http.MethodDelete, "/api/v1/articles/"+articleID+"/topics/"+topics[1].ID, nil)
+        rec := httptest.NewRecorder()
+        c := e.NewContext(req, rec)
+        c.SetParamNames("id", "topic_id")
+        c.SetParamValues(articleID, topics[1].ID)
+
+        require.NoError(t, handler.RemoveTopicFromArticle(c))
+
+        assert.Equal(t, http.StatusOK, rec.Code)
+        assert.Equal(t, `"6"`, rec.Header().Get("ETag"))
+
+        var resp domain.ResponseSingleData[domain.Article]
+        require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+        assert.Equal(t, topics[:1], resp.Data.Topics)
+
+        mockArticleService.AssertExpectations(t)
+    })
+
+    // --- Create With Inline Topics
+    t.Run("CreateArticle_WithTopics", func(t *testing.T) {
+        created := domain.Article{
+            ID:      articleID,
+            Title:   "Test judul",
+            Content: "Test content",
+            Status:  domain.StatusDraft,
+            Topics:  topics,
+        }
+        mockArticleService.
+            On("CreateArticle", mock.Anything, mock.MatchedBy(func(r *domain.CreateArticleRequest) bool {
+                return assert.ObjectsAreEqual([]string{topics[0].ID}, r.TopicIDs) &&
+                    assert.ObjectsAreEqual([]string{"Science"}, r.TopicNames)
+            })).
+            Return(&created, nil).
+            Once()
+
+        body := []byte(`{
+            "title": "Test judul",
+            "content": "Test content",
+            "topic_ids": ["` + topics[0].ID + `"],
+            "topic_names": ["Science"]
+        }`)
+
+        e := newEcho()
+        req := httptest.NewRequest(http.MethodPost, "/api/v1/articles", bytes.NewReader(body))
+        req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+        rec := httptest.NewRecorder()
+        c := e.NewContext(req, rec)
+
+        err := handler.CreateArticle(c)
+        require.NoError(t, err)
+
+        assert.Equal(t, http.StatusCreated, rec.Code)
+
+        var resp domain.ResponseSingleData[domain.Article]
+        err = json.Unmarshal(rec.Body.Bytes(), &resp)
+        require.NoError(t, err)
+        assert.Equal(t, topics, resp.Data.Topics)
+        assert.Nil(t, resp.Data.TopicIDs)
+
+        mockArticleService.AssertExpectations(t)
+    })
+
+    // --- Add An Unknown Topic
+    t.Run("PatchArticleTopics_UnknownTopic", func(t *testing.T) {
+        patch := domain.PatchArticleTopicsRequest{
+            Add:    []string{"3f2504e0-4f89-11d3-9a0c-0305e82c3301"},
+            Remove: []string{topics[1].ID},
+        }
+        mockArticleService.
+            On("PatchArticleTopics", mock.Anything, id, &patch).
+            Return(nil, fmt.Errorf("%w: unknown topics %s", domain.ErrBadParamInput, patch.Add[0])).
+            Once()
+
+        body, err := json.Marshal(patch)
+        require.NoError(t, err)
+
+        e := newEcho()
+        req := httptest.NewRequest(http.MethodPatch, "/api/v1/articles/"+articleID+"/topics", bytes.NewReader(body))
+        req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+        rec := httptest.NewRecorder()
+        c := e.NewContext(req, rec)
+        c.SetParamNames("id")
+        c.SetParamValues(articleID)
+
+        err = handler.PatchArticleTopics(c)
+        require.Error(t, err)
+        rest.HTTPErrorHandler(err, c)
+
+        assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+        var resp domain.Problem
+        err = json.Unmarshal(rec.Body.Bytes(), &resp)
+        require.NoError(t, err)
+        assert.Contains(t, resp.Detail, patch.Add[0])
+
+        mockArticleService.AssertExpectations(t)
+    })
+
+    // --- Replace Without A Topic List
+    t.Run("ReplaceArticleTopics_MissingList", func(t *testing.T) {
+        e := newEcho()
+        req := httptest.NewRequest(http.MethodPut, "/api/v1/articles/"+articleID+"/topics", bytes.NewReader([]byte(`{}`)))
+        req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+        rec := httptest.NewRecorder()
+        c := e.NewContext(req, rec)
+        c.SetParamNames("id")
+        c.SetParamValues(articleID)
+
+        err := handler.ReplaceArticleTopics(c)
+        require.Error(t, err)
+        rest.HTTPErrorHandler(err, c)
+
+        assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+        var resp domain.Problem
+        require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+        assert.Equal(t, []domain.FieldError{{Field: "topic_ids", Reason: "required"}}, resp.Errors)
+    })
+}
+
+func TestArticleSlugs(t *testing.T) {
+    t.Parallel()
+
+    mockArticleService := new(mocks.ArticleService)
+    article := domain.Article{
+        ID:      "d4b8583d-5038-4838-bcd7-3d8dddfedd6a",
+        Title:   "Breaking News: Important Update",
+        Slug:    "breaking-news-important-update",
+        Version: 2,
+    }
+
+    handler := rest.ArticleHandler{
+        Service: mockArticleService,
+    }
+
+    // --- Current Slug
+    t.Run("GetArticleBySlug", func(t *testing.T) {
+        mockArticleService.
+            On("GetArticleBySlug", mock.Anything, article.Slug).
+            Return(&article, nil).
+            Once()
+
+        e := newEcho()
+        req := httptest.NewRequest(http.MethodGet, "/api/v1/articles/by-slug/"+article.Slug, nil)
+        rec := httptest.NewRecorder()
+        c := e.NewContext(req, rec)
+        c.SetParamNames("slug")
+        c.SetParamValues(article.Slug)
+
+        err := handler.GetArticleBySlug(c)
+        require.NoError(t, err)
+
+        assert.Equal(t, http.StatusOK, rec.Code)
+        assert.Equal(t, `"2"`, rec.Header().Get("ETag"))
+
+        var resp domain.ResponseSingleData[domain.Article]
+        err = json.Unmarshal(rec.Body.Bytes(), &resp)
+        require.NoError(t, err)
+        assert.Equal(t, article.ID, resp.Data.ID)
+
+        mockArticleService.AssertExpectations(t)
+    })
+
+    // --- Slug From Before A Rename
+    t.Run("GetArticleBySlug_Redirect", func(t *testing.T) {
+        mockArticleService.
+            On("GetArticleBySlug", mock.Anything, "breaking-news").
+            Return(&article, nil).
+            Once()
+
+        e := newEcho()
+        req := httptest.NewRequest(http.MethodGet, "/api/v1/articles/by-slug/breaking-news?lang=en", nil)
+        rec := httptest.NewRecorder()
+        c := e.NewContext(req, rec)
+        c.SetParamNames("slug")
+        c.SetParamValues("breaking-news")
+
+        err := handler.GetArticleBySlug(c)
+        require.NoError(t, err)
+
+        assert.Equal(t, http.StatusMovedPermanently, rec.Code)
+        assert.Equal(t, "/api/v1/articles/by-slug/"+article.Slug+"?lang=en", rec.Header().Get(echo.HeaderLocation))
+
+        mockArticleService.AssertExpectations(t)
+    })
+
+    // --- Unknown Slug
+    t.Run("GetArticleBySlug_NotFound", func(t *testing.T) {
+        mockArticleService.
+            On("GetArticleBySlug", mock.Anything, "missing").
+            Return(nil, domain.ErrArticleNotFound).
+            Once()
+
+        e := newEcho()
+        req := httptest.NewRequest(http.MethodGet, "/api/v1/articles/by-slug/missing", nil)
+        rec := httptest.NewRecorder()
+        c := e.NewContext(req, rec)
+        c.SetParamNames("slug")
+        c.SetParamValues("missing")
+
+        err := handler.GetArticleBySlug(c)
+        require.Error(t, err)
+        rest.HTTPErrorHandler(err, c)
+
+        assert.Equal(t, http.StatusNotFound, rec.Code)
+
+        mockArticleService.AssertExpectations(t)
+    })
+}
+
+func TestArticleValidation(t *testing.T) {
+    t.Parallel()
+
+    // the service must never be reached with an invalid request
+    mockArticleService := new(mocks.ArticleService)
+    handler := rest.ArticleHandler{
+        Service: mockArticleService,
+    }
+    articleID := "d4b8583d-5038-4838-bcd7-3d8dddfedd6a"
+
+    serve := func(t *testing.T, method, id, body string, h echo.HandlerFunc) domain.Problem {
+        e := newEcho()
+        req := httptest.NewRequest(method, "/api/v1/articles/"+id, bytes.NewReader([]byte(body)))
+        req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+        rec := httptest.NewRecorder()
+        c := e.NewContext(req, rec)
+        c.SetParamNames("id")
+        c.SetParamValues(id)
+
+        err := h(c)
+        require.Error(t, err)
+        rest.HTTPErrorHandler(err, c)
+
+        assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+        var resp domain.Problem
+        require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+        assert.Equal(t, "validation_failed", resp.Code)
+        return resp
+    }
+
+    t.Run("CreateArticle_InvalidFields", func(t *testing.T) {
+        body := fmt.Sprintf(`{"title": %q, "topic_ids": ["not-a-uuid"], "topic_names": ["ok", ""]}`, strings.Repeat("a", 256))
+        resp := serve(t, http.MethodPost, "", body, handler.CreateArticle)
+
+        assert.Equal(t, []domain.FieldError{
+            {Field: "title", Reason: "max", Param: "255"},
+            {Field: "content", Reason: "required"},
+            {Field: "topic_ids[0]", Reason: "uuid"},
+            {Field: "topic_names[1]", Reason: "required"},
+        }, resp.Errors)
+    })
+
+    t.Run("UpdateArticle_UnknownStatus", func(t *testing.T) {
+        body := `{"title": "Title", "content": "Content", "status": "deleted"}`
+        resp := serve(t, http.MethodPut, articleID, body, handler.UpdateArticle)
+
+        assert.Equal(t, []domain.FieldError{{Field: "status", Reason: "article_status"}}, resp.Errors)
+    })
+
+    t.Run("CreateArticle_UnknownContentFormat", func(t *testing.T) {
+        body := `{"title": "Title", "content": "Content", "content_format": "rtf"}`
+        resp := serve(t, http.MethodPost, "", body, handler.CreateArticle)
+
+        assert.Equal(t, []domain.FieldError{{Field: "content_format", Reason: "content_format"}}, resp.Errors)
+    })
+
+    t.Run("UpdateArticle_MalformedID", func(t *testing.T) {
+        body := `{"title": "Title", "content": "Content"}`
+        resp := serve(t, http.MethodPut, "42", body, handler.UpdateArticle)
+
+        assert.Equal(t, []domain.FieldError{{Field: "id", Reason: "uuid"}}, resp.Errors)
+    })
+
+    mockArticleService.AssertExpectations(t)
+}
+
+func TestArticlePatch(t *testing.T) {
+    t.Parallel()
+
+    mockArticleService := new(mocks.ArticleService)
+    handler := rest.ArticleHandler{
+        Service: mockArticleService,
+    }
+
+    articleID := "d4b8583d-5038-4838-bcd7-3d8dddfedd6a"
+    id := uuid.MustParse(articleID)
+    current := domain.Article{
+        ID:      articleID,
+        Title:   "Breaking Nwes",
+        Content: "Content that stays",
+        Status:  domain.StatusDraft,
+        Version: 4,
+    }
+
+    newRequest := func(contentType, body string) (echo.Context, *httptest.ResponseRecorder) {
+        e := newEcho()
+        req := httptest.NewRequest(http.MethodPatch, "/api/v1/articles/"+articleID, bytes.NewReader([]byte(body)))
+        req.Header.Set(echo.HeaderContentType, contentType)
+        rec := httptest.NewRecorder()
+        c := e.NewContext(req, rec)
+        c.SetParamNames("id")
+        c.SetParamValues(articleID)
+        return c, rec
+    }
+
+    t.Run("PatchArticle", func(t *testing.T) {
+        patched := current
+        patched.Title = "Breaking News"
+        patched.Version = 5
+
+        mockArticleService.
+            On("GetArticle", mock.Anything, id).
+            Return(&current, nil).
+            Once()
+        mockArticleService.
+            On("UpdateArticle", mock.Anything, id, mock.MatchedBy(func(a *domain.Article) bool {
+                // untouched fields keep their value and topics are left alone
+                return a.Title == "Breaking News" && a.Content == current.Content &&
+                    a.Status == current.Status && a.Version == current.Version &&
+                    a.TopicIDs == nil && a.TopicNames == nil
+            })).
+            Return(&patched, nil).
+            Once()
+
+        c, rec := newRequest("application/merge-patch+json", `{"title": "Breaking News"}`)
+        err := handler.PatchArticle(c)
+        require.NoError(t, err)
+
+        assert.Equal(t, http.StatusOK, rec.Code)
+        assert.Equal(t, `"5"`, rec.Header().Get("ETag"))
+
+        mockArticleService.AssertExpectations(t)
+    })
+
+    t.Run("PatchArticle_ReplaceTopics", func(t *testing.T) {
+        topicID := "550e8400-e29b-41d4-a716-446655440000"
+
+        mockArticleService.
+            On("GetArticle", mock.Anything, id).
+            Return(&current, nil).
+            Once()
+        mockArticleService.
+            On("UpdateArticle", mock.Anything, id, mock.MatchedBy(func(a *domain.Article) bool {
+                return assert.ObjectsAreEqual([]string{topicID}, a.TopicIDs) && a.Title == current.Title
+            })).
+            Return(&current, nil).
+            Once()
+
+        c, rec := newRequest("application/merge-patch+json; charset=utf-8", `{"topic_ids": ["`+topicID+`"]}`)
+        err := handler.PatchArticle(c)
+        require.NoError(t, err)
+
+        assert.Equal(t, http.StatusOK, rec.Code)
+
+        mockArticleService.AssertExpectations(t)
+    })
+
+    t.Run("PatchArticle_RemovedCover", func(t *testing.T) {
+        coverID := "5b1e9d2c-4f3a-4c8e-9a7b-2d6f8e1c3a5b"
+        withCover := current
+        withCover.CoverMediaID = &coverID
+
+        mockArticleService.
+            On("GetArticle", mock.Anything, id).
+            Return(&withCover, nil).
+            Once()
+        mockArticleService.
+            On("UpdateArticle", mock.Anything, id, mock.MatchedBy(func(a *domain.Article) bool {
+                // an empty cover removes it, attachments are left alone
+                return a.CoverMediaID != nil && *a.CoverMediaID == "" && a.AttachmentIDs == nil
+            })).
+            Return(&current, nil).
+            Once()
+
+        c, rec := newRequest("application/merge-patch+json", `{"cover_media_id": null}`)
+        err := handler.PatchArticle(c)
+        require.NoError(t, err)
+
+        assert.Equal(t, http.StatusOK, rec.Code)
+
+        mockArticleService.AssertExpectations(t)
+    })
+
+    t.Run("PatchArticle_RemovedTitle", func(t *testing.T) {
+        mockArticleService.
+            On("GetArticle", mock.Anything, id).
+            Return(&current, nil).
+            Once()
+
+        c, rec := newRequest("application/merge-patch+json", `{"title": null}`)
+        err := handler.PatchArticle(c)
+        require.Error(t, err)
+        rest.HTTPErrorHandler(err, c)
+
+        assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+        var resp domain.Problem
+        require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+        assert.Equal(t, []domain.FieldError{{Field: "title", Reason: "required"}}, resp.Errors)
+
+        mockArticleService.AssertExpectations(t)
+    })
+
+    t.Run("PatchArticle_NotAMergePatch", func(t *testing.T) {
+        mockArticleService.
+            On("GetArticle", mock.Anything, id).
+            Return(&current, nil).
+            Once()
+
+        c, rec := newRequest(echo.MIMEApplicationJSON, `{"title": "Breaking News"}`)
+        err := handler.PatchArticle(c)
+        require.Error(t, err)
+        rest.HTTPErrorHandler(err, c)
+
+        assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+        assert.Equal(t, "application/merge-patch+json", rec.Header().Get("Accept-Patch"))
+
+        mockArticleService.AssertExpectations(t)
+    })
+}
+
+
+func TestArticleRender(t *testing.T) {
+    t.Parallel()
+
+    mockArticleService := new(mocks.ArticleService)
+    handler := rest.ArticleHandler{
+        Service: mockArticleService,
+    }
+
+    articleID := "d4b8583d-5038-4838-bcd7-3d8dddfedd6a"
+    id := uuid.MustParse(articleID)
+    article := domain.Article{
+        ID:                 articleID,
+        Title:              "Breaking News",
+        Content:            "Some **bold** news",
+        ContentFormat:      domain.FormatMarkdown,
+        ContentHTML:        "<p>Some <strong>bold</strong> news</p>",
+        Excerpt:            "Some bold news",
+        ReadingTimeMinutes: 1,
+        Status:             domain.StatusPublished,
+        Version:            3,
+    }
+
+    get := func(t *testing.T, query string, ifNoneMatch string) (*httptest.ResponseRecorder, error) {
+        // every request gets its own copy, the handler may drop the HTML
+        found := article
+        mockArticleService.
+            On("GetArticle", mock.Anything, id).
+            Return(&found, nil).
+            Once()
+
+        e := newEcho()
+        req := httptest.NewRequest(http.MethodGet, "/api/v1/articles/"+articleID+query, nil)
+        if ifNoneMatch != "" {
+            req.Header.Set("If-None-Match", ifNoneMatch)
+        }
+        rec := httptest.NewRecorder()
+        c := e.NewContext(req, rec)
+        c.SetParamNames("id")
+        c.SetParamValues(articleID)
+        return rec, handler.GetArticle(c)
+    }
+
+    t.Run("Source", func(t *testing.T) {
+        rec, err := get(t, "", "")
+        require.NoError(t, err)
+        assert.Equal(t, `"3"`, rec.Header().Get("ETag"))
+
+        var resp domain.ResponseSingleData[map[string]any]
+        require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+        assert.NotContains(t, resp.Data, "content_html")
+        assert.Equal(t, "markdown", resp.Data["content_format"])
+        assert.Equal(t, "Some bold news", resp.Data["excerpt"])
+        assert.Equal(t, float64(1), resp.Data["reading_time_minutes"])
+    })
+
+    t.Run("HTML", func(t *testing.T) {
+        rec, err := get(t, "?render=html", "")
+        require.NoError(t, err)
+        assert.Equal(t, `"3-html"`, rec.Header().Get("ETag"))
+
+        var resp domain.ResponseSingleData[domain.Article]
+        require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+        assert.Equal(t, article.ContentHTML, resp.Data.ContentHTML)
+    })
+
+    // --- Tags Don't Cross Forms
+    t.Run("NotModified", func(t *testing.T) {
+        rec, err := get(t, "?render=html", `"3"`)
+        require.NoError(t, err)
+        assert.Equal(t, http.StatusOK, rec.Code)
+        assert.Contains(t, rec.Body.String(), "content_html")
+
+        rec, err = get(t, "", `"3-html"`)
+        require.NoError(t, err)
+        assert.Equal(t, http.StatusOK, rec.Code)
+
+        rec, err = get(t, "?render=html", `W/"3-html"`)
+        require.NoError(t, err)
+        assert.Equal(t, http.StatusNotModified, rec.Code)
+    })
+
+    t.Run("UnknownFormat", func(t *testing.T) {
+        e := newEcho()
+        rec, err := get(t, "?render=pdf", "")
+        require.Error(t, err)
+        rest.HTTPErrorHandler(err, e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec))
+
+        assert.Equal(t, http.StatusBadRequest, rec.Code)
+    })
+
+    mockArticleService.AssertExpectations(t)
+}
+
+// viewRecorder collects the IDs of the articles whose reads were counted
+type viewRecorder struct {
+    mu  sync.Mutex
+    ids []string
+}
+
+func (v *viewRecorder) Record(articleID string) {
+    v.mu.Lock()
+    defer v.mu.Unlock()
+    v.ids = append(v.ids, articleID)
+}
+
+func TestArticleViews(t *testing.T) {
+    t.Parallel()
+
+    mockArticleService := new(mocks.ArticleService)
+    views := &viewRecorder{}
+    handler := rest.ArticleHandler{
+        Service: mockArticleService,
+        Views:   views,
+    }
+
+    published := domain.Article{
+        ID:      "d4b8583d-5038-4838-bcd7-3d8dddfedd6a",
+        Slug:    "breaking-news",
+        Status:  domain.StatusPublished,
+        Version: 2,
+    }
+    draft := domain.Article{
+        ID:      "0c7e2f5a-9d1b-4e3a-8f6c-2b5d7a9e1c3f",
+        Slug:    "draft-news",
+        Status:  domain.StatusDraft,
+        Version: 1,
+    }
+
+    get := func(t *testing.T, article domain.Article) {
+        mockArticleService.
+            On("GetArticle", mock.Anything, uuid.MustParse(article.ID)).
+            Return(&article, nil).
+            Once()
+
+        e := newEcho()
+        req := httptest.NewRequest(http.MethodGet, "/api/v1/articles/"+article.ID, nil)
+        rec := httptest.NewRecorder()
+        c := e.NewContext(req, rec)
+        c.SetParamNames("id")
+        c.SetParamValues(article.ID)
+
+        require.NoError(t, handler.GetArticle(c))
+        assert.Equal(t, http.StatusOK, rec.Code)
+    }
+
+    get(t, published)
+    get(t, draft)
+
+    mockArticleService.
+        On("GetArticleBySlug", mock.Anything, published.Slug).
+        Return(&published, nil).
+        Once()
+    e := newEcho()
+    req := httptest.NewRequest(http.MethodGet, "/api/v1/articles/by-slug/"+published.Slug, nil)
+    rec := httptest.NewRecorder()
+    c := e.NewContext(req, rec)
+    c.SetParamNames("slug")
+    c.SetParamValues(published.Slug)
+    require.NoError(t, handler.GetArticleBySlug(c))
+
+    // drafts aren't counted
+    assert.Equal(t, []string{published.ID, published.ID}, views.ids)
+    mockArticleService.AssertExpectations(t)
+}
+
+// visibilityRepo serves fixed articles to a real ArticleService, the methods
+// the visibility tests don't reach stay unimplemented
+type visibilityRepo struct {
+    service.ArticleRepository
+    articles map[string]domain.Article
+    filters  []domain.ArticleFilter
+}
+
+func (r *visibilityRepo) GetArticle(_ context.Context, id uuid.UUID) (*domain.Article, error) {
+    for _, article := range r.articles {
+        if article.ID == id.String() {
+            return &article, nil
+        }
+    }
+    return nil, domain.ErrArticleNotFound
+}
+
+func (r *visibilityRepo) GetArticleIDBySlug(_ context.Context, slug string) (uuid.UUID, error) {
+    article, ok := r.articles[slug]
+    if !ok {
+        return uuid.Nil, domain.ErrArticleNotFound
+    }
+    return uuid.MustParse(article.ID), nil
+}
+
+func (r *visibilityRepo) GetArticleList(_ context.Context, filter *domain.ArticleFilter) ([]domain.Article, domain.CursorMeta, error) {
+    r.filters = append(r.filters, *filter)
+    return nil, domain.CursorMeta{}, nil
+}
+
+// roleVerifier signs callers in by naming their role as the token, the
+// author token belongs to the author of the draft
+type roleVerifier struct{}
+
+func (roleVerifier) VerifyAccessToken(token string) (*domain.AuthUser, error) {
+    switch token {
+    case "author":
+        return &domain.AuthUser{ID: "7f1c2a9e-3b4d-4c5e-8f6a-9b0c1d2e3f4a", Role: domain.RoleAuthor}, nil
+    case "other-author":
+        return &domain.AuthUser{ID: "2b6d8f0a-1c3e-4a5b-9d7f-0e2c4a6b8d1f", Role: domain.RoleAuthor}, nil
+    case "editor":
+        return &domain.AuthUser{ID: "9a8b7c6d-5e4f-4a3b-8c2d-1e0f9a8b7c6d", Role: domain.RoleEditor}, nil
+    }
+    return nil, domain.ErrInvalidToken
+}
+
+func TestArticleVisibility(t *testing.T) {
+    t.Parallel()
+
+    draft := domain.Article{
+        ID:       "d4b8583d-5038-4838-bcd7-3d8dddfedd6a",
+        Slug:     "unreleased-news",
+        Title:    "Unreleased News",
+        Status:   domain.StatusDraft,
+        AuthorID: "7f1c2a9e-3b4d-4c5e-8f6a-9b0c1d2e3f4a",
+        Version:  1,
+    }
+    published := domain.Article{
+        ID:      "0c7e2f5a-9d1b-4e3a-8f6c-2b5d7a9e1c3f",
+        Slug:    "breaking-news",
+        Title:   "Breaking News",
+        Status:  domain.StatusPublished,
+        Version: 2,
+    }
+    repo := &visibilityRepo{articles: map[string]domain.Article{
+        draft.Slug:     draft,
+        published.Slug: published,
+    }}
+
+    e := newEcho()
+    rest.NewArticleHandler(
+        e.Group("/api/v1"),
+        service.NewArticleService(repo),
+        middleware.JWTAuth(roleVerifier{}),
+        middleware.OptionalJWTAuth(roleVerifier{}),
+        nil,
+    )
+
+    get := func(path, token string) *httptest.ResponseRecorder {
+        req := httptest.NewRequest(http.MethodGet, path, nil)
+        if token != "" {
+            req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+        }
+        rec := httptest.NewRecorder()
+        e.ServeHTTP(rec, req)
+        return rec
+    }
+
+    // --- Drafts Don't Exist For Anonymous Callers
+    t.Run("Anonymous", func(t *testing.T) {
+        rec := get("/api/v1/articles/"+draft.ID, "")
+        assert.Equal(t, http.StatusNotFound, rec.Code)
+        assert.NotContains(t, rec.Body.String(), draft.Title)
+
+        rec = get("/api/v1/articles/by-slug/"+draft.Slug, "")
+        assert.Equal(t, http.StatusNotFound, rec.Code)
+
+        rec = get("/api/v1/articles/"+published.ID, "")
+        assert.Equal(t, http.StatusOK, rec.Code)
+    })
+
+    // --- Signed In Callers
+    t.Run("SignedIn", func(t *testing.T) {
+        assert.Equal(t, http.StatusOK, get("/api/v1/articles/"+draft.ID, "author").Code)
+        assert.Equal(t, http.StatusOK, get("/api/v1/articles/"+draft.ID, "editor").Code)
+        assert.Equal(t, http.StatusForbidden, get("/api/v1/articles/"+draft.ID, "other-author").Code)
+        assert.Equal(t, http.StatusUnauthorized, get("/api/v1/articles/"+draft.ID, "expired").Code)
+    })
+
+    // --- Lists Are Scoped To The Caller
+    t.Run("GetArticleList", func(t *testing.T) {
+        repo.filters = nil
+        assert.Equal(t, http.StatusOK, get("/api/v1/articles?status=draft", "").Code)
+        assert.Equal(t, http.StatusOK, get("/api/v1/articles?status=draft", "author").Code)
+        assert.Equal(t, http.StatusOK, get("/api/v1/articles?status=draft", "editor").Code)
+
+        require.Len(t, repo.filters, 3)
+        assert.Equal(t, domain.ArticleScope{Restricted: true}, repo.filters[0].Scope)
+        assert.Equal(t, domain.ArticleScope{Restricted: true, OwnerID: draft.AuthorID}, repo.filters[1].Scope)
+        assert.Equal(t, domain.ArticleScope{}, repo.filters[2].Scope)
     })
 }
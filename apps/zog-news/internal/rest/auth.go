@@ -0,0 +1,162 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"zog-news/domain"
+
+	"github.com/labstack/echo/v4"
+)
+
+type AuthService interface {
+	Register(ctx context.Context, r *domain.RegisterRequest) (*domain.User, error)
+	Login(ctx context.Context, r *domain.LoginRequest) (*domain.AuthTokens, error)
+	Refresh(ctx context.Context, r *domain.RefreshTokenRequest) (*domain.AuthTokens, error)
+	Logout(ctx context.Context, r *domain.RefreshTokenRequest) error
+}
+
+type AuthHandler struct {
+	Service AuthService
+}
+
+func NewAuthHandler(e *echo.Group, svc AuthService) {
+	handler := &AuthHandler{
+		Service: svc,
+	}
+	authGroup := e.Group("/auth") // auth group
+
+	authGroup.POST("/register", handler.Register)
+	authGroup.POST("/login", handler.Login)
+	authGroup.POST("/refresh", handler.Refresh)
+	authGroup.POST("/logout", handler.Logout)
+}
+
+// Register creates a new user account
+//
+//	@Summary		Register user
+//	@Description	Create a new user account
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			user	body		domain.RegisterRequest					true	"Registration data"
+//	@Success		201		{object}	domain.ResponseSingleData[domain.User]	"User successfully registered"
+//	@Failure		400		{object}	domain.Problem							"Malformed request payload"
+//	@Failure		422		{object}	domain.Problem							"Invalid request fields"
+//	@Failure		409		{object}	domain.Problem							"Email already registered"
+//	@Failure		500		{object}	domain.Problem							"Internal server error"
+//	@Router			/auth/register [post]
+func (h *AuthHandler) Register(c echo.Context) error {
+	var req domain.RegisterRequest
+	if err := bind(c, &req); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	user, err := h.Service.Register(ctx, &req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, domain.ResponseSingleData[domain.User]{
+		Data:    *user,
+		Code:    http.StatusCreated,
+		Status:  "success",
+		Message: "User successfully registered",
+	})
+}
+
+// Login starts a session for a user
+//
+//	@Summary		Login
+//	@Description	Exchange email and password for an access token and a refresh token
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			credentials	body		domain.LoginRequest								true	"Login credentials"
+//	@Success		200			{object}	domain.ResponseSingleData[domain.AuthTokens]	"Successfully logged in"
+//	@Failure		400			{object}	domain.Problem									"Malformed request payload"
+//	@Failure		422			{object}	domain.Problem									"Invalid request fields"
+//	@Failure		401			{object}	domain.Problem									"Invalid email or password"
+//	@Failure		500			{object}	domain.Problem									"Internal server error"
+//	@Router			/auth/login [post]
+func (h *AuthHandler) Login(c echo.Context) error {
+	var req domain.LoginRequest
+	if err := bind(c, &req); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	tokens, err := h.Service.Login(ctx, &req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, domain.ResponseSingleData[domain.AuthTokens]{
+		Data:    *tokens,
+		Code:    http.StatusOK,
+		Status:  "success",
+		Message: "Successfully logged in",
+	})
+}
+
+// Refresh rotates a refresh token
+//
+//	@Summary		Refresh session
+//	@Description	Exchange a refresh token for a new access token and refresh token, the old refresh token is revoked
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			token	body		domain.RefreshTokenRequest						true	"Refresh token"
+//	@Success		200		{object}	domain.ResponseSingleData[domain.AuthTokens]	"Session successfully refreshed"
+//	@Failure		400		{object}	domain.Problem									"Malformed request payload"
+//	@Failure		422		{object}	domain.Problem									"Invalid request fields"
+//	@Failure		401		{object}	domain.Problem									"Invalid or expired refresh token"
+//	@Failure		500		{object}	domain.Problem									"Internal server error"
+//	@Router			/auth/refresh [post]
+func (h *AuthHandler) Refresh(c echo.Context) error {
+	var req domain.RefreshTokenRequest
+	if err := bind(c, &req); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	tokens, err := h.Service.Refresh(ctx, &req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, domain.ResponseSingleData[domain.AuthTokens]{
+		Data:    *tokens,
+		Code:    http.StatusOK,
+		Status:  "success",
+		Message: "Session successfully refreshed",
+	})
+}
+
+// Logout revokes a refresh token
+//
+//	@Summary		Logout
+//	@Description	Revoke a refresh token so it can no longer be used
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			token	body	domain.RefreshTokenRequest	true	"Refresh token"
+//	@Success		204		"Successfully logged out"
+//	@Failure		400		{object}	domain.Problem	"Malformed request payload"
+//	@Failure		422		{object}	domain.Problem	"Invalid request fields"
+//	@Failure		401		{object}	domain.Problem	"Invalid or expired refresh token"
+//	@Failure		500		{object}	domain.Problem	"Internal server error"
+//	@Router			/auth/logout [post]
+func (h *AuthHandler) Logout(c echo.Context) error {
+	var req domain.RefreshTokenRequest
+	if err := bind(c, &req); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	if err := h.Service.Logout(ctx, &req); err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,234 @@
+package rest_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"zog-news/domain"
+	"zog-news/internal/rest"
+	"zog-news/internal/rest/middleware"
+	"zog-news/internal/rest/mocks"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthHappyPath(t *testing.T) {
+	t.Parallel()
+
+	mockAuthService := new(mocks.AuthService)
+
+	user := domain.User{
+		ID:    "7f1c2a9e-3b4d-4c5e-8f6a-9b0c1d2e3f4a",
+		Name:  "John Doe",
+		Email: "john@example.com",
+	}
+	tokens := domain.AuthTokens{
+		AccessToken:  "access",
+		RefreshToken: "refresh",
+		TokenType:    "Bearer",
+		ExpiresAt:    time.Date(2023, 6, 1, 12, 15, 0, 0, time.UTC),
+	}
+
+	handler := rest.AuthHandler{
+		Service: mockAuthService,
+	}
+
+	// --- Register
+	t.Run("Register", func(t *testing.T) {
+		registerReq := domain.RegisterRequest{
+			Name:     user.Name,
+			Email:    user.Email,
+			Password: "correct horse battery",
+		}
+		mockAuthService.
+			On("Register", mock.Anything, &registerReq).
+			Return(&user, nil).
+			Once()
+
+		body, err := json.Marshal(registerReq)
+		require.NoError(t, err)
+
+		e := newEcho()
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/register", bytes.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err = handler.Register(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.NotContains(t, rec.Body.String(), "password")
+
+		var resp domain.ResponseSingleData[domain.User]
+		err = json.Unmarshal(rec.Body.Bytes(), &resp)
+		require.NoError(t, err)
+		assert.Equal(t, user, resp.Data)
+
+		mockAuthService.AssertExpectations(t)
+	})
+
+	// --- Login
+	t.Run("Login", func(t *testing.T) {
+		loginReq := domain.LoginRequest{
+			Email:    user.Email,
+			Password: "correct horse battery",
+		}
+		mockAuthService.
+			On("Login", mock.Anything, &loginReq).
+			Return(&tokens, nil).
+			Once()
+
+		body, err := json.Marshal(loginReq)
+		require.NoError(t, err)
+
+		e := newEcho()
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", bytes.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err = handler.Login(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var resp domain.ResponseSingleData[domain.AuthTokens]
+		err = json.Unmarshal(rec.Body.Bytes(), &resp)
+		require.NoError(t, err)
+		assert.Equal(t, tokens, resp.Data)
+
+		mockAuthService.AssertExpectations(t)
+	})
+
+	// --- Logout
+	t.Run("Logout", func(t *testing.T) {
+		logoutReq := domain.RefreshTokenRequest{RefreshToken: tokens.RefreshToken}
+		mockAuthService.
+			On("Logout", mock.Anything, &logoutReq).
+			Return(nil).
+			Once()
+
+		body, err := json.Marshal(logoutReq)
+		require.NoError(t, err)
+
+		e := newEcho()
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/logout", bytes.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err = handler.Logout(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		mockAuthService.AssertExpectations(t)
+	})
+}
+
+func TestAuthUnhappyPath(t *testing.T) {
+	mockAuthService := new(mocks.AuthService)
+
+	handler := rest.AuthHandler{
+		Service: mockAuthService,
+	}
+
+	// --- Wrong Password
+	t.Run("Login_InvalidCredentials", func(t *testing.T) {
+		loginReq := domain.LoginRequest{
+			Email:    "john@example.com",
+			Password: "wrong",
+		}
+		mockAuthService.
+			On("Login", mock.Anything, &loginReq).
+			Return(nil, domain.ErrInvalidCredentials).
+			Once()
+
+		body, err := json.Marshal(loginReq)
+		require.NoError(t, err)
+
+		e := newEcho()
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", bytes.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err = handler.Login(c)
+		require.Error(t, err)
+		rest.HTTPErrorHandler(err, c)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+		var resp domain.Problem
+		err = json.Unmarshal(rec.Body.Bytes(), &resp)
+		require.NoError(t, err)
+		assert.Equal(t, "invalid_credentials", resp.Code)
+
+		mockAuthService.AssertExpectations(t)
+	})
+
+	// --- Duplicate Email
+	t.Run("Register_Conflict", func(t *testing.T) {
+		registerReq := domain.RegisterRequest{
+			Name:     "John Doe",
+			Email:    "john@example.com",
+			Password: "correct horse battery",
+		}
+		mockAuthService.
+			On("Register", mock.Anything, &registerReq).
+			Return(nil, domain.ErrConflict).
+			Once()
+
+		body, err := json.Marshal(registerReq)
+		require.NoError(t, err)
+
+		e := newEcho()
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/register", bytes.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err = handler.Register(c)
+		require.Error(t, err)
+		rest.HTTPErrorHandler(err, c)
+
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		mockAuthService.AssertExpectations(t)
+	})
+
+	// --- Protected Route Without Token
+	t.Run("WriteRouteRequiresToken", func(t *testing.T) {
+		e := newEcho()
+		auth := middleware.JWTAuth(stubVerifier{})
+		rest.NewArticleHandler(e.Group("/api/v1"), new(mocks.ArticleService), auth, middleware.OptionalJWTAuth(stubVerifier{}), nil)
+
+		for _, header := range []string{"", "Bearer expired"} {
+			req := httptest.NewRequest(http.MethodDelete, "/api/v1/articles/d4b8583d-5038-4838-bcd7-3d8dddfedd6a", nil)
+			if header != "" {
+				req.Header.Set(echo.HeaderAuthorization, header)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusUnauthorized, rec.Code)
+			assert.Equal(t, "application/problem+json", rec.Header().Get(echo.HeaderContentType))
+			assert.NotEmpty(t, rec.Header().Get(echo.HeaderWWWAuthenticate))
+		}
+	})
+}
+
+// stubVerifier accepts only the "valid" access token
+type stubVerifier struct{}
+
+func (stubVerifier) VerifyAccessToken(token string) (*domain.AuthUser, error) {
+	if token != "valid" {
+		return nil, domain.ErrInvalidToken
+	}
+	return &domain.AuthUser{ID: "7f1c2a9e-3b4d-4c5e-8f6a-9b0c1d2e3f4a"}, nil
+}
@@ -0,0 +1,28 @@
+package rest
+
+import (
+	"zog-news/domain"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// bind decodes the request into req and checks its validate tags. A body
+// that can't be decoded is a bad request, one that decodes but breaks the
+// constraints is reported field by field.
+func bind(c echo.Context, req any) error {
+	if err := c.Bind(req); err != nil {
+		return badRequest("invalid request payload")
+	}
+	return c.Validate(req)
+}
+
+// paramUUID reads a UUID path parameter, a malformed one is reported as an
+// invalid field named after the parameter
+func paramUUID(c echo.Context, name string) (uuid.UUID, error) {
+	id, err := uuid.Parse(c.Param(name))
+	if err != nil {
+		return uuid.Nil, domain.NewValidationError(domain.FieldError{Field: name, Reason: "uuid"})
+	}
+	return id, nil
+}
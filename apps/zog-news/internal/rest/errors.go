@@ -0,0 +1,124 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"zog-news/domain"
+
+	"github.com/labstack/echo/v4"
+)
+
+// mimeProblemJSON is the media type of RFC 9457 problem details
+const mimeProblemJSON = "application/problem+json"
+
+// problemTypePrefix namespaces the stable error codes into problem type URIs
+const problemTypePrefix = "urn:zog-news:problem:"
+
+// problemType describes how a domain error is served
+type problemType struct {
+	err    error
+	status int
+	code   string
+	title  string
+}
+
+// problemTypes maps the domain errors to their responses, the first match
+// wins so more specific errors come first. Codes are part of the API and must
+// not change once published.
+var problemTypes = []problemType{
+	{domain.ErrArticleNotFound, http.StatusNotFound, "article_not_found", "Article not found"},
+	{domain.ErrTopicNotFound, http.StatusNotFound, "topic_not_found", "Topic not found"},
+	{domain.ErrUserNotFound, http.StatusNotFound, "user_not_found", "User not found"},
+	{domain.ErrRevisionNotFound, http.StatusNotFound, "revision_not_found", "Revision not found"},
+	{domain.ErrWebhookNotFound, http.StatusNotFound, "webhook_not_found", "Webhook not found"},
+	{domain.ErrMediaNotFound, http.StatusNotFound, "media_not_found", "Media not found"},
+	{domain.ErrNotFound, http.StatusNotFound, "not_found", "Resource not found"},
+	{domain.ErrBadParamInput, http.StatusBadRequest, "bad_request", "Invalid request"},
+	{domain.ErrValidation, http.StatusUnprocessableEntity, "validation_failed", "Validation failed"},
+	{domain.ErrInvalidCredentials, http.StatusUnauthorized, "invalid_credentials", "Invalid credentials"},
+	{domain.ErrInvalidToken, http.StatusUnauthorized, "invalid_token", "Invalid or expired token"},
+	{domain.ErrUnauthorized, http.StatusUnauthorized, "unauthorized", "Authentication required"},
+	{domain.ErrForbidden, http.StatusForbidden, "forbidden", "Forbidden"},
+	{domain.ErrInvalidTransition, http.StatusConflict, "invalid_transition", "Invalid status transition"},
+	{domain.ErrConflict, http.StatusConflict, "conflict", "Conflict"},
+	{domain.ErrPreconditionFailed, http.StatusPreconditionFailed, "precondition_failed", "Precondition failed"},
+	{domain.ErrMediaTooLarge, http.StatusRequestEntityTooLarge, "media_too_large", "Media file too large"},
+	{domain.ErrUnsupportedMediaType, http.StatusUnsupportedMediaType, "unsupported_media_type", "Unsupported media type"},
+}
+
+// HTTPErrorHandler renders every error returned by a handler or middleware as
+// problem details. Domain errors keep their message as the detail, anything
+// unexpected is logged and served as a bare 500 so internals don't leak.
+func HTTPErrorHandler(err error, c echo.Context) {
+	if c.Response().Committed {
+		return
+	}
+
+	problem := newProblem(err)
+	problem.Instance = c.Request().URL.Path
+	if problem.Status >= http.StatusInternalServerError {
+		slog.ErrorContext(c.Request().Context(), "Request failed",
+			slog.String("method", c.Request().Method),
+			slog.String("path", c.Request().URL.Path),
+			slog.String("error", err.Error()),
+		)
+	}
+
+	if c.Request().Method == http.MethodHead {
+		err = c.NoContent(problem.Status)
+	} else {
+		c.Response().Header().Set(echo.HeaderContentType, mimeProblemJSON)
+		err = c.JSON(problem.Status, problem)
+	}
+	if err != nil {
+		slog.ErrorContext(c.Request().Context(), "Failed to write error response", slog.String("error", err.Error()))
+	}
+}
+
+// newProblem builds the problem details for err without request specifics
+func newProblem(err error) domain.Problem {
+	for _, t := range problemTypes {
+		if errors.Is(err, t.err) {
+			problem := domain.Problem{
+				Type:   problemTypePrefix + t.code,
+				Title:  t.title,
+				Status: t.status,
+				Detail: err.Error(),
+				Code:   t.code,
+			}
+			var invalid *domain.ValidationError
+			if errors.As(err, &invalid) {
+				problem.Errors = invalid.Fields
+			}
+			return problem
+		}
+	}
+
+	// errors raised by echo itself, such as unknown routes or bad bodies
+	var he *echo.HTTPError
+	if errors.As(err, &he) && he.Code < http.StatusInternalServerError {
+		code := strings.ReplaceAll(strings.ToLower(http.StatusText(he.Code)), " ", "_")
+		return domain.Problem{
+			Type:   problemTypePrefix + code,
+			Title:  http.StatusText(he.Code),
+			Status: he.Code,
+			Detail: fmt.Sprint(he.Message),
+			Code:   code,
+		}
+	}
+
+	return domain.Problem{
+		Type:   problemTypePrefix + "internal_error",
+		Title:  http.StatusText(http.StatusInternalServerError),
+		Status: http.StatusInternalServerError,
+		Code:   "internal_error",
+	}
+}
+
+// badRequest reports invalid input with a message for the client
+func badRequest(message string) error {
+	return fmt.Errorf("%w: %s", domain.ErrBadParamInput, message)
+}
@@ -0,0 +1,70 @@
+package rest_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"zog-news/domain"
+	"zog-news/internal/rest"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPErrorHandler(t *testing.T) {
+	t.Parallel()
+
+	serve := func(method string, err error) (*httptest.ResponseRecorder, domain.Problem) {
+		e := newEcho()
+		req := httptest.NewRequest(method, "/api/v1/articles", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		rest.HTTPErrorHandler(err, c)
+
+		var problem domain.Problem
+		if rec.Body.Len() > 0 {
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+		}
+		return rec, problem
+	}
+
+	t.Run("WrappedDomainError", func(t *testing.T) {
+		err := fmt.Errorf("%w: email already registered", domain.ErrConflict)
+		rec, problem := serve(http.MethodPost, err)
+
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		assert.Equal(t, "application/problem+json", rec.Header().Get(echo.HeaderContentType))
+		assert.Equal(t, "urn:zog-news:problem:conflict", problem.Type)
+		assert.Equal(t, "conflict", problem.Code)
+		assert.Equal(t, http.StatusConflict, problem.Status)
+		assert.Equal(t, err.Error(), problem.Detail)
+		assert.Equal(t, "/api/v1/articles", problem.Instance)
+	})
+
+	t.Run("UnexpectedErrorIsHidden", func(t *testing.T) {
+		rec, problem := serve(http.MethodGet, errors.New("connection refused by 10.0.0.5"))
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.Equal(t, "internal_error", problem.Code)
+		assert.Empty(t, problem.Detail)
+	})
+
+	t.Run("EchoHTTPError", func(t *testing.T) {
+		rec, problem := serve(http.MethodGet, echo.ErrMethodNotAllowed)
+
+		assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+		assert.Equal(t, "method_not_allowed", problem.Code)
+	})
+
+	t.Run("HeadHasNoBody", func(t *testing.T) {
+		rec, _ := serve(http.MethodHead, domain.ErrArticleNotFound)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Zero(t, rec.Body.Len())
+	})
+}
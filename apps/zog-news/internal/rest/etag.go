@@ -0,0 +1,70 @@
+package rest
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"zog-news/domain"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	headerETag        = "ETag"
+	headerIfMatch     = "If-Match"
+	headerIfNoneMatch = "If-None-Match"
+)
+
+// etag formats a resource version as a strong entity tag
+func etag(version int) string {
+	return fmt.Sprintf(`"%d"`, version)
+}
+
+// articleETag tags a read of an article. The body only carries content_html
+// with ?render=html, so that form gets a tag of its own.
+func articleETag(c echo.Context, version int) string {
+	if c.QueryParam("render") == "html" {
+		return fmt.Sprintf(`"%d-html"`, version)
+	}
+	return etag(version)
+}
+
+// ifMatchVersion reads the version a write is conditioned on. A missing header
+// or * returns zero, meaning the write is unconditional. Anything that can't
+// be one of our tags never matches.
+func ifMatchVersion(c echo.Context) (int, error) {
+	header := strings.TrimSpace(c.Request().Header.Get(headerIfMatch))
+	if header == "" || header == "*" {
+		return 0, nil
+	}
+
+	// If-Match uses the strong comparison, weak tags never match
+	tag := strings.TrimSpace(strings.Split(header, ",")[0])
+	if !strings.HasPrefix(tag, `"`) || !strings.HasSuffix(tag, `"`) || len(tag) < 2 {
+		return 0, domain.ErrPreconditionFailed
+	}
+	// a read with ?render=html is tagged "<version>-html", it names the same
+	// version
+	version, err := strconv.Atoi(strings.TrimSuffix(tag[1:len(tag)-1], "-html"))
+	if err != nil || version < 1 {
+		return 0, domain.ErrPreconditionFailed
+	}
+	return version, nil
+}
+
+// notModified reports whether If-None-Match already names the current tag
+func notModified(c echo.Context, current string) bool {
+	header := c.Request().Header.Get(headerIfNoneMatch)
+	if header == "" {
+		return false
+	}
+
+	for _, tag := range strings.Split(header, ",") {
+		// If-None-Match uses the weak comparison
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == current {
+			return true
+		}
+	}
+	return false
+}
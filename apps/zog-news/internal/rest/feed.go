@@ -0,0 +1,305 @@
+package rest
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"zog-news/config"
+	"zog-news/domain"
+	"zog-news/feed"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	headerLastModified    = "Last-Modified"
+	headerIfModifiedSince = "If-Modified-Since"
+)
+
+// feedFormat is a document format a feed is served in
+type feedFormat string
+
+const (
+	feedRSS  feedFormat = "rss"
+	feedAtom feedFormat = "atom"
+	feedJSON feedFormat = "json"
+)
+
+type FeedHandler struct {
+	Articles ArticleService
+	Topics   TopicService
+	Config   config.FeedConfig
+}
+
+// NewFeedHandler registers the public RSS, Atom and JSON feeds of published
+// articles
+func NewFeedHandler(e *echo.Group, articles ArticleService, topics TopicService, cfg config.FeedConfig) {
+	handler := &FeedHandler{
+		Articles: articles,
+		Topics:   topics,
+		Config:   cfg,
+	}
+	feedGroup := e.Group("/feeds") // feeds group
+
+	feedGroup.GET("/articles.rss", handler.GetArticlesRSS)
+	feedGroup.GET("/articles.atom", handler.GetArticlesAtom)
+	feedGroup.GET("/articles.json", handler.GetArticlesJSON)
+	// echo params can't carry a suffix, the handler splits "<id>.<format>"
+	feedGroup.GET("/topics/:feed", handler.GetTopicFeed)
+}
+
+// GetArticlesRSS serves the latest published articles as RSS
+//
+//	@Summary		Articles RSS feed
+//	@Description	The latest published articles as an RSS 2.0 feed, newest first. Supports conditional GET with If-None-Match and If-Modified-Since
+//	@Tags			feeds
+//	@Produce		application/rss+xml
+//	@Success		200	{string}	string			"RSS 2.0 document"
+//	@Success		304	"Feed not modified"
+//	@Failure		500	{object}	domain.Problem	"Internal server error"
+//	@Router			/feeds/articles.rss [get]
+func (h *FeedHandler) GetArticlesRSS(c echo.Context) error {
+	return h.serveArticles(c, feedRSS)
+}
+
+// GetArticlesAtom serves the latest published articles as Atom
+//
+//	@Summary		Articles Atom feed
+//	@Description	The latest published articles as an Atom 1.0 feed, newest first. Supports conditional GET with If-None-Match and If-Modified-Since
+//	@Tags			feeds
+//	@Produce		application/atom+xml
+//	@Success		200	{string}	string			"Atom 1.0 document"
+//	@Success		304	"Feed not modified"
+//	@Failure		500	{object}	domain.Problem	"Internal server error"
+//	@Router			/feeds/articles.atom [get]
+func (h *FeedHandler) GetArticlesAtom(c echo.Context) error {
+	return h.serveArticles(c, feedAtom)
+}
+
+// GetArticlesJSON serves the latest published articles as JSON Feed
+//
+//	@Summary		Articles JSON feed
+//	@Description	The latest published articles as a JSON Feed 1.1 with their full content, newest first. Supports conditional GET with If-None-Match and If-Modified-Since
+//	@Tags			feeds
+//	@Produce		application/feed+json
+//	@Success		200	{string}	string			"JSON Feed 1.1 document"
+//	@Success		304	"Feed not modified"
+//	@Failure		500	{object}	domain.Problem	"Internal server error"
+//	@Router			/feeds/articles.json [get]
+func (h *FeedHandler) GetArticlesJSON(c echo.Context) error {
+	return h.serveArticles(c, feedJSON)
+}
+
+func (h *FeedHandler) serveArticles(c echo.Context, format feedFormat) error {
+	ctx := c.Request().Context()
+	articles, _, err := h.Articles.GetArticleList(ctx, &domain.ArticleFilter{
+		Status: domain.StatusPublished,
+		Sort:   domain.SortPublishedAt,
+		Order:  domain.OrderDesc,
+		Limit:  h.Config.Limit,
+	})
+	if err != nil {
+		return err
+	}
+
+	base := baseURL(c, h.Config)
+	f := &feed.Feed{
+		ID:          base + "/feeds/articles." + string(format),
+		Title:       h.Config.Title,
+		Description: "Latest articles from " + h.Config.Title,
+		Language:    h.Config.Language,
+		Link:        base + "/api/v1/articles",
+		SelfLink:    base + "/feeds/articles." + string(format),
+	}
+	return h.serve(c, format, f, articles)
+}
+
+// GetTopicFeed serves the latest published articles of a topic
+//
+//	@Summary		Topic feed
+//	@Description	The latest published articles tagged with a topic as an RSS 2.0 feed, or Atom 1.0 and JSON Feed 1.1 when requested as {id}.atom and {id}.json. Supports conditional GET with If-None-Match and If-Modified-Since
+//	@Tags			feeds
+//	@Produce		application/rss+xml
+//	@Produce		application/atom+xml
+//	@Produce		application/feed+json
+//	@Param			id					path		string			true	"Topic ID"	format(uuid)
+//	@Param			include_descendants	query		bool			false	"Also list articles tagged with any subtopic"
+//	@Success		200					{string}	string			"RSS 2.0 document"
+//	@Success		304					"Feed not modified"
+//	@Failure		404					{object}	domain.Problem	"Topic not found"
+//	@Failure		422					{object}	domain.Problem	"Invalid topic ID"
+//	@Failure		500					{object}	domain.Problem	"Internal server error"
+//	@Router			/feeds/topics/{id}.rss [get]
+func (h *FeedHandler) GetTopicFeed(c echo.Context) error {
+	param, ext, _ := strings.Cut(c.Param("feed"), ".")
+	format := feedFormat(ext)
+	if format != feedRSS && format != feedAtom && format != feedJSON {
+		return echo.ErrNotFound
+	}
+	c.SetParamNames("id")
+	c.SetParamValues(param)
+	id, err := paramUUID(c, "id")
+	if err != nil {
+		return err
+	}
+
+	filter := new(domain.TopicArticlesFilter)
+	if err := c.Bind(filter); err != nil {
+		return badRequest("invalid query parameters")
+	}
+
+	ctx := c.Request().Context()
+	topic, err := h.Topics.GetTopic(ctx, id)
+	if err != nil {
+		return err
+	}
+	articles, _, err := h.Articles.GetArticleList(ctx, &domain.ArticleFilter{
+		Status:             domain.StatusPublished,
+		TopicID:            topic.ID,
+		IncludeDescendants: filter.IncludeDescendants,
+		Sort:               domain.SortPublishedAt,
+		Order:              domain.OrderDesc,
+		Limit:              h.Config.Limit,
+	})
+	if err != nil {
+		return err
+	}
+
+	base := baseURL(c, h.Config)
+	self := base + "/feeds/topics/" + topic.ID + "." + string(format)
+	f := &feed.Feed{
+		ID:          "urn:uuid:" + topic.ID,
+		Title:       topic.Name + " - " + h.Config.Title,
+		Description: "Latest articles on " + topic.Name + " from " + h.Config.Title,
+		Language:    h.Config.Language,
+		Link:        base + "/api/v1/topics/" + topic.ID + "/articles",
+		SelfLink:    self,
+		Updated:     topic.UpdatedAt,
+	}
+	return h.serve(c, format, f, articles)
+}
+
+// serve fills f with the articles and writes it, or 304 when the client's
+// copy is current
+func (h *FeedHandler) serve(c echo.Context, format feedFormat, f *feed.Feed, articles []domain.Article) error {
+	base := baseURL(c, h.Config)
+	// the tag covers everything rendered, so any edit, unpublish or delete
+	// changes it even when Last-Modified can't move forward
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s\x00%s\x00%s\x00", format, f.Title, base)
+	for _, article := range articles {
+		item := feed.Item{
+			ID:          article.ID,
+			Title:       article.Title,
+			Link:        articleLink(base, article.Slug),
+			Author:      article.Author,
+			ContentHTML: article.ContentHTML,
+			Excerpt:     article.Excerpt,
+			Published:   publishedAt(&article),
+			Updated:     article.UpdatedAt,
+		}
+		for _, topic := range article.Topics {
+			item.Categories = append(item.Categories, topic.Name)
+		}
+		f.Items = append(f.Items, item)
+
+		f.Updated = latest(f.Updated, item.Published, item.Updated)
+		fmt.Fprintf(hash, "%s:%d\x00", article.ID, article.Version)
+	}
+	tag := `W/"` + hex.EncodeToString(hash.Sum(nil)[:16]) + `"`
+
+	header := c.Response().Header()
+	header.Set(headerETag, tag)
+	if !f.Updated.IsZero() {
+		header.Set(headerLastModified, f.Updated.UTC().Format(http.TimeFormat))
+	}
+	if feedNotModified(c, tag, f.Updated) {
+		return c.NoContent(http.StatusNotModified)
+	}
+
+	var buf bytes.Buffer
+	switch format {
+	case feedAtom:
+		if err := feed.WriteAtom(&buf, f, time.Now()); err != nil {
+			return err
+		}
+		return c.Blob(http.StatusOK, feed.ContentTypeAtom, buf.Bytes())
+	case feedJSON:
+		if err := feed.WriteJSON(&buf, f); err != nil {
+			return err
+		}
+		return c.Blob(http.StatusOK, feed.ContentTypeJSON, buf.Bytes())
+	}
+	if err := feed.WriteRSS(&buf, f); err != nil {
+		return err
+	}
+	return c.Blob(http.StatusOK, feed.ContentTypeRSS, buf.Bytes())
+}
+
+// baseURL is the configured public URL, or the scheme and host the request
+// came in on
+func baseURL(c echo.Context, cfg config.FeedConfig) string {
+	if cfg.PublicURL != "" {
+		return cfg.PublicURL
+	}
+	return c.Scheme() + "://" + c.Request().Host
+}
+
+// articleLink is where feeds and sitemaps point readers to for an article
+func articleLink(base, slug string) string {
+	return base + "/api/v1/articles/by-slug/" + url.PathEscape(slug)
+}
+
+// topicLink is where sitemaps point readers to for a topic
+func topicLink(base, slug string) string {
+	return base + "/api/v1/topics/by-slug/" + url.PathEscape(slug)
+}
+
+// feedNotModified reports whether the client's copy of a feed is current.
+// If-None-Match wins over If-Modified-Since when both are sent.
+func feedNotModified(c echo.Context, tag string, lastModified time.Time) bool {
+	req := c.Request()
+	if header := req.Header.Get(headerIfNoneMatch); header != "" {
+		// If-None-Match uses the weak comparison
+		current := strings.TrimPrefix(tag, "W/")
+		for _, candidate := range strings.Split(header, ",") {
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+			if candidate == "*" || candidate == current {
+				return true
+			}
+		}
+		return false
+	}
+
+	if lastModified.IsZero() {
+		return false
+	}
+	since, err := http.ParseTime(req.Header.Get(headerIfModifiedSince))
+	// HTTP dates have no fraction of a second
+	return err == nil && !lastModified.Truncate(time.Second).After(since)
+}
+
+// publishedAt is when an article went live, its creation time if it has
+// no publish time
+func publishedAt(article *domain.Article) time.Time {
+	if article.PublishedAt != nil {
+		return *article.PublishedAt
+	}
+	return article.CreatedAt
+}
+
+// latest returns the latest of the given times
+func latest(times ...time.Time) time.Time {
+	var last time.Time
+	for _, t := range times {
+		if t.After(last) {
+			last = t
+		}
+	}
+	return last
+}
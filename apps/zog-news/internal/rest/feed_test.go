@@ -0,0 +1,292 @@
+package rest_test
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"zog-news/config"
+	"zog-news/domain"
+	"zog-news/internal/rest"
+	"zog-news/internal/rest/mocks"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var feedConfig = config.FeedConfig{
+	PublicURL: "https://news.example.com",
+	Title:     "Zero One Group News",
+	Limit:     50,
+}
+
+func publishedArticle(id, title string, publishedAt time.Time) domain.Article {
+	return domain.Article{
+		ID:          id,
+		Title:       title,
+		Slug:        "breaking-news",
+		Content:     "This is **the content** of the article...",
+		ContentHTML: "<p>This is <strong>the content</strong> of the article...</p>",
+		Status:      domain.StatusPublished,
+		Author:      "John Doe",
+		Topics:      []domain.Topic{{Name: "Technology"}},
+		PublishedAt: &publishedAt,
+		Version:     2,
+		CreatedAt:   publishedAt.Add(-time.Hour),
+		UpdatedAt:   publishedAt,
+	}
+}
+
+func TestFeedArticles(t *testing.T) {
+	t.Parallel()
+
+	publishedAt := time.Date(2025, 7, 16, 8, 0, 0, 0, time.UTC)
+	articles := []domain.Article{
+		publishedArticle("d4b8583d-5038-4838-bcd7-3d8dddfedd6a", "Breaking News", publishedAt),
+	}
+	isPublishedFeed := mock.MatchedBy(func(f *domain.ArticleFilter) bool {
+		return f.Status == domain.StatusPublished && f.Sort == domain.SortPublishedAt && f.Limit == 50
+	})
+
+	mockArticleService := new(mocks.ArticleService)
+	mockArticleService.
+		On("GetArticleList", mock.Anything, isPublishedFeed).
+		Return(articles, domain.CursorMeta{}, nil)
+
+	handler := rest.FeedHandler{
+		Articles: mockArticleService,
+		Config:   feedConfig,
+	}
+
+	t.Run("RSS", func(t *testing.T) {
+		e := newEcho()
+		req := httptest.NewRequest(http.MethodGet, "/feeds/articles.rss", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.GetArticlesRSS(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/rss+xml; charset=utf-8", rec.Header().Get("Content-Type"))
+		assert.Equal(t, "Wed, 16 Jul 2025 08:00:00 GMT", rec.Header().Get("Last-Modified"))
+		assert.NotEmpty(t, rec.Header().Get("ETag"))
+
+		var doc struct {
+			Channel struct {
+				LastBuildDate string `xml:"lastBuildDate"`
+				Items         []struct {
+					Title    string   `xml:"title"`
+					Link     string   `xml:"link"`
+					GUID     string   `xml:"guid"`
+					PubDate  string   `xml:"pubDate"`
+					Category []string `xml:"category"`
+				} `xml:"item"`
+			} `xml:"channel"`
+		}
+		require.NoError(t, xml.Unmarshal(rec.Body.Bytes(), &doc))
+		assert.Equal(t, "Wed, 16 Jul 2025 08:00:00 +0000", doc.Channel.LastBuildDate)
+		require.Len(t, doc.Channel.Items, 1)
+		item := doc.Channel.Items[0]
+		assert.Equal(t, "Breaking News", item.Title)
+		assert.Equal(t, "https://news.example.com/api/v1/articles/by-slug/breaking-news", item.Link)
+		assert.Equal(t, "urn:uuid:d4b8583d-5038-4838-bcd7-3d8dddfedd6a", item.GUID)
+		assert.Equal(t, "Wed, 16 Jul 2025 08:00:00 +0000", item.PubDate)
+		assert.Equal(t, []string{"Technology"}, item.Category)
+	})
+
+	t.Run("Atom", func(t *testing.T) {
+		e := newEcho()
+		req := httptest.NewRequest(http.MethodGet, "/feeds/articles.atom", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.GetArticlesAtom(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/atom+xml; charset=utf-8", rec.Header().Get("Content-Type"))
+
+		var doc struct {
+			XMLName xml.Name `xml:"http://www.w3.org/2005/Atom feed"`
+			Updated string   `xml:"updated"`
+			Entries []struct {
+				ID     string `xml:"id"`
+				Author string `xml:"author>name"`
+			} `xml:"entry"`
+		}
+		require.NoError(t, xml.Unmarshal(rec.Body.Bytes(), &doc))
+		assert.Equal(t, "2025-07-16T08:00:00Z", doc.Updated)
+		require.Len(t, doc.Entries, 1)
+		assert.Equal(t, "urn:uuid:d4b8583d-5038-4838-bcd7-3d8dddfedd6a", doc.Entries[0].ID)
+		assert.Equal(t, "John Doe", doc.Entries[0].Author)
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		e := newEcho()
+		req := httptest.NewRequest(http.MethodGet, "/feeds/articles.json", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.GetArticlesJSON(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/feed+json; charset=utf-8", rec.Header().Get("Content-Type"))
+
+		var doc map[string]any
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &doc))
+		assert.Equal(t, "https://jsonfeed.org/version/1.1", doc["version"])
+		assert.Equal(t, "https://news.example.com/feeds/articles.json", doc["feed_url"])
+		items, ok := doc["items"].([]any)
+		require.True(t, ok)
+		require.Len(t, items, 1)
+		item := items[0].(map[string]any)
+		assert.Equal(t, "d4b8583d-5038-4838-bcd7-3d8dddfedd6a", item["id"])
+		assert.Equal(t, "<p>This is <strong>the content</strong> of the article...</p>", item["content_html"])
+		assert.Equal(t, "This is the content of the article...", item["content_text"])
+		assert.Equal(t, "2025-07-16T08:00:00Z", item["date_published"])
+		assert.Equal(t, []any{map[string]any{"name": "John Doe"}}, item["authors"])
+		assert.Equal(t, []any{"Technology"}, item["tags"])
+	})
+
+	t.Run("NotModified", func(t *testing.T) {
+		e := newEcho()
+		req := httptest.NewRequest(http.MethodGet, "/feeds/articles.rss", nil)
+		rec := httptest.NewRecorder()
+		require.NoError(t, handler.GetArticlesRSS(e.NewContext(req, rec)))
+		tag := rec.Header().Get("ETag")
+
+		req = httptest.NewRequest(http.MethodGet, "/feeds/articles.rss", nil)
+		req.Header.Set("If-None-Match", tag)
+		rec = httptest.NewRecorder()
+		require.NoError(t, handler.GetArticlesRSS(e.NewContext(req, rec)))
+		assert.Equal(t, http.StatusNotModified, rec.Code)
+		assert.Empty(t, rec.Body.Bytes())
+
+		req = httptest.NewRequest(http.MethodGet, "/feeds/articles.rss", nil)
+		req.Header.Set("If-Modified-Since", "Wed, 16 Jul 2025 08:00:00 GMT")
+		rec = httptest.NewRecorder()
+		require.NoError(t, handler.GetArticlesRSS(e.NewContext(req, rec)))
+		assert.Equal(t, http.StatusNotModified, rec.Code)
+
+		// a stale tag wins over a current date
+		req = httptest.NewRequest(http.MethodGet, "/feeds/articles.rss", nil)
+		req.Header.Set("If-None-Match", `W/"stale"`)
+		req.Header.Set("If-Modified-Since", "Wed, 16 Jul 2025 08:00:00 GMT")
+		rec = httptest.NewRecorder()
+		require.NoError(t, handler.GetArticlesRSS(e.NewContext(req, rec)))
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+}
+
+func TestFeedTopic(t *testing.T) {
+	t.Parallel()
+
+	topicID := uuid.MustParse("550e8400-e29b-41d4-a716-446655440000")
+	topic := domain.Topic{
+		ID:        topicID.String(),
+		Name:      "Technology",
+		UpdatedAt: time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC),
+	}
+	older := publishedArticle("d4b8583d-5038-4838-bcd7-3d8dddfedd6a", "Older", time.Date(2025, 7, 10, 8, 0, 0, 0, time.UTC))
+	newer := publishedArticle("0c7e2f5a-9d1b-4e3a-8f6c-2b5d7a9e1c3f", "Newer", time.Date(2025, 7, 16, 8, 0, 0, 0, time.UTC))
+
+	mockArticleService := new(mocks.ArticleService)
+	mockTopicService := new(mocks.TopicService)
+	handler := rest.FeedHandler{
+		Articles: mockArticleService,
+		Topics:   mockTopicService,
+		Config:   feedConfig,
+	}
+
+	t.Run("PublishedNewestFirst", func(t *testing.T) {
+		mockTopicService.
+			On("GetTopic", mock.Anything, topicID).
+			Return(&topic, nil).
+			Once()
+		mockArticleService.
+			On("GetArticleList", mock.Anything, &domain.ArticleFilter{
+				Status:             domain.StatusPublished,
+				TopicID:            topicID.String(),
+				IncludeDescendants: true,
+				Sort:               domain.SortPublishedAt,
+				Order:              domain.OrderDesc,
+				Limit:              50,
+			}).
+			Return([]domain.Article{newer, older}, domain.CursorMeta{}, nil).
+			Once()
+
+		e := newEcho()
+		req := httptest.NewRequest(http.MethodGet, "/feeds/topics/"+topicID.String()+".rss?include_descendants=true", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("feed")
+		c.SetParamValues(topicID.String() + ".rss")
+
+		err := handler.GetTopicFeed(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var doc struct {
+			Channel struct {
+				Title string `xml:"title"`
+				Items []struct {
+					Title      string   `xml:"title"`
+					Categories []string `xml:"category"`
+				} `xml:"item"`
+			} `xml:"channel"`
+		}
+		require.NoError(t, xml.Unmarshal(rec.Body.Bytes(), &doc))
+		assert.Equal(t, "Technology - Zero One Group News", doc.Channel.Title)
+		require.Len(t, doc.Channel.Items, 2)
+		assert.Equal(t, "Newer", doc.Channel.Items[0].Title)
+		assert.Equal(t, "Older", doc.Channel.Items[1].Title)
+		assert.Equal(t, []string{"Technology"}, doc.Channel.Items[0].Categories)
+
+		mockArticleService.AssertExpectations(t)
+		mockTopicService.AssertExpectations(t)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		missing := uuid.New()
+		mockTopicService.
+			On("GetTopic", mock.Anything, missing).
+			Return(nil, domain.ErrTopicNotFound).
+			Once()
+
+		e := newEcho()
+		req := httptest.NewRequest(http.MethodGet, "/feeds/topics/"+missing.String()+".atom", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("feed")
+		c.SetParamValues(missing.String() + ".atom")
+
+		err := handler.GetTopicFeed(c)
+		require.Error(t, err)
+		rest.HTTPErrorHandler(err, c)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		mockTopicService.AssertExpectations(t)
+	})
+
+	t.Run("UnknownFormat", func(t *testing.T) {
+		e := newEcho()
+		req := httptest.NewRequest(http.MethodGet, "/feeds/topics/"+topicID.String()+".xml", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("feed")
+		c.SetParamValues(topicID.String() + ".xml")
+
+		err := handler.GetTopicFeed(c)
+		require.Error(t, err)
+		rest.HTTPErrorHandler(err, c)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
@@ -0,0 +1,16 @@
+package rest_test
+
+import (
+	"zog-news/internal/rest"
+	"zog-news/internal/validator"
+
+	"github.com/labstack/echo/v4"
+)
+
+// newEcho returns an Echo instance set up like the server in main.go
+func newEcho() *echo.Echo {
+	e := echo.New()
+	e.Validator = validator.NewValidator()
+	e.HTTPErrorHandler = rest.HTTPErrorHandler
+	return e
+}
@@ -0,0 +1,231 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"zog-news/config"
+	"zog-news/domain"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// multipartOverhead is what the form around an uploaded file may add to the
+// request on top of the file itself
+const multipartOverhead = 64 << 10
+
+type MediaService interface {
+	UploadMedia(ctx context.Context, r *domain.UploadMediaRequest) (*domain.Media, error)
+	GetMedia(ctx context.Context, id uuid.UUID) (*domain.Media, error)
+	OpenMedia(ctx context.Context, id uuid.UUID, thumbnail bool) (*domain.Media, *domain.MediaFile, error)
+	DeleteMedia(ctx context.Context, id uuid.UUID) error
+}
+
+type MediaHandler struct {
+	Service MediaService
+	Config  config.MediaConfig
+}
+
+// NewMediaHandler registers the media routes, uploading and deleting require
+// auth while media and their files are public
+func NewMediaHandler(e *echo.Group, svc MediaService, auth echo.MiddlewareFunc, cfg config.MediaConfig) {
+	handler := &MediaHandler{
+		Service: svc,
+		Config:  cfg,
+	}
+	mediaGroup := e.Group("/media") // media group
+
+	mediaGroup.POST("", handler.UploadMedia, auth)
+	mediaGroup.GET("/:id", handler.GetMedia)
+	mediaGroup.GET("/:id/file", handler.GetMediaFile)
+	mediaGroup.GET("/:id/thumbnail", handler.GetMediaThumbnail)
+	mediaGroup.DELETE("/:id", handler.DeleteMedia, auth)
+}
+
+// UploadMedia stores an uploaded file
+//
+//	@Summary		Upload media
+//	@Description	Upload a JPEG, PNG, GIF, WebP or PDF file as the "file" field of a multipart form, with an optional "alt" text. The type is sniffed from the file content. Images get their dimensions and a thumbnail. Authors and up
+//	@Tags			media
+//	@Accept			multipart/form-data
+//	@Produce		json
+//	@Param			file	formData	file									true	"File to upload"
+//	@Param			alt		formData	string									false	"Alternative text describing an image"
+//	@Success		201		{object}	domain.ResponseSingleData[domain.Media]	"Media successfully uploaded"
+//	@Failure		400		{object}	domain.Problem							"Missing or empty file"
+//	@Failure		422		{object}	domain.Problem							"Invalid request fields"
+//	@Failure		401		{object}	domain.Problem							"Missing or invalid access token"
+//	@Failure		403		{object}	domain.Problem							"Not allowed for the caller's role"
+//	@Failure		413		{object}	domain.Problem							"File too large"
+//	@Failure		415		{object}	domain.Problem							"File type not allowed"
+//	@Failure		500		{object}	domain.Problem							"Internal server error"
+//	@Security		BearerAuth
+//	@Router			/media [post]
+func (h *MediaHandler) UploadMedia(c echo.Context) error {
+	r := c.Request()
+	r.Body = http.MaxBytesReader(c.Response(), r.Body, h.Config.MaxSize+multipartOverhead)
+
+	fileHeader, err := c.FormFile("file")
+	var maxBytesErr *http.MaxBytesError
+	switch {
+	case errors.As(err, &maxBytesErr):
+		return fmt.Errorf("%w: files may be at most %d bytes", domain.ErrMediaTooLarge, h.Config.MaxSize)
+	case errors.Is(err, http.ErrNotMultipart):
+		return fmt.Errorf("%w: upload the file as multipart/form-data", domain.ErrUnsupportedMediaType)
+	case errors.Is(err, http.ErrMissingFile):
+		return badRequest("file is required")
+	case err != nil:
+		return badRequest("invalid multipart form")
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	req := domain.UploadMediaRequest{
+		Filename: fileHeader.Filename,
+		Alt:      c.FormValue("alt"),
+		Body:     file,
+	}
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+
+	ctx := r.Context()
+	media, err := h.Service.UploadMedia(ctx, &req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, domain.ResponseSingleData[domain.Media]{
+		Data:    *media,
+		Code:    http.StatusCreated,
+		Status:  "success",
+		Message: "Media successfully uploaded",
+	})
+}
+
+// GetMedia retrieves a media
+//
+//	@Summary		Get media by ID
+//	@Description	Get an uploaded file's details and the URLs it and its thumbnail are served at
+//	@Tags			media
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string									true	"Media ID"	format(uuid)
+//	@Success		200	{object}	domain.ResponseSingleData[domain.Media]	"Successfully retrieved media"
+//	@Failure		422	{object}	domain.Problem							"Invalid media ID"
+//	@Failure		404	{object}	domain.Problem							"Media not found"
+//	@Failure		500	{object}	domain.Problem							"Internal server error"
+//	@Router			/media/{id} [get]
+func (h *MediaHandler) GetMedia(c echo.Context) error {
+	id, err := paramUUID(c, "id")
+	if err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	media, err := h.Service.GetMedia(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, domain.ResponseSingleData[domain.Media]{
+		Data:    *media,
+		Code:    http.StatusOK,
+		Status:  "success",
+		Message: "Successfully retrieved media",
+	})
+}
+
+// GetMediaFile serves an uploaded file
+//
+//	@Summary		Get media file
+//	@Description	Download an uploaded file. Files never change, so they may be cached for good
+//	@Tags			media
+//	@Produce		image/jpeg,image/png,image/gif,image/webp,application/pdf
+//	@Param			id	path		string			true	"Media ID"	format(uuid)
+//	@Success		200	{file}		file			"The file"
+//	@Failure		422	{object}	domain.Problem	"Invalid media ID"
+//	@Failure		404	{object}	domain.Problem	"Media not found"
+//	@Failure		500	{object}	domain.Problem	"Internal server error"
+//	@Router			/media/{id}/file [get]
+func (h *MediaHandler) GetMediaFile(c echo.Context) error {
+	return h.serveFile(c, false)
+}
+
+// GetMediaThumbnail serves the thumbnail of an uploaded image
+//
+//	@Summary		Get media thumbnail
+//	@Description	Download the thumbnail of an uploaded image, a JPEG for JPEGs and a PNG otherwise. Other files have no thumbnail
+//	@Tags			media
+//	@Produce		image/jpeg,image/png
+//	@Param			id	path		string			true	"Media ID"	format(uuid)
+//	@Success		200	{file}		file			"The thumbnail"
+//	@Failure		422	{object}	domain.Problem	"Invalid media ID"
+//	@Failure		404	{object}	domain.Problem	"Media or thumbnail not found"
+//	@Failure		500	{object}	domain.Problem	"Internal server error"
+//	@Router			/media/{id}/thumbnail [get]
+func (h *MediaHandler) GetMediaThumbnail(c echo.Context) error {
+	return h.serveFile(c, true)
+}
+
+func (h *MediaHandler) serveFile(c echo.Context, thumbnail bool) error {
+	id, err := paramUUID(c, "id")
+	if err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	media, file, err := h.Service.OpenMedia(ctx, id, thumbnail)
+	if err != nil {
+		return err
+	}
+	defer file.Body.Close()
+
+	header := c.Response().Header()
+	// a media never changes, a new upload gets a new ID
+	header.Set("Cache-Control", "public, max-age=31536000, immutable")
+	header.Set("X-Content-Type-Options", "nosniff")
+	header.Set("Content-Disposition", mime.FormatMediaType("inline", map[string]string{"filename": media.Filename}))
+	if file.Size > 0 {
+		header.Set(echo.HeaderContentLength, strconv.FormatInt(file.Size, 10))
+	}
+	return c.Stream(http.StatusOK, file.ContentType, file.Body)
+}
+
+// DeleteMedia removes an uploaded file
+//
+//	@Summary		Delete media
+//	@Description	Remove an uploaded file no article uses as cover or attachment anymore, trashed articles included. Editors, or the author who uploaded it
+//	@Tags			media
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string			true	"Media ID"	format(uuid)
+//	@Success		204	"Media successfully deleted"
+//	@Failure		422	{object}	domain.Problem	"Invalid media ID"
+//	@Failure		401	{object}	domain.Problem	"Missing or invalid access token"
+//	@Failure		403	{object}	domain.Problem	"Not allowed for the caller's role"
+//	@Failure		404	{object}	domain.Problem	"Media not found"
+//	@Failure		409	{object}	domain.Problem	"Media is used by an article"
+//	@Failure		500	{object}	domain.Problem	"Internal server error"
+//	@Security		BearerAuth
+//	@Router			/media/{id} [delete]
+func (h *MediaHandler) DeleteMedia(c echo.Context) error {
+	id, err := paramUUID(c, "id")
+	if err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	if err := h.Service.DeleteMedia(ctx, id); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,290 @@
+package rest_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"zog-news/config"
+	"zog-news/domain"
+	"zog-news/internal/rest"
+	"zog-news/internal/rest/mocks"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newUpload builds a multipart form holding content as the "file" field
+func newUpload(t *testing.T, filename string, content []byte, alt string) (*bytes.Buffer, string) {
+	t.Helper()
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("file", filename)
+	require.NoError(t, err)
+	_, err = part.Write(content)
+	require.NoError(t, err)
+	if alt != "" {
+		require.NoError(t, form.WriteField("alt", alt))
+	}
+	require.NoError(t, form.Close())
+	return &body, form.FormDataContentType()
+}
+
+func TestMediaHappyPath(t *testing.T) {
+	t.Parallel()
+
+	mockMediaService := new(mocks.MediaService)
+
+	media := domain.Media{
+		ID:           "5b1e9d2c-4f3a-4c8e-9a7b-2d6f8e1c3a5b",
+		Filename:     "flood.png",
+		ContentType:  "image/png",
+		Size:         8,
+		Width:        640,
+		Height:       480,
+		Alt:          "Flooded street",
+		ThumbnailKey: "2025/07/5b1e9d2c-4f3a-4c8e-9a7b-2d6f8e1c3a5b-thumbnail.png",
+	}
+	media.SetURLs()
+	id := uuid.MustParse(media.ID)
+
+	handler := rest.MediaHandler{
+		Service: mockMediaService,
+		Config:  config.MediaConfig{MaxSize: 1 << 20},
+	}
+
+	// --- Upload Media
+	t.Run("UploadMedia", func(t *testing.T) {
+		content := []byte("\x89PNG\r\n\x1a\n")
+		mockMediaService.
+			On("UploadMedia", mock.Anything, mock.MatchedBy(func(r *domain.UploadMediaRequest) bool {
+				body, err := io.ReadAll(r.Body)
+				return err == nil && r.Filename == "flood.png" && r.Alt == "Flooded street" && bytes.Equal(body, content)
+			})).
+			Return(&media, nil).
+			Once()
+
+		body, contentType := newUpload(t, "flood.png", content, "Flooded street")
+
+		e := newEcho()
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/media", body)
+		req.Header.Set(echo.HeaderContentType, contentType)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.UploadMedia(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.NotContains(t, rec.Body.String(), "storage_key")
+
+		var resp domain.ResponseSingleData[map[string]any]
+		err = json.Unmarshal(rec.Body.Bytes(), &resp)
+		require.NoError(t, err)
+		assert.Equal(t, "/api/v1/media/"+media.ID+"/file", resp.Data["url"])
+		assert.Equal(t, "/api/v1/media/"+media.ID+"/thumbnail", resp.Data["thumbnail_url"])
+
+		mockMediaService.AssertExpectations(t)
+	})
+
+	// --- Get Media File
+	t.Run("GetMediaFile", func(t *testing.T) {
+		file := &domain.MediaFile{
+			Body:        io.NopCloser(strings.NewReader("\x89PNG\r\n\x1a\n")),
+			ContentType: "image/png",
+			Size:        8,
+		}
+		mockMediaService.
+			On("OpenMedia", mock.Anything, id, false).
+			Return(&media, file, nil).
+			Once()
+
+		e := newEcho()
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/media/"+media.ID+"/file", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(media.ID)
+
+		err := handler.GetMediaFile(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "image/png", rec.Header().Get(echo.HeaderContentType))
+		assert.Equal(t, "8", rec.Header().Get(echo.HeaderContentLength))
+		assert.Equal(t, "nosniff", rec.Header().Get("X-Content-Type-Options"))
+		assert.Contains(t, rec.Header().Get("Cache-Control"), "immutable")
+		assert.Equal(t, `inline; filename=flood.png`, rec.Header().Get("Content-Disposition"))
+		assert.Equal(t, "\x89PNG\r\n\x1a\n", rec.Body.String())
+
+		mockMediaService.AssertExpectations(t)
+	})
+
+	// --- Delete Media
+	t.Run("DeleteMedia", func(t *testing.T) {
+		mockMediaService.
+			On("DeleteMedia", mock.Anything, id).
+			Return(nil).
+			Once()
+
+		e := newEcho()
+		req := httptest.NewRequest(http.MethodDelete, "/api/v1/media/"+media.ID, nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(media.ID)
+
+		err := handler.DeleteMedia(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		mockMediaService.AssertExpectations(t)
+	})
+}
+
+func TestMediaUnhappyPath(t *testing.T) {
+	mockMediaService := new(mocks.MediaService)
+
+	handler := rest.MediaHandler{
+		Service: mockMediaService,
+		Config:  config.MediaConfig{MaxSize: 1 << 10},
+	}
+
+	problemCode := func(t *testing.T, rec *httptest.ResponseRecorder) string {
+		var resp domain.Problem
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		return resp.Code
+	}
+
+	// --- Missing File
+	t.Run("UploadMedia_MissingFile", func(t *testing.T) {
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		require.NoError(t, form.WriteField("alt", "No file"))
+		require.NoError(t, form.Close())
+
+		e := newEcho()
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/media", &body)
+		req.Header.Set(echo.HeaderContentType, form.FormDataContentType())
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.UploadMedia(c)
+		require.Error(t, err)
+		rest.HTTPErrorHandler(err, c)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	// --- Not Multipart
+	t.Run("UploadMedia_NotMultipart", func(t *testing.T) {
+		e := newEcho()
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/media", strings.NewReader(`{"file": "flood.png"}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.UploadMedia(c)
+		require.Error(t, err)
+		rest.HTTPErrorHandler(err, c)
+
+		assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+		assert.Equal(t, "unsupported_media_type", problemCode(t, rec))
+	})
+
+	// --- Too Large
+	t.Run("UploadMedia_TooLarge", func(t *testing.T) {
+		body, contentType := newUpload(t, "huge.png", bytes.Repeat([]byte{0}, 128<<10), "")
+
+		e := newEcho()
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/media", body)
+		req.Header.Set(echo.HeaderContentType, contentType)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.UploadMedia(c)
+		require.Error(t, err)
+		rest.HTTPErrorHandler(err, c)
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+		assert.Equal(t, "media_too_large", problemCode(t, rec))
+	})
+
+	// --- Unsupported Type
+	t.Run("UploadMedia_UnsupportedType", func(t *testing.T) {
+		mockMediaService.
+			On("UploadMedia", mock.Anything, mock.Anything).
+			Return(nil, domain.ErrUnsupportedMediaType).
+			Once()
+
+		body, contentType := newUpload(t, "notes.txt", []byte("plain text"), "")
+
+		e := newEcho()
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/media", body)
+		req.Header.Set(echo.HeaderContentType, contentType)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.UploadMedia(c)
+		require.Error(t, err)
+		rest.HTTPErrorHandler(err, c)
+
+		assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+		mockMediaService.AssertExpectations(t)
+	})
+
+	// --- Missing Thumbnail
+	t.Run("GetMediaThumbnail_NotFound", func(t *testing.T) {
+		id := uuid.New()
+		mockMediaService.
+			On("OpenMedia", mock.Anything, id, true).
+			Return(nil, nil, domain.ErrMediaNotFound).
+			Once()
+
+		e := newEcho()
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/media/"+id.String()+"/thumbnail", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(id.String())
+
+		err := handler.GetMediaThumbnail(c)
+		require.Error(t, err)
+		rest.HTTPErrorHandler(err, c)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Equal(t, "media_not_found", problemCode(t, rec))
+		mockMediaService.AssertExpectations(t)
+	})
+
+	// --- Media In Use
+	t.Run("DeleteMedia_InUse", func(t *testing.T) {
+		id := uuid.New()
+		mockMediaService.
+			On("DeleteMedia", mock.Anything, id).
+			Return(domain.ErrConflict).
+			Once()
+
+		e := newEcho()
+		req := httptest.NewRequest(http.MethodDelete, "/api/v1/media/"+id.String(), nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(id.String())
+
+		err := handler.DeleteMedia(c)
+		require.Error(t, err)
+		rest.HTTPErrorHandler(err, c)
+
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		mockMediaService.AssertExpectations(t)
+	})
+}
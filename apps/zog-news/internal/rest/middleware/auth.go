@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"strings"
+	"zog-news/domain"
+
+	"github.com/labstack/echo/v4"
+)
+
+// TokenVerifier validates an access token and returns the caller it belongs to
+type TokenVerifier interface {
+	VerifyAccessToken(token string) (*domain.AuthUser, error)
+}
+
+// JWTAuth rejects requests without a valid "Authorization: Bearer <token>"
+// header and stores the authenticated caller in the request context.
+func JWTAuth(verifier TokenVerifier) echo.MiddlewareFunc {
+	return jwtAuth(verifier, true)
+}
+
+// OptionalJWTAuth lets requests without an Authorization header through as
+// anonymous, for public reads that show more to signed in callers. A header
+// that is sent must still hold a valid token.
+func OptionalJWTAuth(verifier TokenVerifier) echo.MiddlewareFunc {
+	return jwtAuth(verifier, false)
+}
+
+func jwtAuth(verifier TokenVerifier, required bool) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			header := c.Request().Header.Get(echo.HeaderAuthorization)
+			if header == "" && !required {
+				return next(c)
+			}
+			scheme, token, found := strings.Cut(header, " ")
+			if !found || !strings.EqualFold(scheme, "Bearer") || token == "" {
+				return unauthorized(c, domain.ErrUnauthorized)
+			}
+
+			user, err := verifier.VerifyAccessToken(strings.TrimSpace(token))
+			if err != nil {
+				return unauthorized(c, domain.ErrInvalidToken)
+			}
+
+			req := c.Request()
+			c.SetRequest(req.WithContext(domain.ContextWithAuthUser(req.Context(), user)))
+			return next(c)
+		}
+	}
+}
+
+// unauthorized asks the client to authenticate and leaves rendering err to
+// the error handler
+func unauthorized(c echo.Context, err error) error {
+	c.Response().Header().Set(echo.HeaderWWWAuthenticate, `Bearer realm="zog-news"`)
+	return err
+}
@@ -33,6 +33,11 @@ func Cors() echo.MiddlewareFunc {
 			echo.HeaderAccept,
 			echo.HeaderAuthorization,
 			"X-Signature",
+			"If-Match",
+			"If-None-Match",
+		},
+		ExposeHeaders: []string{
+			"ETag",
 		},
 	})
 }
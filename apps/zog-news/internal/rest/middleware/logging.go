@@ -13,6 +13,11 @@ func SlogLoggerMiddleware() echo.MiddlewareFunc {
 		return func(c echo.Context) error {
 			start := time.Now()
 			err := next(c)
+			if err != nil {
+				// render the error now so the logged status is the one sent,
+				// the error handler skips responses that are already committed
+				c.Error(err)
+			}
 			duration := time.Since(start)
 
 			req := c.Request()
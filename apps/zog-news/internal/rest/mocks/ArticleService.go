@@ -60,7 +60,7 @@ func (_m *ArticleService) UpdateArticle(ctx context.Context, id uuid.UUID, u *do
 	return r0, r1
 }
 
-func (_m *ArticleService) GetArticleList(ctx context.Context, filter *domain.ArticleFilter) ([]domain.Article, error) {
+func (_m *ArticleService) GetArticleList(ctx context.Context, filter *domain.ArticleFilter) ([]domain.Article, domain.CursorMeta, error) {
 	ret := _m.Called(ctx, filter)
 
 	var r0 []domain.Article
@@ -68,6 +68,171 @@ func (_m *ArticleService) GetArticleList(ctx context.Context, filter *domain.Art
 		r0 = ret.Get(0).([]domain.Article)
 	}
 
+	var r1 domain.CursorMeta
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(domain.CursorMeta)
+	}
+
+	var r2 error
+	if ret.Get(2) != nil {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+func (_m *ArticleService) SearchArticles(ctx context.Context, filter *domain.ArticleSearchFilter) ([]domain.ArticleSearchResult, error) {
+	ret := _m.Called(ctx, filter)
+
+	var r0 []domain.ArticleSearchResult
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).([]domain.ArticleSearchResult)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+func (_m *ArticleService) SubmitArticle(ctx context.Context, id uuid.UUID) (*domain.Article, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *domain.Article
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*domain.Article)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+func (_m *ArticleService) PublishArticle(ctx context.Context, id uuid.UUID, req *domain.PublishArticleRequest) (*domain.Article, error) {
+	ret := _m.Called(ctx, id, req)
+
+	var r0 *domain.Article
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*domain.Article)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+func (_m *ArticleService) ArchiveArticle(ctx context.Context, id uuid.UUID) (*domain.Article, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *domain.Article
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*domain.Article)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+func (_m *ArticleService) RejectArticle(ctx context.Context, id uuid.UUID) (*domain.Article, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *domain.Article
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*domain.Article)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+func (_m *ArticleService) GetArticleRevisions(ctx context.Context, id uuid.UUID) ([]domain.ArticleRevision, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 []domain.ArticleRevision
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).([]domain.ArticleRevision)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+func (_m *ArticleService) GetArticleRevision(ctx context.Context, id uuid.UUID, revision int) (*domain.ArticleRevision, error) {
+	ret := _m.Called(ctx, id, revision)
+
+	var r0 *domain.ArticleRevision
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*domain.ArticleRevision)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+func (_m *ArticleService) DiffArticleRevisions(ctx context.Context, id uuid.UUID, filter *domain.RevisionDiffFilter) (*domain.ArticleRevisionDiff, error) {
+	ret := _m.Called(ctx, id, filter)
+
+	var r0 *domain.ArticleRevisionDiff
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*domain.ArticleRevisionDiff)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+func (_m *ArticleService) RestoreArticleRevision(ctx context.Context, id uuid.UUID, revision int) (*domain.Article, error) {
+	ret := _m.Called(ctx, id, revision)
+
+	var r0 *domain.Article
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*domain.Article)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+func (_m *ArticleService) GetDeletedArticles(ctx context.Context) ([]domain.Article, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []domain.Article
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).([]domain.Article)
+	}
+
 	var r1 error
 	if ret.Get(1) != nil {
 		r1 = ret.Error(1)
@@ -76,7 +241,23 @@ func (_m *ArticleService) GetArticleList(ctx context.Context, filter *domain.Art
 	return r0, r1
 }
 
-func (_m *ArticleService) DeleteArticle(ctx context.Context, id uuid.UUID) error {
+func (_m *ArticleService) RestoreArticle(ctx context.Context, id uuid.UUID) (*domain.Article, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *domain.Article
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*domain.Article)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+func (_m *ArticleService) PurgeArticle(ctx context.Context, id uuid.UUID) error {
 	ret := _m.Called(ctx, id)
 
 	var r0 error
@@ -87,6 +268,17 @@ func (_m *ArticleService) DeleteArticle(ctx context.Context, id uuid.UUID) error
 	return r0
 }
 
+func (_m *ArticleService) DeleteArticle(ctx context.Context, id uuid.UUID, version int) error {
+	ret := _m.Called(ctx, id, version)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 func (_m *ArticleService) AddTopicToArticle(ctx context.Context, articleID uuid.UUID, topicID string) error {
     ret := _m.Called(ctx, articleID, topicID)
 
@@ -124,3 +316,51 @@ func (_m *ArticleService) GetTopicsByArticleID(ctx context.Context, articleID uu
 
     return r0, r1
 }
+
+func (_m *ArticleService) ReplaceArticleTopics(ctx context.Context, articleID uuid.UUID, topicIDs []string) ([]domain.Topic, error) {
+	ret := _m.Called(ctx, articleID, topicIDs)
+
+	var r0 []domain.Topic
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).([]domain.Topic)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+func (_m *ArticleService) PatchArticleTopics(ctx context.Context, articleID uuid.UUID, req *domain.PatchArticleTopicsRequest) ([]domain.Topic, error) {
+	ret := _m.Called(ctx, articleID, req)
+
+	var r0 []domain.Topic
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).([]domain.Topic)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+func (_m *ArticleService) GetArticleBySlug(ctx context.Context, slug string) (*domain.Article, error) {
+	ret := _m.Called(ctx, slug)
+
+	var r0 *domain.Article
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*domain.Article)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
@@ -0,0 +1,71 @@
+package mocks
+
+import (
+	"context"
+	"zog-news/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+type AuthService struct {
+	mock.Mock
+}
+
+func (_m *AuthService) Register(ctx context.Context, r *domain.RegisterRequest) (*domain.User, error) {
+	ret := _m.Called(ctx, r)
+
+	var r0 *domain.User
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*domain.User)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+func (_m *AuthService) Login(ctx context.Context, r *domain.LoginRequest) (*domain.AuthTokens, error) {
+	ret := _m.Called(ctx, r)
+
+	var r0 *domain.AuthTokens
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*domain.AuthTokens)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+func (_m *AuthService) Refresh(ctx context.Context, r *domain.RefreshTokenRequest) (*domain.AuthTokens, error) {
+	ret := _m.Called(ctx, r)
+
+	var r0 *domain.AuthTokens
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*domain.AuthTokens)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+func (_m *AuthService) Logout(ctx context.Context, r *domain.RefreshTokenRequest) error {
+	ret := _m.Called(ctx, r)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
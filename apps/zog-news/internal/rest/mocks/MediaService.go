@@ -0,0 +1,77 @@
+package mocks
+
+import (
+	"context"
+	"zog-news/domain"
+
+	"github.com/google/uuid"
+	mock "github.com/stretchr/testify/mock"
+)
+
+type MediaService struct {
+	mock.Mock
+}
+
+func (_m *MediaService) UploadMedia(ctx context.Context, r *domain.UploadMediaRequest) (*domain.Media, error) {
+	ret := _m.Called(ctx, r)
+
+	var r0 *domain.Media
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*domain.Media)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+func (_m *MediaService) GetMedia(ctx context.Context, id uuid.UUID) (*domain.Media, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *domain.Media
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*domain.Media)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+func (_m *MediaService) OpenMedia(ctx context.Context, id uuid.UUID, thumbnail bool) (*domain.Media, *domain.MediaFile, error) {
+	ret := _m.Called(ctx, id, thumbnail)
+
+	var r0 *domain.Media
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*domain.Media)
+	}
+
+	var r1 *domain.MediaFile
+	if ret.Get(1) != nil {
+		r1 = ret.Get(1).(*domain.MediaFile)
+	}
+
+	var r2 error
+	if ret.Get(2) != nil {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+func (_m *MediaService) DeleteMedia(ctx context.Context, id uuid.UUID) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
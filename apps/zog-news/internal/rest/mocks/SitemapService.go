@@ -0,0 +1,71 @@
+package mocks
+
+import (
+	"context"
+	"zog-news/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+type SitemapService struct {
+	mock.Mock
+}
+
+func (_m *SitemapService) CountSitemapArticles(ctx context.Context) (int, error) {
+	ret := _m.Called(ctx)
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Error(1)
+	}
+
+	return ret.Int(0), r1
+}
+
+func (_m *SitemapService) GetSitemapArticles(ctx context.Context, page int) ([]domain.SitemapEntry, error) {
+	ret := _m.Called(ctx, page)
+
+	var r0 []domain.SitemapEntry
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).([]domain.SitemapEntry)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+func (_m *SitemapService) GetNewsSitemapArticles(ctx context.Context) ([]domain.SitemapEntry, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []domain.SitemapEntry
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).([]domain.SitemapEntry)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+func (_m *SitemapService) GetSitemapTopics(ctx context.Context) ([]domain.SitemapEntry, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []domain.SitemapEntry
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).([]domain.SitemapEntry)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
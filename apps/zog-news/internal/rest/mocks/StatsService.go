@@ -0,0 +1,45 @@
+package mocks
+
+import (
+	"context"
+	"zog-news/domain"
+
+	"github.com/google/uuid"
+	mock "github.com/stretchr/testify/mock"
+)
+
+type StatsService struct {
+	mock.Mock
+}
+
+func (_m *StatsService) GetTrendingArticles(ctx context.Context, filter *domain.TrendingFilter) ([]domain.TrendingArticle, error) {
+	ret := _m.Called(ctx, filter)
+
+	var r0 []domain.TrendingArticle
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).([]domain.TrendingArticle)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+func (_m *StatsService) GetArticleStats(ctx context.Context, id uuid.UUID, days int) (*domain.ArticleStats, error) {
+	ret := _m.Called(ctx, id, days)
+
+	var r0 *domain.ArticleStats
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*domain.ArticleStats)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
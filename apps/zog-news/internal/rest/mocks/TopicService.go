@@ -76,7 +76,39 @@ func (_m *TopicService) GetTopicList(ctx context.Context, filter *domain.TopicFi
 	return r0, r1
 }
 
-func (_m *TopicService) DeleteTopic(ctx context.Context, id uuid.UUID) error {
+func (_m *TopicService) GetDeletedTopics(ctx context.Context) ([]domain.Topic, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []domain.Topic
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).([]domain.Topic)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+func (_m *TopicService) RestoreTopic(ctx context.Context, id uuid.UUID) (*domain.Topic, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *domain.Topic
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*domain.Topic)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+func (_m *TopicService) PurgeTopic(ctx context.Context, id uuid.UUID) error {
 	ret := _m.Called(ctx, id)
 
 	var r0 error
@@ -87,8 +119,51 @@ func (_m *TopicService) DeleteTopic(ctx context.Context, id uuid.UUID) error {
 	return r0
 }
 
-func (_m *TopicService) GetTopicArticles(ctx context.Context, id uuid.UUID) ([]domain.Article, error) {
-    ret := _m.Called(ctx, id)
+func (_m *TopicService) DeleteTopic(ctx context.Context, id uuid.UUID, version int) error {
+	ret := _m.Called(ctx, id, version)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+func (_m *TopicService) GetTopicChildren(ctx context.Context, id uuid.UUID) ([]domain.Topic, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 []domain.Topic
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).([]domain.Topic)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+func (_m *TopicService) GetTopicTree(ctx context.Context) ([]domain.Topic, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []domain.Topic
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).([]domain.Topic)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+func (_m *TopicService) GetTopicArticles(ctx context.Context, id uuid.UUID, filter *domain.TopicArticlesFilter) ([]domain.Article, error) {
+    ret := _m.Called(ctx, id, filter)
 
     var r0 []domain.Article
     if ret.Get(0) != nil {
@@ -102,3 +177,35 @@ func (_m *TopicService) GetTopicArticles(ctx context.Context, id uuid.UUID) ([]d
 
     return r0, r1
 }
+
+func (_m *TopicService) GetTopicBySlug(ctx context.Context, slug string) (*domain.Topic, error) {
+	ret := _m.Called(ctx, slug)
+
+	var r0 *domain.Topic
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*domain.Topic)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+func (_m *TopicService) MergeTopics(ctx context.Context, targetID uuid.UUID, req *domain.MergeTopicsRequest) (*domain.Topic, error) {
+	ret := _m.Called(ctx, targetID, req)
+
+	var r0 *domain.Topic
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*domain.Topic)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
@@ -0,0 +1,88 @@
+package mocks
+
+import (
+	"context"
+	"zog-news/domain"
+
+	"github.com/google/uuid"
+	mock "github.com/stretchr/testify/mock"
+)
+
+type WebhookService struct {
+	mock.Mock
+}
+
+func (_m *WebhookService) CreateWebhook(ctx context.Context, r *domain.CreateWebhookRequest) (*domain.Webhook, error) {
+	ret := _m.Called(ctx, r)
+
+	var r0 *domain.Webhook
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*domain.Webhook)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+func (_m *WebhookService) GetWebhookList(ctx context.Context) ([]domain.Webhook, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []domain.Webhook
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).([]domain.Webhook)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+func (_m *WebhookService) GetWebhook(ctx context.Context, id uuid.UUID) (*domain.Webhook, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *domain.Webhook
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*domain.Webhook)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+func (_m *WebhookService) DeleteWebhook(ctx context.Context, id uuid.UUID) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if ret.Get(0) != nil {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+func (_m *WebhookService) GetWebhookDeliveries(ctx context.Context, id uuid.UUID) ([]domain.WebhookDelivery, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 []domain.WebhookDelivery
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).([]domain.WebhookDelivery)
+	}
+
+	var r1 error
+	if ret.Get(1) != nil {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
@@ -0,0 +1,81 @@
+package rest
+
+import (
+	"encoding/json"
+	"io"
+	"mime"
+	"reflect"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// mimeMergePatchJSON is the media type of RFC 7396 JSON merge patches
+	mimeMergePatchJSON = "application/merge-patch+json"
+
+	headerAcceptPatch = "Accept-Patch"
+)
+
+// bindMergePatch applies the merge patch in the request body to req, which
+// holds the current state of the resource, and validates the result. Members
+// the patch leaves out keep their current value and null removes a member.
+func bindMergePatch(c echo.Context, req any) error {
+	mediaType, _, _ := mime.ParseMediaType(c.Request().Header.Get(echo.HeaderContentType))
+	if mediaType != mimeMergePatchJSON {
+		c.Response().Header().Set(headerAcceptPatch, mimeMergePatchJSON)
+		return echo.ErrUnsupportedMediaType
+	}
+
+	patch, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return err
+	}
+	current, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	merged, err := mergePatch(current, patch)
+	if err != nil {
+		return badRequest("invalid merge patch")
+	}
+
+	// start from zero so removed members don't keep their old value
+	reflect.ValueOf(req).Elem().SetZero()
+	if err := json.Unmarshal(merged, req); err != nil {
+		return badRequest("invalid request payload")
+	}
+	return c.Validate(req)
+}
+
+// mergePatch applies an RFC 7396 merge patch to a JSON document
+func mergePatch(doc, patch []byte) ([]byte, error) {
+	var target, changes any
+	if err := json.Unmarshal(doc, &target); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(patch, &changes); err != nil {
+		return nil, err
+	}
+	return json.Marshal(mergeValue(target, changes))
+}
+
+// mergeValue is the MergePatch function of RFC 7396 section 2
+func mergeValue(target, patch any) any {
+	changes, ok := patch.(map[string]any)
+	if !ok {
+		// anything but an object replaces the target, arrays included
+		return patch
+	}
+	object, ok := target.(map[string]any)
+	if !ok {
+		object = map[string]any{}
+	}
+	for name, value := range changes {
+		if value == nil {
+			delete(object, name)
+			continue
+		}
+		object[name] = mergeValue(object[name], value)
+	}
+	return object
+}
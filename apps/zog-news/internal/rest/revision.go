@@ -0,0 +1,191 @@
+package rest
+
+import (
+	"net/http"
+	"strconv"
+	"zog-news/domain"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// GetArticleRevisions lists the revision history of an article
+//
+//	@Summary		Get article revisions
+//	@Description	List every revision of an article, newest first. Available to editors and the article's author
+//	@Tags			articles
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string												true	"Article ID"	format(uuid)
+//	@Success		200	{object}	domain.ResponseMultipleData[domain.ArticleRevision]	"Successfully retrieved revisions"
+//	@Failure		422	{object}	domain.Problem										"Invalid article ID"
+//	@Failure		401	{object}	domain.Problem										"Missing or invalid access token"
+//	@Failure		403	{object}	domain.Problem										"Not allowed for the caller's role"
+//	@Failure		404	{object}	domain.Problem										"Article not found"
+//	@Failure		500	{object}	domain.Problem										"Internal server error"
+//	@Security		BearerAuth
+//	@Router			/articles/{id}/revisions [get]
+func (h *ArticleHandler) GetArticleRevisions(c echo.Context) error {
+	tracer := otel.Tracer("http.handler.article")
+	ctx, span := tracer.Start(c.Request().Context(), "GetArticleRevisionsHandler")
+	defer span.End()
+
+	id, err := paramUUID(c, "id")
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "invalid UUID")
+		return err
+	}
+
+	span.SetAttributes(attribute.String("article.id", id.String()))
+	revisions, err := h.Service.GetArticleRevisions(ctx, id)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+	if revisions == nil {
+		revisions = []domain.ArticleRevision{}
+	}
+
+	return c.JSON(http.StatusOK, domain.ResponseMultipleData[domain.ArticleRevision]{
+		Data:    revisions,
+		Code:    http.StatusOK,
+		Status:  "success",
+		Message: "Successfully retrieved revisions",
+	})
+}
+
+// GetArticleRevision retrieves a single revision of an article
+//
+//	@Summary		Get article revision
+//	@Description	Get the title and content of an article as of a revision
+//	@Tags			articles
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string												true	"Article ID"	format(uuid)
+//	@Param			rev	path		int													true	"Revision number"
+//	@Success		200	{object}	domain.ResponseSingleData[domain.ArticleRevision]	"Successfully retrieved revision"
+//	@Failure		422	{object}	domain.Problem										"Invalid article ID or revision"
+//	@Failure		401	{object}	domain.Problem										"Missing or invalid access token"
+//	@Failure		403	{object}	domain.Problem										"Not allowed for the caller's role"
+//	@Failure		404	{object}	domain.Problem										"Article or revision not found"
+//	@Failure		500	{object}	domain.Problem										"Internal server error"
+//	@Security		BearerAuth
+//	@Router			/articles/{id}/revisions/{rev} [get]
+func (h *ArticleHandler) GetArticleRevision(c echo.Context) error {
+	id, rev, err := revisionParams(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	revision, err := h.Service.GetArticleRevision(ctx, id, rev)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, domain.ResponseSingleData[domain.ArticleRevision]{
+		Data:    *revision,
+		Code:    http.StatusOK,
+		Status:  "success",
+		Message: "Successfully retrieved revision",
+	})
+}
+
+// DiffArticleRevisions compares two revisions of an article
+//
+//	@Summary		Diff article revisions
+//	@Description	Line-level diff of the title and content between two revisions. Without parameters the latest revision is compared with the one before it
+//	@Tags			articles
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string													true	"Article ID"	format(uuid)
+//	@Param			from	query		int														false	"Older revision, defaults to the one before to"
+//	@Param			to		query		int														false	"Newer revision, defaults to the latest"
+//	@Success		200		{object}	domain.ResponseSingleData[domain.ArticleRevisionDiff]	"Successfully compared revisions"
+//	@Failure		400		{object}	domain.Problem											"Invalid revision numbers"
+//	@Failure		422		{object}	domain.Problem											"Invalid article ID"
+//	@Failure		401		{object}	domain.Problem											"Missing or invalid access token"
+//	@Failure		403		{object}	domain.Problem											"Not allowed for the caller's role"
+//	@Failure		404		{object}	domain.Problem											"Article or revision not found"
+//	@Failure		500		{object}	domain.Problem											"Internal server error"
+//	@Security		BearerAuth
+//	@Router			/articles/{id}/revisions/diff [get]
+func (h *ArticleHandler) DiffArticleRevisions(c echo.Context) error {
+	id, err := paramUUID(c, "id")
+	if err != nil {
+		return err
+	}
+
+	filter := new(domain.RevisionDiffFilter)
+	if err := c.Bind(filter); err != nil {
+		return badRequest("invalid revision numbers")
+	}
+
+	ctx := c.Request().Context()
+	diff, err := h.Service.DiffArticleRevisions(ctx, id, filter)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, domain.ResponseSingleData[domain.ArticleRevisionDiff]{
+		Data:    *diff,
+		Code:    http.StatusOK,
+		Status:  "success",
+		Message: "Successfully compared revisions",
+	})
+}
+
+// RestoreArticleRevision restores an article to an older revision
+//
+//	@Summary		Restore article revision
+//	@Description	Copy the title and content of an old revision back onto the article, recorded as a new revision
+//	@Tags			articles
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string										true	"Article ID"	format(uuid)
+//	@Param			rev	path		int											true	"Revision number"
+//	@Success		200	{object}	domain.ResponseSingleData[domain.Article]	"Revision successfully restored"
+//	@Failure		422	{object}	domain.Problem								"Invalid article ID or revision"
+//	@Failure		401	{object}	domain.Problem								"Missing or invalid access token"
+//	@Failure		403	{object}	domain.Problem								"Not allowed for the caller's role"
+//	@Failure		404	{object}	domain.Problem								"Article or revision not found"
+//	@Failure		500	{object}	domain.Problem								"Internal server error"
+//	@Security		BearerAuth
+//	@Router			/articles/{id}/revisions/{rev}/restore [post]
+func (h *ArticleHandler) RestoreArticleRevision(c echo.Context) error {
+	id, rev, err := revisionParams(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	article, err := h.Service.RestoreArticleRevision(ctx, id, rev)
+	if err != nil {
+		return err
+	}
+
+	c.Response().Header().Set(headerETag, etag(article.Version))
+	return c.JSON(http.StatusOK, domain.ResponseSingleData[domain.Article]{
+		Data:    *article,
+		Code:    http.StatusOK,
+		Status:  "success",
+		Message: "Revision successfully restored",
+	})
+}
+
+// revisionParams parses the article ID and revision number path parameters
+func revisionParams(c echo.Context) (uuid.UUID, int, error) {
+	id, err := paramUUID(c, "id")
+	if err != nil {
+		return uuid.Nil, 0, err
+	}
+	rev, err := strconv.Atoi(c.Param("rev"))
+	if err != nil || rev < 1 {
+		return uuid.Nil, 0, domain.NewValidationError(domain.FieldError{Field: "rev", Reason: "min", Param: "1"})
+	}
+	return id, rev, nil
+}
@@ -0,0 +1,115 @@
+package rest_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"zog-news/domain"
+	"zog-news/internal/rest"
+	"zog-news/internal/rest/mocks"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArticleRevisions(t *testing.T) {
+	t.Parallel()
+
+	mockArticleService := new(mocks.ArticleService)
+
+	articleID := "d4b8583d-5038-4838-bcd7-3d8dddfedd6a"
+	id := uuid.MustParse(articleID)
+
+	revisions := []domain.ArticleRevision{
+		{ArticleID: articleID, Revision: 2, Title: "Test judul", Content: "line one\nline two"},
+		{ArticleID: articleID, Revision: 1, Title: "Test judul", Content: "line one"},
+	}
+
+	handler := rest.ArticleHandler{
+		Service: mockArticleService,
+	}
+
+	// --- List Revisions
+	t.Run("GetArticleRevisions", func(t *testing.T) {
+		mockArticleService.
+			On("GetArticleRevisions", mock.Anything, id).
+			Return(revisions, nil).
+			Once()
+
+		e := newEcho()
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/articles/"+articleID+"/revisions", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(articleID)
+
+		err := handler.GetArticleRevisions(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var resp domain.ResponseMultipleData[domain.ArticleRevision]
+		err = json.Unmarshal(rec.Body.Bytes(), &resp)
+		require.NoError(t, err)
+		assert.Equal(t, revisions, resp.Data)
+
+		mockArticleService.AssertExpectations(t)
+	})
+
+	// --- Diff Revisions
+	t.Run("DiffArticleRevisions", func(t *testing.T) {
+		diff := domain.DiffRevisions(&revisions[1], &revisions[0])
+		mockArticleService.
+			On("DiffArticleRevisions", mock.Anything, id, &domain.RevisionDiffFilter{From: 1, To: 2}).
+			Return(&diff, nil).
+			Once()
+
+		e := newEcho()
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/articles/"+articleID+"/revisions/diff?from=1&to=2", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(articleID)
+
+		err := handler.DiffArticleRevisions(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var resp domain.ResponseSingleData[domain.ArticleRevisionDiff]
+		err = json.Unmarshal(rec.Body.Bytes(), &resp)
+		require.NoError(t, err)
+		assert.Equal(t, []domain.DiffLine{
+			{Op: domain.DiffEqual, Text: "line one"},
+			{Op: domain.DiffInsert, Text: "line two"},
+		}, resp.Data.Content)
+
+		mockArticleService.AssertExpectations(t)
+	})
+
+	// --- Restore Missing Revision
+	t.Run("RestoreArticleRevision_NotFound", func(t *testing.T) {
+		mockArticleService.
+			On("RestoreArticleRevision", mock.Anything, id, 9).
+			Return(nil, domain.ErrRevisionNotFound).
+			Once()
+
+		e := newEcho()
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/articles/"+articleID+"/revisions/9/restore", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id", "rev")
+		c.SetParamValues(articleID, "9")
+
+		err := handler.RestoreArticleRevision(c)
+		require.Error(t, err)
+		rest.HTTPErrorHandler(err, c)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+
+		mockArticleService.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,198 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"zog-news/config"
+	"zog-news/domain"
+	"zog-news/feed"
+
+	"github.com/labstack/echo/v4"
+)
+
+// sitemapMaxAge is how long crawlers may reuse a sitemap
+const sitemapMaxAge = "public, max-age=900"
+
+type SitemapService interface {
+	CountSitemapArticles(ctx context.Context) (int, error)
+	GetSitemapArticles(ctx context.Context, page int) ([]domain.SitemapEntry, error)
+	GetNewsSitemapArticles(ctx context.Context) ([]domain.SitemapEntry, error)
+	GetSitemapTopics(ctx context.Context) ([]domain.SitemapEntry, error)
+}
+
+type SitemapHandler struct {
+	Service SitemapService
+	Config  config.FeedConfig
+}
+
+// NewSitemapHandler registers the sitemaps of published articles and live
+// topics
+func NewSitemapHandler(e *echo.Group, svc SitemapService, cfg config.FeedConfig) {
+	handler := &SitemapHandler{
+		Service: svc,
+		Config:  cfg,
+	}
+
+	e.GET("/sitemap.xml", handler.GetSitemap)
+	e.GET("/news-sitemap.xml", handler.GetNewsSitemap)
+	// the pages a sitemap index points at, topics.xml and articles-<n>.xml
+	e.GET("/sitemaps/:file", handler.GetSitemapPage)
+}
+
+// GetSitemap serves the sitemap of every published article and live topic
+//
+//	@Summary		Sitemap
+//	@Description	Sitemap of every published article and live topic. Past 50,000 URLs it is a sitemap index pointing at /sitemaps/topics.xml and /sitemaps/articles-{n}.xml instead
+//	@Tags			seo
+//	@Produce		application/xml
+//	@Success		200	{string}	string			"Sitemap or sitemap index"
+//	@Failure		500	{object}	domain.Problem	"Internal server error"
+//	@Router			/sitemap.xml [get]
+func (h *SitemapHandler) GetSitemap(c echo.Context) error {
+	ctx := c.Request().Context()
+	count, err := h.Service.CountSitemapArticles(ctx)
+	if err != nil {
+		return err
+	}
+	topics, err := h.Service.GetSitemapTopics(ctx)
+	if err != nil {
+		return err
+	}
+
+	base := baseURL(c, h.Config)
+	var buf bytes.Buffer
+	if count+len(topics) > domain.SitemapMaxURLs {
+		sitemaps := []feed.URL{{Loc: base + "/sitemaps/topics.xml"}}
+		pages := (count + domain.SitemapMaxURLs - 1) / domain.SitemapMaxURLs
+		for page := 1; page <= pages; page++ {
+			sitemaps = append(sitemaps, feed.URL{Loc: base + "/sitemaps/articles-" + strconv.Itoa(page) + ".xml"})
+		}
+		if err := feed.WriteSitemapIndex(&buf, sitemaps); err != nil {
+			return err
+		}
+		return h.blob(c, buf.Bytes())
+	}
+
+	var articles []domain.SitemapEntry
+	if count > 0 {
+		if articles, err = h.Service.GetSitemapArticles(ctx, 1); err != nil {
+			return err
+		}
+	}
+	urls := append(articleURLs(base, articles), topicURLs(base, topics)...)
+	if err := feed.WriteSitemap(&buf, urls); err != nil {
+		return err
+	}
+	return h.blob(c, buf.Bytes())
+}
+
+// GetSitemapPage serves a sitemap listed in the sitemap index
+//
+//	@Summary		Sitemap page
+//	@Description	A sitemap listed in the sitemap index, topics.xml for the live topics or articles-{n}.xml for the n-th 50,000 published articles, oldest first
+//	@Tags			seo
+//	@Produce		application/xml
+//	@Param			file	path		string			true	"topics.xml or articles-{n}.xml"
+//	@Success		200		{string}	string			"Sitemap"
+//	@Failure		404		{object}	domain.Problem	"No such sitemap"
+//	@Failure		500		{object}	domain.Problem	"Internal server error"
+//	@Router			/sitemaps/{file} [get]
+func (h *SitemapHandler) GetSitemapPage(c echo.Context) error {
+	ctx := c.Request().Context()
+	base := baseURL(c, h.Config)
+	file := c.Param("file")
+
+	var urls []feed.URL
+	if file == "topics.xml" {
+		topics, err := h.Service.GetSitemapTopics(ctx)
+		if err != nil {
+			return err
+		}
+		urls = topicURLs(base, topics)
+	} else {
+		name, ok := strings.CutPrefix(file, "articles-")
+		name, isXML := strings.CutSuffix(name, ".xml")
+		page, err := strconv.Atoi(name)
+		if !ok || !isXML || err != nil || page < 1 {
+			return echo.ErrNotFound
+		}
+
+		articles, err := h.Service.GetSitemapArticles(ctx, page)
+		if err != nil {
+			return err
+		}
+		// only the first page may be empty, on a site without articles
+		if len(articles) == 0 && page > 1 {
+			return echo.ErrNotFound
+		}
+		urls = articleURLs(base, articles)
+	}
+
+	var buf bytes.Buffer
+	if err := feed.WriteSitemap(&buf, urls); err != nil {
+		return err
+	}
+	return h.blob(c, buf.Bytes())
+}
+
+// GetNewsSitemap serves the Google News sitemap
+//
+//	@Summary		Google News sitemap
+//	@Description	Google News sitemap of the articles published in the last 48 hours, newest first, at most 1,000
+//	@Tags			seo
+//	@Produce		application/xml
+//	@Success		200	{string}	string			"Google News sitemap"
+//	@Failure		500	{object}	domain.Problem	"Internal server error"
+//	@Router			/news-sitemap.xml [get]
+func (h *SitemapHandler) GetNewsSitemap(c echo.Context) error {
+	ctx := c.Request().Context()
+	articles, err := h.Service.GetNewsSitemapArticles(ctx)
+	if err != nil {
+		return err
+	}
+
+	base := baseURL(c, h.Config)
+	urls := make([]feed.NewsURL, 0, len(articles))
+	for _, article := range articles {
+		url := feed.NewsURL{
+			Loc:         articleLink(base, article.Slug),
+			Title:       article.Title,
+			PublishedAt: article.LastModified,
+		}
+		if article.PublishedAt != nil {
+			url.PublishedAt = *article.PublishedAt
+		}
+		urls = append(urls, url)
+	}
+
+	publication := feed.Publication{Name: h.Config.Title, Language: h.Config.Language}
+	var buf bytes.Buffer
+	if err := feed.WriteNewsSitemap(&buf, publication, urls); err != nil {
+		return err
+	}
+	return h.blob(c, buf.Bytes())
+}
+
+func (h *SitemapHandler) blob(c echo.Context, body []byte) error {
+	c.Response().Header().Set("Cache-Control", sitemapMaxAge)
+	return c.Blob(http.StatusOK, feed.ContentTypeXML, body)
+}
+
+func articleURLs(base string, articles []domain.SitemapEntry) []feed.URL {
+	urls := make([]feed.URL, 0, len(articles))
+	for _, article := range articles {
+		urls = append(urls, feed.URL{Loc: articleLink(base, article.Slug), LastModified: article.LastModified})
+	}
+	return urls
+}
+
+func topicURLs(base string, topics []domain.SitemapEntry) []feed.URL {
+	urls := make([]feed.URL, 0, len(topics))
+	for _, topic := range topics {
+		urls = append(urls, feed.URL{Loc: topicLink(base, topic.Slug), LastModified: topic.LastModified})
+	}
+	return urls
+}
@@ -0,0 +1,156 @@
+package rest_test
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"zog-news/domain"
+	"zog-news/internal/rest"
+	"zog-news/internal/rest/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type sitemapDoc struct {
+	XMLName xml.Name
+	URLs    []struct {
+		Loc     string `xml:"loc"`
+		LastMod string `xml:"lastmod"`
+	} `xml:"url"`
+	Sitemaps []string `xml:"sitemap>loc"`
+}
+
+func TestSitemap(t *testing.T) {
+	t.Parallel()
+
+	updatedAt := time.Date(2025, 7, 16, 8, 0, 0, 0, time.UTC)
+	articles := []domain.SitemapEntry{{ID: "d4b8583d-5038-4838-bcd7-3d8dddfedd6a", Slug: "breaking-news", LastModified: updatedAt}}
+	topics := []domain.SitemapEntry{{ID: "550e8400-e29b-41d4-a716-446655440000", Slug: "technology", LastModified: updatedAt}}
+
+	t.Run("URLSet", func(t *testing.T) {
+		mockSitemapService := new(mocks.SitemapService)
+		mockSitemapService.On("CountSitemapArticles", mock.Anything).Return(1, nil).Once()
+		mockSitemapService.On("GetSitemapTopics", mock.Anything).Return(topics, nil).Once()
+		mockSitemapService.On("GetSitemapArticles", mock.Anything, 1).Return(articles, nil).Once()
+		handler := rest.SitemapHandler{Service: mockSitemapService, Config: feedConfig}
+
+		e := newEcho()
+		req := httptest.NewRequest(http.MethodGet, "/sitemap.xml", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.GetSitemap(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/xml; charset=utf-8", rec.Header().Get("Content-Type"))
+
+		var doc sitemapDoc
+		require.NoError(t, xml.Unmarshal(rec.Body.Bytes(), &doc))
+		assert.Equal(t, "urlset", doc.XMLName.Local)
+		require.Len(t, doc.URLs, 2)
+		assert.Equal(t, "https://news.example.com/api/v1/articles/by-slug/breaking-news", doc.URLs[0].Loc)
+		assert.Equal(t, "2025-07-16T08:00:00Z", doc.URLs[0].LastMod)
+		assert.Equal(t, "https://news.example.com/api/v1/topics/by-slug/technology", doc.URLs[1].Loc)
+
+		mockSitemapService.AssertExpectations(t)
+	})
+
+	t.Run("Index", func(t *testing.T) {
+		mockSitemapService := new(mocks.SitemapService)
+		mockSitemapService.On("CountSitemapArticles", mock.Anything).Return(domain.SitemapMaxURLs+1, nil).Once()
+		mockSitemapService.On("GetSitemapTopics", mock.Anything).Return(topics, nil).Once()
+		handler := rest.SitemapHandler{Service: mockSitemapService, Config: feedConfig}
+
+		e := newEcho()
+		req := httptest.NewRequest(http.MethodGet, "/sitemap.xml", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.GetSitemap(c)
+		require.NoError(t, err)
+
+		var doc sitemapDoc
+		require.NoError(t, xml.Unmarshal(rec.Body.Bytes(), &doc))
+		assert.Equal(t, "sitemapindex", doc.XMLName.Local)
+		assert.Equal(t, []string{
+			"https://news.example.com/sitemaps/topics.xml",
+			"https://news.example.com/sitemaps/articles-1.xml",
+			"https://news.example.com/sitemaps/articles-2.xml",
+		}, doc.Sitemaps)
+
+		mockSitemapService.AssertExpectations(t)
+	})
+
+	t.Run("Page", func(t *testing.T) {
+		mockSitemapService := new(mocks.SitemapService)
+		mockSitemapService.On("GetSitemapArticles", mock.Anything, 2).Return(articles, nil).Once()
+		mockSitemapService.On("GetSitemapArticles", mock.Anything, 3).Return(nil, nil).Once()
+		handler := rest.SitemapHandler{Service: mockSitemapService, Config: feedConfig}
+
+		for file, status := range map[string]int{
+			"articles-2.xml": http.StatusOK,
+			"articles-3.xml": http.StatusNotFound,
+			"articles-0.xml": http.StatusNotFound,
+			"articles.xml":   http.StatusNotFound,
+		} {
+			e := newEcho()
+			req := httptest.NewRequest(http.MethodGet, "/sitemaps/"+file, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("file")
+			c.SetParamValues(file)
+
+			if err := handler.GetSitemapPage(c); err != nil {
+				rest.HTTPErrorHandler(err, c)
+			}
+			assert.Equal(t, status, rec.Code, file)
+		}
+
+		mockSitemapService.AssertExpectations(t)
+	})
+
+	t.Run("News", func(t *testing.T) {
+		publishedAt := time.Date(2025, 7, 16, 7, 30, 0, 0, time.UTC)
+		news := []domain.SitemapEntry{{Slug: "breaking-news", Title: "Breaking News", PublishedAt: &publishedAt, LastModified: updatedAt}}
+		mockSitemapService := new(mocks.SitemapService)
+		mockSitemapService.On("GetNewsSitemapArticles", mock.Anything).Return(news, nil).Once()
+		config := feedConfig
+		config.Language = "id"
+		handler := rest.SitemapHandler{Service: mockSitemapService, Config: config}
+
+		e := newEcho()
+		req := httptest.NewRequest(http.MethodGet, "/news-sitemap.xml", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.GetNewsSitemap(c)
+		require.NoError(t, err)
+
+		const newsNS = "http://www.google.com/schemas/sitemap-news/0.9"
+		var doc struct {
+			URLs []struct {
+				Loc  string `xml:"loc"`
+				News struct {
+					Name     string `xml:"http://www.google.com/schemas/sitemap-news/0.9 publication>name"`
+					Language string `xml:"http://www.google.com/schemas/sitemap-news/0.9 publication>language"`
+					Date     string `xml:"http://www.google.com/schemas/sitemap-news/0.9 publication_date"`
+					Title    string `xml:"http://www.google.com/schemas/sitemap-news/0.9 title"`
+				} `xml:"http://www.google.com/schemas/sitemap-news/0.9 news"`
+			} `xml:"url"`
+		}
+		assert.Contains(t, rec.Body.String(), `xmlns:news="`+newsNS+`"`)
+		require.NoError(t, xml.Unmarshal(rec.Body.Bytes(), &doc))
+		require.Len(t, doc.URLs, 1)
+		assert.Equal(t, "Zero One Group News", doc.URLs[0].News.Name)
+		assert.Equal(t, "id", doc.URLs[0].News.Language)
+		assert.Equal(t, "2025-07-16T07:30:00Z", doc.URLs[0].News.Date)
+		assert.Equal(t, "Breaking News", doc.URLs[0].News.Title)
+
+		mockSitemapService.AssertExpectations(t)
+	})
+}
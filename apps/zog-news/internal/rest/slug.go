@@ -0,0 +1,16 @@
+package rest
+
+import (
+	"path"
+
+	"github.com/labstack/echo/v4"
+)
+
+// slugLocation points a by-slug request at the current slug of the resource,
+// keeping the rest of the path and the query string
+func slugLocation(c echo.Context, slug string) string {
+	location := *c.Request().URL
+	location.Path = path.Join(path.Dir(location.Path), slug)
+	location.RawPath = ""
+	return location.String()
+}
@@ -0,0 +1,115 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"zog-news/domain"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type StatsService interface {
+	GetTrendingArticles(ctx context.Context, filter *domain.TrendingFilter) ([]domain.TrendingArticle, error)
+	GetArticleStats(ctx context.Context, id uuid.UUID, days int) (*domain.ArticleStats, error)
+}
+
+type StatsHandler struct {
+	Service StatsService
+}
+
+// NewStatsHandler registers the article stats routes, trending articles are
+// public while the stats of an article require auth
+func NewStatsHandler(e *echo.Group, svc StatsService, auth echo.MiddlewareFunc) {
+	handler := &StatsHandler{
+		Service: svc,
+	}
+	articleGroup := e.Group("/articles") // articles group
+
+	articleGroup.GET("/trending", handler.GetTrendingArticles)
+	articleGroup.GET("/:id/stats", handler.GetArticleStats, auth)
+}
+
+// GetTrendingArticles lists the most read published articles
+//
+//	@Summary		Get trending articles
+//	@Description	List the published articles read the most in the window, recent views weigh more: a view loses half its weight over half the window. Views are counted per UTC day and written every few seconds
+//	@Tags			articles
+//	@Accept			json
+//	@Produce		json
+//	@Param			window	query		string												false	"Window the views are counted in"	Enums(24h,7d,30d)	default(24h)
+//	@Param			topic	query		string												false	"Filter by topic ID, name or slug"
+//	@Param			limit	query		int													false	"Number of articles (max 50)"	default(10)
+//	@Success		200		{object}	domain.ResponseMultipleData[domain.TrendingArticle]	"Successfully retrieved trending articles"
+//	@Failure		400		{object}	domain.Problem										"Unknown window or invalid query parameters"
+//	@Failure		500		{object}	domain.Problem										"Internal server error"
+//	@Router			/articles/trending [get]
+func (h *StatsHandler) GetTrendingArticles(c echo.Context) error {
+	filter := new(domain.TrendingFilter)
+	if err := c.Bind(filter); err != nil {
+		return badRequest("invalid query parameters")
+	}
+
+	ctx := c.Request().Context()
+	articles, err := h.Service.GetTrendingArticles(ctx, filter)
+	if err != nil {
+		return err
+	}
+	if articles == nil {
+		articles = []domain.TrendingArticle{}
+	}
+
+	return c.JSON(http.StatusOK, domain.ResponseMultipleData[domain.TrendingArticle]{
+		Data:    articles,
+		Code:    http.StatusOK,
+		Status:  "success",
+		Message: "Successfully retrieved trending articles",
+	})
+}
+
+// GetArticleStats retrieves how often an article was read
+//
+//	@Summary		Get article stats
+//	@Description	Get the views of an article on each of the last days, today included, in UTC. Editors, or the author of the article
+//	@Tags			articles
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string										true	"Article ID"	format(uuid)
+//	@Param			days	query		int											false	"Number of days (max 365)"	default(30)
+//	@Success		200		{object}	domain.ResponseSingleData[domain.ArticleStats]	"Successfully retrieved article stats"
+//	@Failure		400		{object}	domain.Problem								"Invalid number of days"
+//	@Failure		422		{object}	domain.Problem								"Invalid article ID"
+//	@Failure		401		{object}	domain.Problem								"Missing or invalid access token"
+//	@Failure		403		{object}	domain.Problem								"Not allowed for the caller's role"
+//	@Failure		404		{object}	domain.Problem								"Article not found"
+//	@Failure		500		{object}	domain.Problem								"Internal server error"
+//	@Security		BearerAuth
+//	@Router			/articles/{id}/stats [get]
+func (h *StatsHandler) GetArticleStats(c echo.Context) error {
+	id, err := paramUUID(c, "id")
+	if err != nil {
+		return err
+	}
+
+	days := domain.DefaultStatsDays
+	if param := c.QueryParam("days"); param != "" {
+		days, err = strconv.Atoi(param)
+		if err != nil {
+			return badRequest("days must be a number")
+		}
+	}
+
+	ctx := c.Request().Context()
+	stats, err := h.Service.GetArticleStats(ctx, id, days)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, domain.ResponseSingleData[domain.ArticleStats]{
+		Data:    *stats,
+		Code:    http.StatusOK,
+		Status:  "success",
+		Message: "Successfully retrieved article stats",
+	})
+}
@@ -0,0 +1,161 @@
+package rest_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"zog-news/domain"
+	"zog-news/internal/rest"
+	"zog-news/internal/rest/mocks"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatsHappyPath(t *testing.T) {
+	t.Parallel()
+
+	mockStatsService := new(mocks.StatsService)
+	handler := rest.StatsHandler{
+		Service: mockStatsService,
+	}
+
+	articleID := "d4b8583d-5038-4838-bcd7-3d8dddfedd6a"
+	id := uuid.MustParse(articleID)
+
+	// --- Trending Articles
+	t.Run("GetTrendingArticles", func(t *testing.T) {
+		trending := []domain.TrendingArticle{{
+			Article: domain.Article{ID: articleID, Title: "Breaking News", Status: domain.StatusPublished},
+			Views:   120,
+			Score:   97.5,
+		}}
+		mockStatsService.
+			On("GetTrendingArticles", mock.Anything, &domain.TrendingFilter{Window: "7d", Topic: "technology", Limit: 5}).
+			Return(trending, nil).
+			Once()
+
+		e := newEcho()
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/articles/trending?window=7d&topic=technology&limit=5", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.GetTrendingArticles(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var resp domain.ResponseMultipleData[domain.TrendingArticle]
+		err = json.Unmarshal(rec.Body.Bytes(), &resp)
+		require.NoError(t, err)
+		assert.Equal(t, trending, resp.Data)
+
+		mockStatsService.AssertExpectations(t)
+	})
+
+	// --- Article Stats
+	t.Run("GetArticleStats", func(t *testing.T) {
+		stats := domain.ArticleStats{
+			ArticleID:  articleID,
+			TotalViews: 7,
+			Days: []domain.DailyViews{
+				{Date: "2025-07-17", Views: 0},
+				{Date: "2025-07-18", Views: 7},
+			},
+		}
+		mockStatsService.
+			On("GetArticleStats", mock.Anything, id, 2).
+			Return(&stats, nil).
+			Once()
+
+		e := newEcho()
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/articles/"+articleID+"/stats?days=2", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(articleID)
+
+		err := handler.GetArticleStats(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var resp domain.ResponseSingleData[domain.ArticleStats]
+		err = json.Unmarshal(rec.Body.Bytes(), &resp)
+		require.NoError(t, err)
+		assert.Equal(t, stats, resp.Data)
+
+		mockStatsService.AssertExpectations(t)
+	})
+}
+
+func TestStatsUnhappyPath(t *testing.T) {
+	mockStatsService := new(mocks.StatsService)
+	handler := rest.StatsHandler{
+		Service: mockStatsService,
+	}
+
+	// --- Unknown Window
+	t.Run("GetTrendingArticles_UnknownWindow", func(t *testing.T) {
+		mockStatsService.
+			On("GetTrendingArticles", mock.Anything, mock.Anything).
+			Return(nil, domain.ErrBadParamInput).
+			Once()
+
+		e := newEcho()
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/articles/trending?window=1y", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.GetTrendingArticles(c)
+		require.Error(t, err)
+		rest.HTTPErrorHandler(err, c)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		mockStatsService.AssertExpectations(t)
+	})
+
+	// --- Malformed Days
+	t.Run("GetArticleStats_MalformedDays", func(t *testing.T) {
+		articleID := uuid.NewString()
+
+		e := newEcho()
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/articles/"+articleID+"/stats?days=week", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(articleID)
+
+		err := handler.GetArticleStats(c)
+		require.Error(t, err)
+		rest.HTTPErrorHandler(err, c)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	// --- Stats Of Someone Else's Article
+	t.Run("GetArticleStats_Forbidden", func(t *testing.T) {
+		id := uuid.New()
+		mockStatsService.
+			On("GetArticleStats", mock.Anything, id, domain.DefaultStatsDays).
+			Return(nil, domain.ErrForbidden).
+			Once()
+
+		e := newEcho()
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/articles/"+id.String()+"/stats", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(id.String())
+
+		err := handler.GetArticleStats(c)
+		require.Error(t, err)
+		rest.HTTPErrorHandler(err, c)
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+		mockStatsService.AssertExpectations(t)
+	})
+}
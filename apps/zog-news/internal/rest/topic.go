@@ -2,9 +2,6 @@ package rest
 
 import (
 	"context"
-	"database/sql"
-	"errors"
-	"fmt"
 	"net/http"
 	"zog-news/domain"
 
@@ -19,30 +16,46 @@ type TopicService interface {
 	CreateTopic(ctx context.Context, topic *domain.CreateTopicRequest) (*domain.Topic, error)
 	GetTopicList(ctx context.Context, filter *domain.TopicFilter) ([]domain.Topic, error)
 	GetTopic(ctx context.Context, id uuid.UUID) (*domain.Topic, error)
+	GetTopicBySlug(ctx context.Context, slug string) (*domain.Topic, error)
 	UpdateTopic(ctx context.Context, id uuid.UUID, topic *domain.Topic) (*domain.Topic, error)
-	DeleteTopic(ctx context.Context, id uuid.UUID) error
+	DeleteTopic(ctx context.Context, id uuid.UUID, version int) error
+	MergeTopics(ctx context.Context, targetID uuid.UUID, req *domain.MergeTopicsRequest) (*domain.Topic, error)
 
-	GetTopicArticles(ctx context.Context, id uuid.UUID) ([]domain.Article, error)
+	GetTopicChildren(ctx context.Context, id uuid.UUID) ([]domain.Topic, error)
+	GetTopicTree(ctx context.Context) ([]domain.Topic, error)
+	GetTopicArticles(ctx context.Context, id uuid.UUID, filter *domain.TopicArticlesFilter) ([]domain.Article, error)
+
+	GetDeletedTopics(ctx context.Context) ([]domain.Topic, error)
+	RestoreTopic(ctx context.Context, id uuid.UUID) (*domain.Topic, error)
+	PurgeTopic(ctx context.Context, id uuid.UUID) error
 }
 
 type TopicHandler struct {
 	Service TopicService
 }
 
-func NewTopicHandler(e *echo.Group, svc TopicService) {
+// NewTopicHandler registers the topic routes, write routes go through auth
+// and topic article lists through readAuth, which lets anonymous callers in
+func NewTopicHandler(e *echo.Group, svc TopicService, auth, readAuth echo.MiddlewareFunc) {
 	handler := &TopicHandler{
 		Service: svc,
 	}
 	topicGroup := e.Group("/topics") // topics group
 
 	topicGroup.GET("", handler.GetTopicList)
+	topicGroup.GET("/tree", handler.GetTopicTree)
+	topicGroup.GET("/by-slug/:slug", handler.GetTopicBySlug)
 	topicGroup.GET("/:id", handler.GetTopic)
-	topicGroup.POST("", handler.CreateTopic)
-	topicGroup.PUT("/:id", handler.UpdateTopic)
-	topicGroup.DELETE("/:id", handler.DeleteTopic)
+	topicGroup.GET("/:id/children", handler.GetTopicChildren)
+	topicGroup.POST("", handler.CreateTopic, auth)
+	topicGroup.PUT("/:id", handler.UpdateTopic, auth)
+	topicGroup.PATCH("/:id", handler.PatchTopic, auth)
+	topicGroup.DELETE("/:id", handler.DeleteTopic, auth)
+	topicGroup.POST("/:id/restore", handler.RestoreTopic, auth)
+	topicGroup.POST("/:id/merge", handler.MergeTopics, auth)
 
 	topicArticlesGroup := topicGroup.Group("/:id/articles")
-	topicArticlesGroup.GET("", handler.GetTopicArticles)
+	topicArticlesGroup.GET("", handler.GetTopicArticles, readAuth)
 }
 
 // GetTopicList retrieves a list of topics with optional filtering
@@ -54,23 +67,18 @@ func NewTopicHandler(e *echo.Group, svc TopicService) {
 //	@Produce		json
 //	@Param			search	query		string										false	"Search in topic name"
 //	@Success		200		{object}	domain.ResponseMultipleData[domain.Topic]	"Successfully retrieved topics list"
-//	@Failure		500		{object}	domain.ResponseMultipleData[domain.Empty]	"Internal server error"
+//	@Failure		500		{object}	domain.Problem								"Internal server error"
 //	@Router			/topics [get]
 func (h *TopicHandler) GetTopicList(c echo.Context) error {
 	filter := new(domain.TopicFilter)
 	if err := c.Bind(filter); err != nil {
-		fmt.Println(err)
+		return badRequest("invalid query parameters")
 	}
 
 	ctx := c.Request().Context()
 	topics, err := h.Service.GetTopicList(ctx, filter)
 	if err != nil {
-		fmt.Println(err)
-		return c.JSON(http.StatusInternalServerError, domain.ResponseMultipleData[domain.Empty]{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Failed to list topics: " + err.Error(),
-		})
+		return err
 	}
 	if topics == nil {
 		topics = []domain.Topic{}
@@ -91,51 +99,81 @@ func (h *TopicHandler) GetTopicList(c echo.Context) error {
 //	@Tags			topics
 //	@Accept			json
 //	@Produce		json
-//	@Param			id	path		string									true	"Topic ID"	format(uuid)
-//	@Success		200	{object}	domain.ResponseSingleData[domain.Topic]	"Successfully retrieved topic"
-//	@Failure		400	{object}	domain.ResponseSingleData[domain.Empty]	"Invalid topic ID format"
-//	@Failure		404	{object}	domain.ResponseSingleData[domain.Empty]	"Topic not found"
-//	@Failure		500	{object}	domain.ResponseSingleData[domain.Empty]	"Internal server error"
+//	@Param			id				path		string									true	"Topic ID"	format(uuid)
+//	@Param			If-None-Match	header		string									false	"ETag of a cached copy"
+//	@Success		200				{object}	domain.ResponseSingleData[domain.Topic]	"Successfully retrieved topic"
+//	@Header			200				{string}	ETag									"Current version of the topic"
+//	@Success		304				"The cached copy is still current"
+//	@Failure		422				{object}	domain.Problem	"Invalid topic ID"
+//	@Failure		404				{object}	domain.Problem	"Topic not found"
+//	@Failure		500				{object}	domain.Problem	"Internal server error"
 //	@Router			/topics/{id} [get]
 func (h *TopicHandler) GetTopic(c echo.Context) error {
 	tracer := otel.Tracer("http.handler.topic")
 	ctx, span := tracer.Start(c.Request().Context(), "GetTopicHandler")
 	defer span.End()
 
-	idParam := c.Param("id")
-	id, err := uuid.Parse(idParam)
+	id, err := paramUUID(c, "id")
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, "invalid UUID")
-		return c.JSON(http.StatusBadRequest, domain.ResponseSingleData[domain.Empty]{
-			Code:    http.StatusBadRequest,
-			Status:  "error",
-			Message: "Invalid topic ID format",
-		})
+		return err
 	}
 
 	span.SetAttributes(attribute.String("topic.id", id.String()))
 	topic, err := h.Service.GetTopic(ctx, id)
 	if err != nil {
 		span.RecordError(err)
-		if errors.Is(err, sql.ErrNoRows) {
-			span.SetStatus(codes.Error, "not found")
-			return c.JSON(http.StatusNotFound, domain.ResponseSingleData[domain.Empty]{
-				Code:    http.StatusNotFound,
-				Status:  "error",
-				Message: "Topic not found",
-			})
-		}
-
 		span.SetStatus(codes.Error, "service error")
-		fmt.Println("GetTopic error:", err)
-		return c.JSON(http.StatusInternalServerError, domain.ResponseSingleData[domain.Empty]{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Failed to get topic: " + err.Error(),
-		})
+		return err
+	}
+
+	if notModified(c, etag(topic.Version)) {
+		return c.NoContent(http.StatusNotModified)
 	}
 
+	c.Response().Header().Set(headerETag, etag(topic.Version))
+	return c.JSON(http.StatusOK, domain.ResponseSingleData[domain.Topic]{
+		Data:    *topic,
+		Code:    http.StatusOK,
+		Status:  "success",
+		Message: "Successfully retrieved topic",
+	})
+}
+
+// GetTopicBySlug retrieves a single topic by its slug
+//
+//	@Summary		Get topic by slug
+//	@Description	Get a single topic by its slug. A slug the topic had before a rename answers with a redirect to the current one
+//	@Tags			topics
+//	@Accept			json
+//	@Produce		json
+//	@Param			slug			path		string									true	"Topic slug"
+//	@Param			If-None-Match	header		string									false	"ETag of a cached copy"
+//	@Success		200				{object}	domain.ResponseSingleData[domain.Topic]	"Successfully retrieved topic"
+//	@Header			200				{string}	ETag									"Current version of the topic"
+//	@Success		301				"The topic moved to the slug in Location"
+//	@Success		304				"The cached copy is still current"
+//	@Failure		404				{object}	domain.Problem	"Topic not found"
+//	@Failure		500				{object}	domain.Problem	"Internal server error"
+//	@Router			/topics/by-slug/{slug} [get]
+func (h *TopicHandler) GetTopicBySlug(c echo.Context) error {
+	slug := c.Param("slug")
+
+	ctx := c.Request().Context()
+	topic, err := h.Service.GetTopicBySlug(ctx, slug)
+	if err != nil {
+		return err
+	}
+
+	if topic.Slug != slug {
+		return c.Redirect(http.StatusMovedPermanently, slugLocation(c, topic.Slug))
+	}
+	if notModified(c, etag(topic.Version)) {
+		return c.NoContent(http.StatusNotModified)
+	}
+
+	c.Response().Header().Set(headerETag, etag(topic.Version))
 	return c.JSON(http.StatusOK, domain.ResponseSingleData[domain.Topic]{
 		Data:    *topic,
 		Code:    http.StatusOK,
@@ -153,28 +191,24 @@ func (h *TopicHandler) GetTopic(c echo.Context) error {
 //	@Produce		json
 //	@Param			topic	body		domain.CreateTopicRequest				true	"Topic creation data"
 //	@Success		201		{object}	domain.ResponseSingleData[domain.Topic]	"Topic successfully created"
-//	@Failure		400		{object}	domain.ResponseSingleData[domain.Empty]	"Invalid request payload"
-//	@Failure		500		{object}	domain.ResponseSingleData[domain.Empty]	"Internal server error"
+//	@Failure		400		{object}	domain.Problem							"Malformed request payload or unknown parent topic"
+//	@Failure		422		{object}	domain.Problem							"Invalid request fields"
+//	@Failure		401		{object}	domain.Problem							"Missing or invalid access token"
+//	@Failure		403		{object}	domain.Problem							"Not allowed for the caller's role"
+//	@Failure		409		{object}	domain.Problem							"Another topic already has this name"
+//	@Failure		500		{object}	domain.Problem							"Internal server error"
+//	@Security		BearerAuth
 //	@Router			/topics [post]
 func (h *TopicHandler) CreateTopic(c echo.Context) error {
 	var topic domain.CreateTopicRequest
-	if err := c.Bind(&topic); err != nil {
-		return c.JSON(http.StatusBadRequest, domain.ResponseSingleData[domain.Empty]{
-			Code:    http.StatusBadRequest,
-			Status:  "error",
-			Message: "Invalid request payload",
-		})
+	if err := bind(c, &topic); err != nil {
+		return err
 	}
 
 	ctx := c.Request().Context()
 	createdTopic, err := h.Service.CreateTopic(ctx, &topic)
 	if err != nil {
-		fmt.Println("CreateTopic error:", err)
-		return c.JSON(http.StatusInternalServerError, domain.ResponseSingleData[domain.Empty]{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Failed to create topic: " + err.Error(),
-		})
+		return err
 	}
 
 	return c.JSON(http.StatusCreated, domain.ResponseSingleData[domain.Topic]{
@@ -188,47 +222,114 @@ func (h *TopicHandler) CreateTopic(c echo.Context) error {
 // UpdateTopic updates an existing topic
 //
 //	@Summary		Update topic
-//	@Description	Update an existing topic by ID with new information
+//	@Description	Update an existing topic by ID with new information. Moving a topic under itself or one of its subtopics is rejected
 //	@Tags			topics
 //	@Accept			json
 //	@Produce		json
-//	@Param			id		path		string									true	"Topic ID"	format(uuid)
-//	@Param			topic	body		domain.UpdateTopicRequest				true	"Topic update data"
-//	@Success		200		{object}	domain.ResponseSingleData[domain.Topic]	"Topic successfully updated"
-//	@Failure		400		{object}	domain.ResponseSingleData[domain.Empty]	"Invalid request payload or topic ID"
-//	@Failure		500		{object}	domain.ResponseSingleData[domain.Empty]	"Internal server error"
+//	@Param			id			path		string									true	"Topic ID"	format(uuid)
+//	@Param			topic		body		domain.UpdateTopicRequest				true	"Topic update data"
+//	@Param			If-Match	header		string									false	"ETag the update is based on"
+//	@Success		200			{object}	domain.ResponseSingleData[domain.Topic]	"Topic successfully updated"
+//	@Header			200			{string}	ETag									"New version of the topic"
+//	@Failure		400			{object}	domain.Problem							"Malformed request payload or unknown parent topic"
+//	@Failure		422			{object}	domain.Problem							"Invalid request fields or topic ID"
+//	@Failure		401			{object}	domain.Problem							"Missing or invalid access token"
+//	@Failure		403			{object}	domain.Problem							"Not allowed for the caller's role"
+//	@Failure		409			{object}	domain.Problem							"Another topic already has this name"
+//	@Failure		412			{object}	domain.Problem							"The topic changed since it was read"
+//	@Failure		500			{object}	domain.Problem							"Internal server error"
+//	@Security		BearerAuth
 //	@Router			/topics/{id} [put]
 func (h *TopicHandler) UpdateTopic(c echo.Context) error {
-	idParam := c.Param("id")
-	id, err := uuid.Parse(idParam)
+	id, err := paramUUID(c, "id")
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, domain.ResponseSingleData[domain.Empty]{
-			Code:    http.StatusBadRequest,
-			Status:  "error",
-			Message: "Invalid topic ID format",
-		})
+		return err
 	}
 
-	var topic domain.Topic
-	if err := c.Bind(&topic); err != nil {
-		return c.JSON(http.StatusBadRequest, domain.ResponseSingleData[domain.Empty]{
-			Code:    http.StatusBadRequest,
-			Status:  "error",
-			Message: "Invalid request payload",
-		})
+	var req domain.UpdateTopicRequest
+	if err := bind(c, &req); err != nil {
+		return err
+	}
+
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		return err
+	}
+
+	return h.saveTopic(c, id, &req, version)
+}
+
+// PatchTopic changes only the fields sent in a JSON merge patch
+//
+//	@Summary		Patch topic
+//	@Description	Change some fields of a topic with a JSON merge patch (RFC 7396). Members left out keep their value, a null parent_id moves the topic to the top level
+//	@Tags			topics
+//	@Accept			application/merge-patch+json
+//	@Produce		json
+//	@Param			id			path		string									true	"Topic ID"	format(uuid)
+//	@Param			topic		body		domain.UpdateTopicRequest				true	"Fields to change"
+//	@Param			If-Match	header		string									false	"ETag the patch is based on"
+//	@Success		200			{object}	domain.ResponseSingleData[domain.Topic]	"Topic successfully updated"
+//	@Header			200			{string}	ETag									"New version of the topic"
+//	@Failure		400			{object}	domain.Problem							"Malformed merge patch or unknown parent topic"
+//	@Failure		401			{object}	domain.Problem							"Missing or invalid access token"
+//	@Failure		403			{object}	domain.Problem							"Not allowed for the caller's role"
+//	@Failure		404			{object}	domain.Problem							"Topic not found"
+//	@Failure		409			{object}	domain.Problem							"Another topic already has this name"
+//	@Failure		412			{object}	domain.Problem							"The topic changed since it was read"
+//	@Failure		415			{object}	domain.Problem							"Body is not a merge patch"
+//	@Failure		422			{object}	domain.Problem							"Invalid fields after applying the patch or topic ID"
+//	@Failure		500			{object}	domain.Problem							"Internal server error"
+//	@Security		BearerAuth
+//	@Router			/topics/{id} [patch]
+func (h *TopicHandler) PatchTopic(c echo.Context) error {
+	id, err := paramUUID(c, "id")
+	if err != nil {
+		return err
+	}
+
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	current, err := h.Service.GetTopic(ctx, id)
+	if err != nil {
+		return err
+	}
+	if version == 0 {
+		// the patch applies to what was just read, a concurrent change fails
+		version = current.Version
+	}
+
+	req := domain.UpdateTopicRequest{
+		Name:     current.Name,
+		ParentID: current.ParentID,
+	}
+	if err := bindMergePatch(c, &req); err != nil {
+		return err
+	}
+
+	return h.saveTopic(c, id, &req, version)
+}
+
+// saveTopic applies a full update of a topic, a non-zero version must match
+// the stored one
+func (h *TopicHandler) saveTopic(c echo.Context, id uuid.UUID, req *domain.UpdateTopicRequest, version int) error {
+	topic := domain.Topic{
+		Name:     req.Name,
+		ParentID: req.ParentID,
+		Version:  version,
 	}
 
 	ctx := c.Request().Context()
 	updatedTopic, err := h.Service.UpdateTopic(ctx, id, &topic)
 	if err != nil {
-		fmt.Println("UpdateTopic error:", err)
-		return c.JSON(http.StatusInternalServerError, domain.ResponseSingleData[domain.Empty]{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Failed to update topic: " + err.Error(),
-		})
+		return err
 	}
 
+	c.Response().Header().Set(headerETag, etag(updatedTopic.Version))
 	return c.JSON(http.StatusOK, domain.ResponseSingleData[domain.Topic]{
 		Data:    *updatedTopic,
 		Code:    http.StatusOK,
@@ -244,72 +345,211 @@ func (h *TopicHandler) UpdateTopic(c echo.Context) error {
 //	@Tags			topics
 //	@Accept			json
 //	@Produce		json
-//	@Param			id	path		string									true	"Topic ID"	format(uuid)
-//	@Success		204	{object}	domain.ResponseSingleData[domain.Empty]	"Topic successfully deleted"
-//	@Failure		400	{object}	domain.ResponseSingleData[domain.Empty]	"Invalid topic ID format"
-//	@Failure		404	{object}	domain.ResponseSingleData[domain.Empty]	"Topic not found"
-//	@Failure		500	{object}	domain.ResponseSingleData[domain.Empty]	"Internal server error"
+//	@Param			id			path		string									true	"Topic ID"	format(uuid)
+//	@Param			If-Match	header		string									false	"ETag the deletion is based on"
+//	@Success		204			{object}	domain.ResponseSingleData[domain.Empty]	"Topic successfully deleted"
+//	@Failure		422			{object}	domain.Problem							"Invalid topic ID"
+//	@Failure		404			{object}	domain.Problem							"Topic not found"
+//	@Failure		401			{object}	domain.Problem							"Missing or invalid access token"
+//	@Failure		403			{object}	domain.Problem							"Not allowed for the caller's role"
+//	@Failure		412			{object}	domain.Problem							"The topic changed since it was read"
+//	@Failure		500			{object}	domain.Problem							"Internal server error"
+//	@Security		BearerAuth
 //	@Router			/topics/{id} [delete]
 func (h *TopicHandler) DeleteTopic(c echo.Context) error {
-	idParam := c.Param("id")
-	id, err := uuid.Parse(idParam)
+	id, err := paramUUID(c, "id")
+	if err != nil {
+		return err
+	}
+
+	version, err := ifMatchVersion(c)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, domain.ResponseSingleData[domain.Empty]{
-			Code:    http.StatusBadRequest,
-			Status:  "error",
-			Message: "Invalid topic ID format",
-		})
+		return err
 	}
 
 	ctx := c.Request().Context()
-	if err := h.Service.DeleteTopic(ctx, id); err != nil {
-		fmt.Println("DeleteTopic error:", err)
-		return c.JSON(http.StatusInternalServerError, domain.ResponseSingleData[domain.Empty]{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Failed to delete topic: " + err.Error(),
-		})
+	if err := h.Service.DeleteTopic(ctx, id, version); err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// RestoreTopic takes a topic out of the trash
+//
+//	@Summary		Restore deleted topic
+//	@Description	Restore a soft-deleted topic together with its article links. Admins only
+//	@Tags			trash
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string									true	"Topic ID"	format(uuid)
+//	@Success		200	{object}	domain.ResponseSingleData[domain.Topic]	"Topic successfully restored"
+//	@Failure		422	{object}	domain.Problem							"Invalid topic ID"
+//	@Failure		401	{object}	domain.Problem							"Missing or invalid access token"
+//	@Failure		403	{object}	domain.Problem							"Not allowed for the caller's role"
+//	@Failure		404	{object}	domain.Problem							"Topic not in the trash"
+//	@Failure		409	{object}	domain.Problem							"A live topic already has this name"
+//	@Failure		500	{object}	domain.Problem							"Internal server error"
+//	@Security		BearerAuth
+//	@Router			/topics/{id}/restore [post]
+func (h *TopicHandler) RestoreTopic(c echo.Context) error {
+	id, err := paramUUID(c, "id")
+	if err != nil {
+		return err
 	}
 
-	return c.JSON(http.StatusNoContent, domain.ResponseSingleData[domain.Empty]{
-		Code:    http.StatusNoContent,
+	ctx := c.Request().Context()
+	topic, err := h.Service.RestoreTopic(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	c.Response().Header().Set(headerETag, etag(topic.Version))
+	return c.JSON(http.StatusOK, domain.ResponseSingleData[domain.Topic]{
+		Data:    *topic,
+		Code:    http.StatusOK,
+		Status:  "success",
+		Message: "Topic successfully restored",
+	})
+}
+
+// MergeTopics folds other topics into a topic
+//
+//	@Summary		Merge topics
+//	@Description	Move the articles, subtopics and slugs of the source topics to the target topic and put the sources in the trash, in one transaction. Admins only
+//	@Tags			topics
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string									true	"Target topic ID"	format(uuid)
+//	@Param			merge	body		domain.MergeTopicsRequest				true	"Topics to merge into the target"
+//	@Success		200		{object}	domain.ResponseSingleData[domain.Topic]	"Topics successfully merged"
+//	@Header			200		{string}	ETag									"New version of the target topic"
+//	@Failure		400		{object}	domain.Problem							"Malformed request payload or unknown source topics"
+//	@Failure		422		{object}	domain.Problem							"Invalid request fields or topic ID"
+//	@Failure		401		{object}	domain.Problem							"Missing or invalid access token"
+//	@Failure		403		{object}	domain.Problem							"Not allowed for the caller's role"
+//	@Failure		404		{object}	domain.Problem							"Target topic not found"
+//	@Failure		500		{object}	domain.Problem							"Internal server error"
+//	@Security		BearerAuth
+//	@Router			/topics/{id}/merge [post]
+func (h *TopicHandler) MergeTopics(c echo.Context) error {
+	id, err := paramUUID(c, "id")
+	if err != nil {
+		return err
+	}
+
+	var req domain.MergeTopicsRequest
+	if err := bind(c, &req); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	topic, err := h.Service.MergeTopics(ctx, id, &req)
+	if err != nil {
+		return err
+	}
+
+	c.Response().Header().Set(headerETag, etag(topic.Version))
+	return c.JSON(http.StatusOK, domain.ResponseSingleData[domain.Topic]{
+		Data:    *topic,
+		Code:    http.StatusOK,
+		Status:  "success",
+		Message: "Topics successfully merged",
+	})
+}
+
+// GetTopicTree retrieves every topic nested under its parent
+//
+//	@Summary		Get topic tree
+//	@Description	Get all topics as a tree, each topic lists its subtopics in children
+//	@Tags			topics
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	domain.ResponseMultipleData[domain.Topic]	"Successfully retrieved topic tree"
+//	@Failure		500	{object}	domain.Problem								"Internal server error"
+//	@Router			/topics/tree [get]
+func (h *TopicHandler) GetTopicTree(c echo.Context) error {
+	ctx := c.Request().Context()
+	tree, err := h.Service.GetTopicTree(ctx)
+	if err != nil {
+		return err
+	}
+	if tree == nil {
+		tree = []domain.Topic{}
+	}
+
+	return c.JSON(http.StatusOK, domain.ResponseMultipleData[domain.Topic]{
+		Data:    tree,
+		Code:    http.StatusOK,
+		Status:  "success",
+		Message: "Successfully retrieved topic tree",
+	})
+}
+
+// GetTopicChildren retrieves the direct subtopics of a topic
+//
+//	@Summary		Get topic children
+//	@Description	Get the direct subtopics of a topic ordered by name
+//	@Tags			topics
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string										true	"Topic ID"	format(uuid)
+//	@Success		200	{object}	domain.ResponseMultipleData[domain.Topic]	"Successfully retrieved subtopics"
+//	@Failure		422	{object}	domain.Problem								"Invalid topic ID"
+//	@Failure		404	{object}	domain.Problem								"Topic not found"
+//	@Failure		500	{object}	domain.Problem								"Internal server error"
+//	@Router			/topics/{id}/children [get]
+func (h *TopicHandler) GetTopicChildren(c echo.Context) error {
+	id, err := paramUUID(c, "id")
+	if err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	children, err := h.Service.GetTopicChildren(ctx, id)
+	if err != nil {
+		return err
+	}
+	if children == nil {
+		children = []domain.Topic{}
+	}
+
+	return c.JSON(http.StatusOK, domain.ResponseMultipleData[domain.Topic]{
+		Data:    children,
+		Code:    http.StatusOK,
 		Status:  "success",
-		Message: "Topic successfully deleted",
+		Message: "Successfully retrieved topic children",
 	})
 }
 
 // GetTopicArticles retrieves articles associated with a topic
 //
 //	@Summary		Get topic articles
-//	@Description	Get all articles associated with a specific topic
+//	@Description	Get all articles associated with a specific topic, optionally including its subtopics
 //	@Tags			topics
 //	@Accept			json
 //	@Produce		json
-//	@Param			id	path		string										true	"Topic ID"	format(uuid)
-//	@Success		200	{object}	domain.ResponseMultipleData[domain.Article]	"Successfully retrieved articles for topic"
-//	@Failure		400	{object}	domain.ResponseSingleData[domain.Empty]		"Invalid topic ID format"
-//	@Failure		500	{object}	domain.ResponseMultipleData[domain.Empty]	"Internal server error"
+//	@Param			id					path		string										true	"Topic ID"	format(uuid)
+//	@Param			include_descendants	query		bool										false	"Also return articles tagged with any subtopic"
+//	@Success		200					{object}	domain.ResponseMultipleData[domain.Article]	"Successfully retrieved articles for topic"
+//	@Failure		422					{object}	domain.Problem								"Invalid topic ID"
+//	@Failure		500					{object}	domain.Problem								"Internal server error"
 //	@Router			/topics/{id}/articles [get]
 func (h *TopicHandler) GetTopicArticles(c echo.Context) error {
-	idParam := c.Param("id")
-	id, err := uuid.Parse(idParam)
+	id, err := paramUUID(c, "id")
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, domain.ResponseSingleData[domain.Empty]{
-			Code:    http.StatusBadRequest,
-			Status:  "error",
-			Message: "Invalid topic ID format",
-		})
+		return err
+	}
+
+	filter := new(domain.TopicArticlesFilter)
+	if err := c.Bind(filter); err != nil {
+		return badRequest("invalid include_descendants value")
 	}
 
 	ctx := c.Request().Context()
-	articles, err := h.Service.GetTopicArticles(ctx, id)
+	articles, err := h.Service.GetTopicArticles(ctx, id, filter)
 	if err != nil {
-		fmt.Println("GetTopicArticles error:", err)
-		return c.JSON(http.StatusInternalServerError, domain.ResponseMultipleData[domain.Empty]{
-			Code:    http.StatusInternalServerError,
-			Status:  "error",
-			Message: "Failed to get topic articles: " + err.Error(),
-		})
+		return err
 	}
 	if articles == nil {
 		articles = []domain.Article{}
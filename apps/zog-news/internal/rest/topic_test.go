@@ -2,10 +2,10 @@ package rest_test
 
 import (
     "bytes"
-    "database/sql"
     "encoding/json"
     "net/http"
     "net/http/httptest"
+    "strings"
     "testing"
     "zog-news/domain"
     "zog-news/internal/rest"
@@ -49,7 +49,7 @@ func TestTopicHappyPath(t *testing.T) {
         body, err := json.Marshal(createReq)
         require.NoError(t, err)
 
-        e := echo.New()
+        e := newEcho()
         req := httptest.NewRequest(http.MethodPost, "/api/v1/topics", bytes.NewReader(body))
         req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
         rec := httptest.NewRecorder()
@@ -84,7 +84,7 @@ func TestTopicHappyPath(t *testing.T) {
         body, err := json.Marshal(updatedTopic)
         require.NoError(t, err)
 
-        e := echo.New()
+        e := newEcho()
         req := httptest.NewRequest(http.MethodPut, "/api/v1/topics/"+newTopic.ID, bytes.NewReader(body))
         req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
         rec := httptest.NewRecorder()
@@ -116,7 +116,7 @@ func TestTopicHappyPath(t *testing.T) {
             Return(&updatedTopic, nil).
             Once()
 
-        e := echo.New()
+        e := newEcho()
         req := httptest.NewRequest(http.MethodGet, "/api/v1/topics/"+newTopic.ID, nil)
         rec := httptest.NewRecorder()
         c := e.NewContext(req, rec)
@@ -142,11 +142,11 @@ func TestTopicHappyPath(t *testing.T) {
         id, err := uuid.Parse(newTopic.ID)
         require.NoError(t, err)
         mockTopicService.
-            On("DeleteTopic", mock.Anything, id).
+            On("DeleteTopic", mock.Anything, id, 0).
             Return(nil).
             Once()
 
-        e := echo.New()
+        e := newEcho()
         req := httptest.NewRequest(http.MethodDelete, "/api/v1/topics/"+newTopic.ID, nil)
         rec := httptest.NewRecorder()
         c := e.NewContext(req, rec)
@@ -166,10 +166,10 @@ func TestTopicHappyPath(t *testing.T) {
         require.NoError(t, err)
         mockTopicService.
             On("GetTopic", mock.Anything, id).
-            Return(nil, sql.ErrNoRows).
+            Return(nil, domain.ErrTopicNotFound).
             Once()
 
-        e := echo.New()
+        e := newEcho()
         req := httptest.NewRequest(http.MethodGet, "/api/v1/topics/"+newTopic.ID, nil)
         rec := httptest.NewRecorder()
         c := e.NewContext(req, rec)
@@ -177,17 +177,18 @@ func TestTopicHappyPath(t *testing.T) {
         c.SetParamValues(newTopic.ID)
 
         err = handler.GetTopic(c)
-        require.NoError(t, err)
+        require.Error(t, err)
+        rest.HTTPErrorHandler(err, c)
 
         assert.Equal(t, http.StatusNotFound, rec.Code)
 
-        var resp domain.ResponseSingleData[domain.Empty]
+        var resp domain.Problem
         err = json.Unmarshal(rec.Body.Bytes(), &resp)
         require.NoError(t, err)
 
-        assert.Equal(t, "error", resp.Status)
-        assert.Equal(t, "Topic not found", resp.Message)
-        assert.Equal(t, http.StatusNotFound, resp.Code)
+        assert.Equal(t, "topic_not_found", resp.Code)
+        assert.Equal(t, "topic not found", resp.Detail)
+        assert.Equal(t, http.StatusNotFound, resp.Status)
 
         mockTopicService.AssertExpectations(t)
     })
@@ -214,10 +215,10 @@ func TestTopicUnhappyPath(t *testing.T) {
         require.NoError(t, err)
         mockTopicService.
             On("GetTopic", mock.Anything, id).
-            Return(nil, sql.ErrNoRows).
+            Return(nil, domain.ErrTopicNotFound).
             Once()
 
-        e := echo.New()
+        e := newEcho()
         req := httptest.NewRequest(http.MethodGet, "/api/v1/topics/"+newTopic.ID, nil)
         rec := httptest.NewRecorder()
         c := e.NewContext(req, rec)
@@ -225,17 +226,18 @@ func TestTopicUnhappyPath(t *testing.T) {
         c.SetParamValues(newTopic.ID)
 
         err = handler.GetTopic(c)
-        require.NoError(t, err)
+        require.Error(t, err)
+        rest.HTTPErrorHandler(err, c)
 
         assert.Equal(t, http.StatusNotFound, rec.Code)
 
-        var resp domain.ResponseSingleData[domain.Empty]
+        var resp domain.Problem
         err = json.Unmarshal(rec.Body.Bytes(), &resp)
         require.NoError(t, err)
 
-        assert.Equal(t, "error", resp.Status)
-        assert.Equal(t, "Topic not found", resp.Message)
-        assert.Equal(t, http.StatusNotFound, resp.Code)
+        assert.Equal(t, "topic_not_found", resp.Code)
+        assert.Equal(t, "topic not found", resp.Detail)
+        assert.Equal(t, http.StatusNotFound, resp.Status)
 
         mockTopicService.AssertExpectations(t)
     })
@@ -246,21 +248,359 @@ func TestTopicUnhappyPath(t *testing.T) {
             "Name": "",
         }`)
 
-        e := echo.New()
+        e := newEcho()
         req := httptest.NewRequest(http.MethodPost, "/api/v1/topics", bytes.NewReader(body))
         req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
         rec := httptest.NewRecorder()
         c := e.NewContext(req, rec)
 
         err := handler.CreateTopic(c)
-        require.NoError(t, err)
+        require.Error(t, err)
+        rest.HTTPErrorHandler(err, c)
 
         assert.Equal(t, http.StatusBadRequest, rec.Code)
 
-        var resp domain.ResponseSingleData[domain.Empty]
+        var resp domain.Problem
+        err = json.Unmarshal(rec.Body.Bytes(), &resp)
+        require.NoError(t, err)
+        assert.Equal(t, "bad_request", resp.Code)
+        assert.NotEmpty(t, resp.Detail)
+    })
+
+    t.Run("CreateTopic_NameTooLong", func(t *testing.T) {
+        body := []byte(`{"name": "` + strings.Repeat("t", 65) + `"}`)
+
+        e := newEcho()
+        req := httptest.NewRequest(http.MethodPost, "/api/v1/topics", bytes.NewReader(body))
+        req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+        rec := httptest.NewRecorder()
+        c := e.NewContext(req, rec)
+
+        err := handler.CreateTopic(c)
+        require.Error(t, err)
+        rest.HTTPErrorHandler(err, c)
+
+        assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+        var resp domain.Problem
         err = json.Unmarshal(rec.Body.Bytes(), &resp)
         require.NoError(t, err)
-        assert.Equal(t, "error", resp.Status)
-        assert.NotEmpty(t, resp.Message)
+        assert.Equal(t, []domain.FieldError{{Field: "name", Reason: "max", Param: "64"}}, resp.Errors)
+    })
+}
+
+func TestTopicHierarchy(t *testing.T) {
+    t.Parallel()
+
+    mockTopicService := new(mocks.TopicService)
+    handler := rest.TopicHandler{
+        Service: mockTopicService,
+    }
+
+    parentID := "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+    childID := "d4b8583d-5038-4838-bcd7-3d8dddfedd6a"
+
+    t.Run("GetTopicTree", func(t *testing.T) {
+        tree := domain.BuildTopicTree([]domain.Topic{
+            {ID: childID, Name: "AI", ParentID: &parentID},
+            {ID: parentID, Name: "Technology"},
+        })
+        mockTopicService.
+            On("GetTopicTree", mock.Anything).
+            Return(tree, nil).
+            Once()
+
+        e := newEcho()
+        req := httptest.NewRequest(http.MethodGet, "/api/v1/topics/tree", nil)
+        rec := httptest.NewRecorder()
+        c := e.NewContext(req, rec)
+
+        err := handler.GetTopicTree(c)
+        require.NoError(t, err)
+        assert.Equal(t, http.StatusOK, rec.Code)
+
+        var resp domain.ResponseMultipleData[domain.Topic]
+        require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+        require.Len(t, resp.Data, 1)
+        assert.Equal(t, "Technology", resp.Data[0].Name)
+        require.Len(t, resp.Data[0].Children, 1)
+        assert.Equal(t, "AI", resp.Data[0].Children[0].Name)
+
+        mockTopicService.AssertExpectations(t)
+    })
+
+    t.Run("GetTopicChildren_NotFound", func(t *testing.T) {
+        id := uuid.MustParse(parentID)
+        mockTopicService.
+            On("GetTopicChildren", mock.Anything, id).
+            Return(nil, domain.ErrTopicNotFound).
+            Once()
+
+        e := newEcho()
+        req := httptest.NewRequest(http.MethodGet, "/api/v1/topics/"+parentID+"/children", nil)
+        rec := httptest.NewRecorder()
+        c := e.NewContext(req, rec)
+        c.SetParamNames("id")
+        c.SetParamValues(parentID)
+
+        err := handler.GetTopicChildren(c)
+        require.Error(t, err)
+        rest.HTTPErrorHandler(err, c)
+        assert.Equal(t, http.StatusNotFound, rec.Code)
+
+        mockTopicService.AssertExpectations(t)
+    })
+
+    t.Run("UpdateTopic_Cycle", func(t *testing.T) {
+        id := uuid.MustParse(parentID)
+        mockTopicService.
+            On("UpdateTopic", mock.Anything, id, mock.AnythingOfType("*domain.Topic")).
+            Return(nil, domain.ErrBadParamInput).
+            Once()
+
+        body := []byte(`{"name": "Technology", "parent_id": "` + childID + `"}`)
+
+        e := newEcho()
+        req := httptest.NewRequest(http.MethodPut, "/api/v1/topics/"+parentID, bytes.NewReader(body))
+        req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+        rec := httptest.NewRecorder()
+        c := e.NewContext(req, rec)
+        c.SetParamNames("id")
+        c.SetParamValues(parentID)
+
+        err := handler.UpdateTopic(c)
+        require.Error(t, err)
+        rest.HTTPErrorHandler(err, c)
+        assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+        mockTopicService.AssertExpectations(t)
+    })
+
+    t.Run("GetTopicArticles_IncludeDescendants", func(t *testing.T) {
+        id := uuid.MustParse(parentID)
+        filter := &domain.TopicArticlesFilter{IncludeDescendants: true}
+        mockTopicService.
+            On("GetTopicArticles", mock.Anything, id, filter).
+            Return([]domain.Article{{ID: childID, Title: "Robots"}}, nil).
+            Once()
+
+        e := newEcho()
+        req := httptest.NewRequest(http.MethodGet, "/api/v1/topics/"+parentID+"/articles?include_descendants=true", nil)
+        rec := httptest.NewRecorder()
+        c := e.NewContext(req, rec)
+        c.SetParamNames("id")
+        c.SetParamValues(parentID)
+
+        err := handler.GetTopicArticles(c)
+        require.NoError(t, err)
+        assert.Equal(t, http.StatusOK, rec.Code)
+
+        var resp domain.ResponseMultipleData[domain.Article]
+        require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+        assert.Len(t, resp.Data, 1)
+
+        mockTopicService.AssertExpectations(t)
     })
 }
+
+func TestTopicSlugs(t *testing.T) {
+    t.Parallel()
+
+    mockTopicService := new(mocks.TopicService)
+    handler := rest.TopicHandler{
+        Service: mockTopicService,
+    }
+
+    topic := domain.Topic{
+        ID:      "550e8400-e29b-41d4-a716-446655440000",
+        Name:    "Artificial Intelligence",
+        Slug:    "artificial-intelligence",
+        Version: 3,
+    }
+
+    t.Run("GetTopicBySlug_Redirect", func(t *testing.T) {
+        mockTopicService.
+            On("GetTopicBySlug", mock.Anything, "ai").
+            Return(&topic, nil).
+            Once()
+
+        e := newEcho()
+        req := httptest.NewRequest(http.MethodGet, "/api/v1/topics/by-slug/ai", nil)
+        rec := httptest.NewRecorder()
+        c := e.NewContext(req, rec)
+        c.SetParamNames("slug")
+        c.SetParamValues("ai")
+
+        err := handler.GetTopicBySlug(c)
+        require.NoError(t, err)
+
+        assert.Equal(t, http.StatusMovedPermanently, rec.Code)
+        assert.Equal(t, "/api/v1/topics/by-slug/artificial-intelligence", rec.Header().Get(echo.HeaderLocation))
+
+        mockTopicService.AssertExpectations(t)
+    })
+
+    t.Run("GetTopicBySlug_NotModified", func(t *testing.T) {
+        mockTopicService.
+            On("GetTopicBySlug", mock.Anything, topic.Slug).
+            Return(&topic, nil).
+            Once()
+
+        e := newEcho()
+        req := httptest.NewRequest(http.MethodGet, "/api/v1/topics/by-slug/"+topic.Slug, nil)
+        req.Header.Set("If-None-Match", `"3"`)
+        rec := httptest.NewRecorder()
+        c := e.NewContext(req, rec)
+        c.SetParamNames("slug")
+        c.SetParamValues(topic.Slug)
+
+        err := handler.GetTopicBySlug(c)
+        require.NoError(t, err)
+
+        assert.Equal(t, http.StatusNotModified, rec.Code)
+
+        mockTopicService.AssertExpectations(t)
+    })
+}
+
+func TestTopicMerge(t *testing.T) {
+    t.Parallel()
+
+    mockTopicService := new(mocks.TopicService)
+    handler := rest.TopicHandler{
+        Service: mockTopicService,
+    }
+
+    targetID := "550e8400-e29b-41d4-a716-446655440000"
+    sourceID := "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+
+    t.Run("MergeTopics", func(t *testing.T) {
+        merged := domain.Topic{ID: targetID, Name: "Technology", Slug: "technology", Version: 4}
+        req := &domain.MergeTopicsRequest{SourceIDs: []string{sourceID}}
+        mockTopicService.
+            On("MergeTopics", mock.Anything, uuid.MustParse(targetID), req).
+            Return(&merged, nil).
+            Once()
+
+        body, err := json.Marshal(req)
+        require.NoError(t, err)
+
+        e := newEcho()
+        httpReq := httptest.NewRequest(http.MethodPost, "/api/v1/topics/"+targetID+"/merge", bytes.NewReader(body))
+        httpReq.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+        rec := httptest.NewRecorder()
+        c := e.NewContext(httpReq, rec)
+        c.SetParamNames("id")
+        c.SetParamValues(targetID)
+
+        err = handler.MergeTopics(c)
+        require.NoError(t, err)
+
+        assert.Equal(t, http.StatusOK, rec.Code)
+        assert.Equal(t, `"4"`, rec.Header().Get("ETag"))
+
+        mockTopicService.AssertExpectations(t)
+    })
+
+    t.Run("MergeTopics_MissingSources", func(t *testing.T) {
+        e := newEcho()
+        httpReq := httptest.NewRequest(http.MethodPost, "/api/v1/topics/"+targetID+"/merge", bytes.NewReader([]byte(`{"source_ids": []}`)))
+        httpReq.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+        rec := httptest.NewRecorder()
+        c := e.NewContext(httpReq, rec)
+        c.SetParamNames("id")
+        c.SetParamValues(targetID)
+
+        err := handler.MergeTopics(c)
+        require.Error(t, err)
+        rest.HTTPErrorHandler(err, c)
+
+        assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+        var resp domain.Problem
+        require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+        assert.Equal(t, []domain.FieldError{{Field: "source_ids", Reason: "min", Param: "1"}}, resp.Errors)
+    })
+
+    t.Run("CreateTopic_DuplicateName", func(t *testing.T) {
+        createReq := domain.CreateTopicRequest{Name: "technology"}
+        mockTopicService.
+            On("CreateTopic", mock.Anything, &createReq).
+            Return(nil, domain.ErrConflict).
+            Once()
+
+        body, err := json.Marshal(createReq)
+        require.NoError(t, err)
+
+        e := newEcho()
+        httpReq := httptest.NewRequest(http.MethodPost, "/api/v1/topics", bytes.NewReader(body))
+        httpReq.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+        rec := httptest.NewRecorder()
+        c := e.NewContext(httpReq, rec)
+
+        err = handler.CreateTopic(c)
+        require.Error(t, err)
+        rest.HTTPErrorHandler(err, c)
+
+        assert.Equal(t, http.StatusConflict, rec.Code)
+
+        mockTopicService.AssertExpectations(t)
+    })
+}
+
+func TestTopicPatch(t *testing.T) {
+    t.Parallel()
+
+    mockTopicService := new(mocks.TopicService)
+    handler := rest.TopicHandler{
+        Service: mockTopicService,
+    }
+
+    topicID := "d4b8583d-5038-4838-bcd7-3d8dddfedd6a"
+    parentID := "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+    id := uuid.MustParse(topicID)
+    current := domain.Topic{
+        ID:       topicID,
+        Name:     "Technology",
+        ParentID: &parentID,
+        Version:  2,
+    }
+
+    t.Run("PatchTopic_MoveToTopLevel", func(t *testing.T) {
+        moved := current
+        moved.ParentID = nil
+        moved.Version = 3
+
+        mockTopicService.
+            On("GetTopic", mock.Anything, id).
+            Return(&current, nil).
+            Once()
+        mockTopicService.
+            On("UpdateTopic", mock.Anything, id, mock.MatchedBy(func(u *domain.Topic) bool {
+                return u.Name == current.Name && u.ParentID == nil && u.Version == 2
+            })).
+            Return(&moved, nil).
+            Once()
+
+        e := newEcho()
+        req := httptest.NewRequest(http.MethodPatch, "/api/v1/topics/"+topicID, bytes.NewReader([]byte(`{"parent_id": null}`)))
+        req.Header.Set(echo.HeaderContentType, "application/merge-patch+json")
+        req.Header.Set("If-Match", `"2"`)
+        rec := httptest.NewRecorder()
+        c := e.NewContext(req, rec)
+        c.SetParamNames("id")
+        c.SetParamValues(topicID)
+
+        err := handler.PatchTopic(c)
+        require.NoError(t, err)
+
+        assert.Equal(t, http.StatusOK, rec.Code)
+
+        var resp domain.ResponseSingleData[domain.Topic]
+        require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+        assert.Nil(t, resp.Data.ParentID)
+
+        mockTopicService.AssertExpectations(t)
+    })
+}
+
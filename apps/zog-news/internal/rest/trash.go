@@ -0,0 +1,150 @@
+package rest
+
+import (
+	"net/http"
+	"zog-news/domain"
+
+	"github.com/labstack/echo/v4"
+)
+
+type TrashHandler struct {
+	Articles ArticleService
+	Topics   TopicService
+}
+
+// NewTrashHandler registers the routes listing and purging soft-deleted
+// articles and topics, every route goes through auth
+func NewTrashHandler(e *echo.Group, articles ArticleService, topics TopicService, auth echo.MiddlewareFunc) {
+	handler := &TrashHandler{
+		Articles: articles,
+		Topics:   topics,
+	}
+	trashGroup := e.Group("/trash", auth) // trash group
+
+	trashGroup.GET("/articles", handler.GetDeletedArticles)
+	trashGroup.DELETE("/articles/:id", handler.PurgeArticle)
+	trashGroup.GET("/topics", handler.GetDeletedTopics)
+	trashGroup.DELETE("/topics/:id", handler.PurgeTopic)
+}
+
+// GetDeletedArticles lists the articles in the trash
+//
+//	@Summary		Get deleted articles
+//	@Description	List soft-deleted articles, most recently deleted first. Editors only
+//	@Tags			trash
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	domain.ResponseMultipleData[domain.Article]	"Successfully retrieved deleted articles"
+//	@Failure		401	{object}	domain.Problem								"Missing or invalid access token"
+//	@Failure		403	{object}	domain.Problem								"Not allowed for the caller's role"
+//	@Failure		500	{object}	domain.Problem								"Internal server error"
+//	@Security		BearerAuth
+//	@Router			/trash/articles [get]
+func (h *TrashHandler) GetDeletedArticles(c echo.Context) error {
+	ctx := c.Request().Context()
+	articles, err := h.Articles.GetDeletedArticles(ctx)
+	if err != nil {
+		return err
+	}
+	if articles == nil {
+		articles = []domain.Article{}
+	}
+
+	return c.JSON(http.StatusOK, domain.ResponseMultipleData[domain.Article]{
+		Data:    articles,
+		Code:    http.StatusOK,
+		Status:  "success",
+		Message: "Successfully retrieved deleted articles",
+	})
+}
+
+// PurgeArticle permanently removes an article from the trash
+//
+//	@Summary		Purge deleted article
+//	@Description	Permanently remove a soft-deleted article with its topic links and revisions. Admins only
+//	@Tags			trash
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path	string	true	"Article ID"	format(uuid)
+//	@Success		204	"Article permanently removed"
+//	@Failure		422	{object}	domain.Problem	"Invalid article ID"
+//	@Failure		401	{object}	domain.Problem	"Missing or invalid access token"
+//	@Failure		403	{object}	domain.Problem	"Not allowed for the caller's role"
+//	@Failure		404	{object}	domain.Problem	"Article not in the trash"
+//	@Failure		500	{object}	domain.Problem	"Internal server error"
+//	@Security		BearerAuth
+//	@Router			/trash/articles/{id} [delete]
+func (h *TrashHandler) PurgeArticle(c echo.Context) error {
+	id, err := paramUUID(c, "id")
+	if err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	if err := h.Articles.PurgeArticle(ctx, id); err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// GetDeletedTopics lists the topics in the trash
+//
+//	@Summary		Get deleted topics
+//	@Description	List soft-deleted topics, most recently deleted first. Admins only
+//	@Tags			trash
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	domain.ResponseMultipleData[domain.Topic]	"Successfully retrieved deleted topics"
+//	@Failure		401	{object}	domain.Problem								"Missing or invalid access token"
+//	@Failure		403	{object}	domain.Problem								"Not allowed for the caller's role"
+//	@Failure		500	{object}	domain.Problem								"Internal server error"
+//	@Security		BearerAuth
+//	@Router			/trash/topics [get]
+func (h *TrashHandler) GetDeletedTopics(c echo.Context) error {
+	ctx := c.Request().Context()
+	topics, err := h.Topics.GetDeletedTopics(ctx)
+	if err != nil {
+		return err
+	}
+	if topics == nil {
+		topics = []domain.Topic{}
+	}
+
+	return c.JSON(http.StatusOK, domain.ResponseMultipleData[domain.Topic]{
+		Data:    topics,
+		Code:    http.StatusOK,
+		Status:  "success",
+		Message: "Successfully retrieved deleted topics",
+	})
+}
+
+// PurgeTopic permanently removes a topic from the trash
+//
+//	@Summary		Purge deleted topic
+//	@Description	Permanently remove a soft-deleted topic with its article links. Admins only
+//	@Tags			trash
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path	string	true	"Topic ID"	format(uuid)
+//	@Success		204	"Topic permanently removed"
+//	@Failure		422	{object}	domain.Problem	"Invalid topic ID"
+//	@Failure		401	{object}	domain.Problem	"Missing or invalid access token"
+//	@Failure		403	{object}	domain.Problem	"Not allowed for the caller's role"
+//	@Failure		404	{object}	domain.Problem	"Topic not in the trash"
+//	@Failure		500	{object}	domain.Problem	"Internal server error"
+//	@Security		BearerAuth
+//	@Router			/trash/topics/{id} [delete]
+func (h *TrashHandler) PurgeTopic(c echo.Context) error {
+	id, err := paramUUID(c, "id")
+	if err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	if err := h.Topics.PurgeTopic(ctx, id); err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,141 @@
+package rest_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"zog-news/domain"
+	"zog-news/internal/rest"
+	"zog-news/internal/rest/mocks"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrash(t *testing.T) {
+	t.Parallel()
+
+	mockArticleService := new(mocks.ArticleService)
+	mockTopicService := new(mocks.TopicService)
+
+	deletedAt := time.Date(2025, 7, 1, 9, 0, 0, 0, time.UTC)
+	deletedArticle := domain.Article{
+		ID:        "d4b8583d-5038-4838-bcd7-3d8dddfedd6a",
+		Title:     "Test judul",
+		Content:   "Test content",
+		Status:    domain.StatusDraft,
+		Version:   2,
+		DeletedAt: &deletedAt,
+	}
+	topicID := "550e8400-e29b-41d4-a716-446655440000"
+
+	trashHandler := rest.TrashHandler{
+		Articles: mockArticleService,
+		Topics:   mockTopicService,
+	}
+	articleHandler := rest.ArticleHandler{
+		Service: mockArticleService,
+	}
+
+	// --- List Deleted Articles
+	t.Run("GetDeletedArticles", func(t *testing.T) {
+		mockArticleService.
+			On("GetDeletedArticles", mock.Anything).
+			Return([]domain.Article{deletedArticle}, nil).
+			Once()
+
+		e := newEcho()
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/trash/articles", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := trashHandler.GetDeletedArticles(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var resp domain.ResponseMultipleData[domain.Article]
+		err = json.Unmarshal(rec.Body.Bytes(), &resp)
+		require.NoError(t, err)
+		require.Len(t, resp.Data, 1)
+		assert.True(t, resp.Data[0].DeletedAt.Equal(deletedAt))
+
+		mockArticleService.AssertExpectations(t)
+	})
+
+	// --- Restore Article
+	t.Run("RestoreArticle", func(t *testing.T) {
+		restored := deletedArticle
+		restored.DeletedAt = nil
+		restored.Version = 3
+		mockArticleService.
+			On("RestoreArticle", mock.Anything, uuid.MustParse(deletedArticle.ID)).
+			Return(&restored, nil).
+			Once()
+
+		e := newEcho()
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/articles/"+deletedArticle.ID+"/restore", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(deletedArticle.ID)
+
+		err := articleHandler.RestoreArticle(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, `"3"`, rec.Header().Get("ETag"))
+
+		mockArticleService.AssertExpectations(t)
+	})
+
+	// --- Purge Without Permission
+	t.Run("PurgeTopic_Forbidden", func(t *testing.T) {
+		mockTopicService.
+			On("PurgeTopic", mock.Anything, uuid.MustParse(topicID)).
+			Return(domain.ErrForbidden).
+			Once()
+
+		e := newEcho()
+		req := httptest.NewRequest(http.MethodDelete, "/api/v1/trash/topics/"+topicID, nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(topicID)
+
+		err := trashHandler.PurgeTopic(c)
+		require.Error(t, err)
+		rest.HTTPErrorHandler(err, c)
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+
+		mockTopicService.AssertExpectations(t)
+	})
+
+	// --- Purge Topic Not In Trash
+	t.Run("PurgeTopic_NotFound", func(t *testing.T) {
+		mockTopicService.
+			On("PurgeTopic", mock.Anything, uuid.MustParse(topicID)).
+			Return(domain.ErrTopicNotFound).
+			Once()
+
+		e := newEcho()
+		req := httptest.NewRequest(http.MethodDelete, "/api/v1/trash/topics/"+topicID, nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(topicID)
+
+		err := trashHandler.PurgeTopic(c)
+		require.Error(t, err)
+		rest.HTTPErrorHandler(err, c)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+
+		mockTopicService.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,107 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"zog-news/domain"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type UserService interface {
+	GetCurrentUser(ctx context.Context) (*domain.User, error)
+	UpdateUserRole(ctx context.Context, id uuid.UUID, role domain.Role) (*domain.User, error)
+}
+
+type UserHandler struct {
+	Service UserService
+}
+
+// NewUserHandler registers the user routes, all of them require auth
+func NewUserHandler(e *echo.Group, svc UserService, auth echo.MiddlewareFunc) {
+	handler := &UserHandler{
+		Service: svc,
+	}
+	userGroup := e.Group("/users", auth) // users group
+
+	userGroup.GET("/me", handler.GetCurrentUser)
+	userGroup.PUT("/:id/role", handler.UpdateUserRole)
+}
+
+// GetCurrentUser retrieves the authenticated user
+//
+//	@Summary		Get current user
+//	@Description	Get the account of the user the access token belongs to
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	domain.ResponseSingleData[domain.User]	"Successfully retrieved user"
+//	@Failure		401	{object}	domain.Problem							"Missing or invalid access token"
+//	@Failure		404	{object}	domain.Problem							"User not found"
+//	@Failure		500	{object}	domain.Problem							"Internal server error"
+//	@Security		BearerAuth
+//	@Router			/users/me [get]
+func (h *UserHandler) GetCurrentUser(c echo.Context) error {
+	tracer := otel.Tracer("http.handler.user")
+	ctx, span := tracer.Start(c.Request().Context(), "GetCurrentUserHandler")
+	defer span.End()
+
+	user, err := h.Service.GetCurrentUser(ctx)
+	if err != nil {
+		return err
+	}
+
+	span.SetAttributes(attribute.String("user.id", user.ID))
+	return c.JSON(http.StatusOK, domain.ResponseSingleData[domain.User]{
+		Data:    *user,
+		Code:    http.StatusOK,
+		Status:  "success",
+		Message: "Successfully retrieved user",
+	})
+}
+
+// UpdateUserRole changes the role of a user
+//
+//	@Summary		Update user role
+//	@Description	Change the role of a user, admin only. The new role applies from the user's next login or token refresh
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string									true	"User ID"	format(uuid)
+//	@Param			role	body		domain.UpdateUserRoleRequest			true	"New role"
+//	@Success		200		{object}	domain.ResponseSingleData[domain.User]	"User role successfully updated"
+//	@Failure		400		{object}	domain.Problem							"Malformed request payload"
+//	@Failure		422		{object}	domain.Problem							"Invalid request fields or user ID"
+//	@Failure		401		{object}	domain.Problem							"Missing or invalid access token"
+//	@Failure		403		{object}	domain.Problem							"Not allowed for the caller's role"
+//	@Failure		404		{object}	domain.Problem							"User not found"
+//	@Failure		500		{object}	domain.Problem							"Internal server error"
+//	@Security		BearerAuth
+//	@Router			/users/{id}/role [put]
+func (h *UserHandler) UpdateUserRole(c echo.Context) error {
+	id, err := paramUUID(c, "id")
+	if err != nil {
+		return err
+	}
+
+	var req domain.UpdateUserRoleRequest
+	if err := bind(c, &req); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	user, err := h.Service.UpdateUserRole(ctx, id, req.Role)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, domain.ResponseSingleData[domain.User]{
+		Data:    *user,
+		Code:    http.StatusOK,
+		Status:  "success",
+		Message: "User role successfully updated",
+	})
+}
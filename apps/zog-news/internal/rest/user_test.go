@@ -0,0 +1,131 @@
+package rest_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"zog-news/domain"
+	"zog-news/internal/rest"
+	"zog-news/internal/rest/mocks"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserHappyPath(t *testing.T) {
+	t.Parallel()
+
+	mockUserService := new(mocks.UserService)
+
+	user := domain.User{
+		ID:    "7f1c2a9e-3b4d-4c5e-8f6a-9b0c1d2e3f4a",
+		Name:  "John Doe",
+		Email: "john@example.com",
+		Role:  domain.RoleAuthor,
+	}
+
+	handler := rest.UserHandler{
+		Service: mockUserService,
+	}
+
+	// --- Get Current User
+	t.Run("GetCurrentUser", func(t *testing.T) {
+		mockUserService.
+			On("GetCurrentUser", mock.Anything).
+			Return(&user, nil).
+			Once()
+
+		e := newEcho()
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/users/me", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.GetCurrentUser(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var resp domain.ResponseSingleData[domain.User]
+		err = json.Unmarshal(rec.Body.Bytes(), &resp)
+		require.NoError(t, err)
+		assert.Equal(t, user, resp.Data)
+
+		mockUserService.AssertExpectations(t)
+	})
+
+	// --- Promote User
+	t.Run("UpdateUserRole", func(t *testing.T) {
+		id, err := uuid.Parse(user.ID)
+		require.NoError(t, err)
+
+		promoted := user
+		promoted.Role = domain.RoleEditor
+		mockUserService.
+			On("UpdateUserRole", mock.Anything, id, domain.RoleEditor).
+			Return(&promoted, nil).
+			Once()
+
+		body, err := json.Marshal(domain.UpdateUserRoleRequest{Role: domain.RoleEditor})
+		require.NoError(t, err)
+
+		e := newEcho()
+		req := httptest.NewRequest(http.MethodPut, "/api/v1/users/"+user.ID+"/role", bytes.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(user.ID)
+
+		err = handler.UpdateUserRole(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var resp domain.ResponseSingleData[domain.User]
+		err = json.Unmarshal(rec.Body.Bytes(), &resp)
+		require.NoError(t, err)
+		assert.Equal(t, promoted, resp.Data)
+
+		mockUserService.AssertExpectations(t)
+	})
+}
+
+func TestUserUnhappyPath(t *testing.T) {
+	mockUserService := new(mocks.UserService)
+
+	handler := rest.UserHandler{
+		Service: mockUserService,
+	}
+
+	// --- Non-Admin Changing Roles
+	t.Run("UpdateUserRole_Forbidden", func(t *testing.T) {
+		id := uuid.New()
+		mockUserService.
+			On("UpdateUserRole", mock.Anything, id, domain.RoleAdmin).
+			Return(nil, domain.ErrForbidden).
+			Once()
+
+		body, err := json.Marshal(domain.UpdateUserRoleRequest{Role: domain.RoleAdmin})
+		require.NoError(t, err)
+
+		e := newEcho()
+		req := httptest.NewRequest(http.MethodPut, "/api/v1/users/"+id.String()+"/role", bytes.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(id.String())
+
+		err = handler.UpdateUserRole(c)
+		require.Error(t, err)
+		rest.HTTPErrorHandler(err, c)
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+		mockUserService.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,208 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"zog-news/domain"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type WebhookService interface {
+	CreateWebhook(ctx context.Context, r *domain.CreateWebhookRequest) (*domain.Webhook, error)
+	GetWebhookList(ctx context.Context) ([]domain.Webhook, error)
+	GetWebhook(ctx context.Context, id uuid.UUID) (*domain.Webhook, error)
+	DeleteWebhook(ctx context.Context, id uuid.UUID) error
+	GetWebhookDeliveries(ctx context.Context, id uuid.UUID) ([]domain.WebhookDelivery, error)
+}
+
+type WebhookHandler struct {
+	Service WebhookService
+}
+
+// NewWebhookHandler registers the webhook routes, all of them require auth
+func NewWebhookHandler(e *echo.Group, svc WebhookService, auth echo.MiddlewareFunc) {
+	handler := &WebhookHandler{
+		Service: svc,
+	}
+	webhookGroup := e.Group("/webhooks", auth) // webhooks group
+
+	webhookGroup.POST("", handler.CreateWebhook)
+	webhookGroup.GET("", handler.GetWebhookList)
+	webhookGroup.GET("/:id", handler.GetWebhook)
+	webhookGroup.DELETE("/:id", handler.DeleteWebhook)
+	webhookGroup.GET("/:id/deliveries", handler.GetWebhookDeliveries)
+}
+
+// CreateWebhook registers a webhook
+//
+//	@Summary		Create webhook
+//	@Description	Register a URL events of the given types are POSTed to, "*" subscribes to every type. Each delivery carries an X-Signature header "t=<unix>,sha256=<hex>", the HMAC-SHA256 of "<unix>.<body>" keyed with the returned secret. The secret is only returned here. Admins only
+//	@Tags			webhooks
+//	@Accept			json
+//	@Produce		json
+//	@Param			webhook	body		domain.CreateWebhookRequest					true	"Webhook registration data"
+//	@Success		201		{object}	domain.ResponseSingleData[domain.Webhook]	"Webhook successfully created"
+//	@Failure		400		{object}	domain.Problem								"Malformed request payload or URL"
+//	@Failure		422		{object}	domain.Problem								"Invalid request fields"
+//	@Failure		401		{object}	domain.Problem								"Missing or invalid access token"
+//	@Failure		403		{object}	domain.Problem								"Not allowed for the caller's role"
+//	@Failure		500		{object}	domain.Problem								"Internal server error"
+//	@Security		BearerAuth
+//	@Router			/webhooks [post]
+func (h *WebhookHandler) CreateWebhook(c echo.Context) error {
+	var req domain.CreateWebhookRequest
+	if err := bind(c, &req); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	webhook, err := h.Service.CreateWebhook(ctx, &req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, domain.ResponseSingleData[domain.Webhook]{
+		Data:    *webhook,
+		Code:    http.StatusCreated,
+		Status:  "success",
+		Message: "Webhook successfully created",
+	})
+}
+
+// GetWebhookList lists the registered webhooks
+//
+//	@Summary		Get webhooks
+//	@Description	List the registered webhooks, oldest first, without their secrets. Admins only
+//	@Tags			webhooks
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	domain.ResponseMultipleData[domain.Webhook]	"Successfully retrieved webhooks"
+//	@Failure		401	{object}	domain.Problem								"Missing or invalid access token"
+//	@Failure		403	{object}	domain.Problem								"Not allowed for the caller's role"
+//	@Failure		500	{object}	domain.Problem								"Internal server error"
+//	@Security		BearerAuth
+//	@Router			/webhooks [get]
+func (h *WebhookHandler) GetWebhookList(c echo.Context) error {
+	ctx := c.Request().Context()
+	webhooks, err := h.Service.GetWebhookList(ctx)
+	if err != nil {
+		return err
+	}
+	if webhooks == nil {
+		webhooks = []domain.Webhook{}
+	}
+
+	return c.JSON(http.StatusOK, domain.ResponseMultipleData[domain.Webhook]{
+		Data:    webhooks,
+		Code:    http.StatusOK,
+		Status:  "success",
+		Message: "Successfully retrieved webhooks",
+	})
+}
+
+// GetWebhook retrieves a webhook
+//
+//	@Summary		Get webhook by ID
+//	@Description	Get a registered webhook without its secret. Admins only
+//	@Tags			webhooks
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string										true	"Webhook ID"	format(uuid)
+//	@Success		200	{object}	domain.ResponseSingleData[domain.Webhook]	"Successfully retrieved webhook"
+//	@Failure		422	{object}	domain.Problem								"Invalid webhook ID"
+//	@Failure		401	{object}	domain.Problem								"Missing or invalid access token"
+//	@Failure		403	{object}	domain.Problem								"Not allowed for the caller's role"
+//	@Failure		404	{object}	domain.Problem								"Webhook not found"
+//	@Failure		500	{object}	domain.Problem								"Internal server error"
+//	@Security		BearerAuth
+//	@Router			/webhooks/{id} [get]
+func (h *WebhookHandler) GetWebhook(c echo.Context) error {
+	id, err := paramUUID(c, "id")
+	if err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	webhook, err := h.Service.GetWebhook(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, domain.ResponseSingleData[domain.Webhook]{
+		Data:    *webhook,
+		Code:    http.StatusOK,
+		Status:  "success",
+		Message: "Successfully retrieved webhook",
+	})
+}
+
+// DeleteWebhook removes a webhook
+//
+//	@Summary		Delete webhook
+//	@Description	Remove a webhook with its delivery log, pending deliveries are dropped. Admins only
+//	@Tags			webhooks
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path	string	true	"Webhook ID"	format(uuid)
+//	@Success		204	"Webhook successfully deleted"
+//	@Failure		422	{object}	domain.Problem	"Invalid webhook ID"
+//	@Failure		401	{object}	domain.Problem	"Missing or invalid access token"
+//	@Failure		403	{object}	domain.Problem	"Not allowed for the caller's role"
+//	@Failure		404	{object}	domain.Problem	"Webhook not found"
+//	@Failure		500	{object}	domain.Problem	"Internal server error"
+//	@Security		BearerAuth
+//	@Router			/webhooks/{id} [delete]
+func (h *WebhookHandler) DeleteWebhook(c echo.Context) error {
+	id, err := paramUUID(c, "id")
+	if err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	if err := h.Service.DeleteWebhook(ctx, id); err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// GetWebhookDeliveries lists the latest deliveries of a webhook
+//
+//	@Summary		Get webhook deliveries
+//	@Description	List the latest 100 deliveries of a webhook, newest first, with their status, attempts and last response. Admins only
+//	@Tags			webhooks
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string												true	"Webhook ID"	format(uuid)
+//	@Success		200	{object}	domain.ResponseMultipleData[domain.WebhookDelivery]	"Successfully retrieved webhook deliveries"
+//	@Failure		422	{object}	domain.Problem										"Invalid webhook ID"
+//	@Failure		401	{object}	domain.Problem										"Missing or invalid access token"
+//	@Failure		403	{object}	domain.Problem										"Not allowed for the caller's role"
+//	@Failure		404	{object}	domain.Problem										"Webhook not found"
+//	@Failure		500	{object}	domain.Problem										"Internal server error"
+//	@Security		BearerAuth
+//	@Router			/webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) GetWebhookDeliveries(c echo.Context) error {
+	id, err := paramUUID(c, "id")
+	if err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	deliveries, err := h.Service.GetWebhookDeliveries(ctx, id)
+	if err != nil {
+		return err
+	}
+	if deliveries == nil {
+		deliveries = []domain.WebhookDelivery{}
+	}
+
+	return c.JSON(http.StatusOK, domain.ResponseMultipleData[domain.WebhookDelivery]{
+		Data:    deliveries,
+		Code:    http.StatusOK,
+		Status:  "success",
+		Message: "Successfully retrieved webhook deliveries",
+	})
+}
@@ -0,0 +1,212 @@
+package rest_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"zog-news/domain"
+	"zog-news/internal/rest"
+	"zog-news/internal/rest/mocks"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookHappyPath(t *testing.T) {
+	t.Parallel()
+
+	mockWebhookService := new(mocks.WebhookService)
+
+	webhook := domain.Webhook{
+		ID:         "3f2b8c1e-7a4d-4e9b-9c2a-1d5e6f7a8b9c",
+		URL:        "https://example.com/hooks",
+		EventTypes: []domain.EventType{domain.EventArticlePublished},
+		Active:     true,
+	}
+	id := uuid.MustParse(webhook.ID)
+
+	handler := rest.WebhookHandler{
+		Service: mockWebhookService,
+	}
+
+	// --- Create Webhook
+	t.Run("CreateWebhook", func(t *testing.T) {
+		request := domain.CreateWebhookRequest{
+			URL:        webhook.URL,
+			EventTypes: []domain.EventType{domain.EventArticlePublished},
+		}
+		created := webhook
+		created.Secret = "whsec_test"
+		mockWebhookService.
+			On("CreateWebhook", mock.Anything, &request).
+			Return(&created, nil).
+			Once()
+
+		body, err := json.Marshal(request)
+		require.NoError(t, err)
+
+		e := newEcho()
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/webhooks", bytes.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err = handler.CreateWebhook(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusCreated, rec.Code)
+
+		var resp domain.ResponseSingleData[domain.Webhook]
+		err = json.Unmarshal(rec.Body.Bytes(), &resp)
+		require.NoError(t, err)
+		assert.Equal(t, "whsec_test", resp.Data.Secret)
+
+		mockWebhookService.AssertExpectations(t)
+	})
+
+	// --- Get Webhooks
+	t.Run("GetWebhookList", func(t *testing.T) {
+		mockWebhookService.
+			On("GetWebhookList", mock.Anything).
+			Return([]domain.Webhook{webhook}, nil).
+			Once()
+
+		e := newEcho()
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/webhooks", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.GetWebhookList(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.NotContains(t, rec.Body.String(), "secret")
+
+		var resp domain.ResponseMultipleData[domain.Webhook]
+		err = json.Unmarshal(rec.Body.Bytes(), &resp)
+		require.NoError(t, err)
+		assert.Equal(t, []domain.Webhook{webhook}, resp.Data)
+
+		mockWebhookService.AssertExpectations(t)
+	})
+
+	// --- Get Deliveries
+	t.Run("GetWebhookDeliveries", func(t *testing.T) {
+		status := http.StatusOK
+		delivery := domain.WebhookDelivery{
+			ID:             "0c7e2f5a-9d1b-4e3a-8f6c-2b5d7a9e1c3f",
+			WebhookID:      webhook.ID,
+			EventType:      domain.EventArticlePublished,
+			Status:         domain.DeliverySucceeded,
+			Attempts:       1,
+			ResponseStatus: &status,
+		}
+		mockWebhookService.
+			On("GetWebhookDeliveries", mock.Anything, id).
+			Return([]domain.WebhookDelivery{delivery}, nil).
+			Once()
+
+		e := newEcho()
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/webhooks/"+webhook.ID+"/deliveries", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(webhook.ID)
+
+		err := handler.GetWebhookDeliveries(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var resp domain.ResponseMultipleData[domain.WebhookDelivery]
+		err = json.Unmarshal(rec.Body.Bytes(), &resp)
+		require.NoError(t, err)
+		assert.Equal(t, []domain.WebhookDelivery{delivery}, resp.Data)
+
+		mockWebhookService.AssertExpectations(t)
+	})
+
+	// --- Delete Webhook
+	t.Run("DeleteWebhook", func(t *testing.T) {
+		mockWebhookService.
+			On("DeleteWebhook", mock.Anything, id).
+			Return(nil).
+			Once()
+
+		e := newEcho()
+		req := httptest.NewRequest(http.MethodDelete, "/api/v1/webhooks/"+webhook.ID, nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(webhook.ID)
+
+		err := handler.DeleteWebhook(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		mockWebhookService.AssertExpectations(t)
+	})
+}
+
+func TestWebhookUnhappyPath(t *testing.T) {
+	mockWebhookService := new(mocks.WebhookService)
+
+	handler := rest.WebhookHandler{
+		Service: mockWebhookService,
+	}
+
+	// --- Unknown Event Type
+	t.Run("CreateWebhook_UnknownEventType", func(t *testing.T) {
+		body := []byte(`{"url": "https://example.com/hooks", "event_types": ["article.published", "article.liked"]}`)
+
+		e := newEcho()
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/webhooks", bytes.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.CreateWebhook(c)
+		require.Error(t, err)
+		rest.HTTPErrorHandler(err, c)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+		var resp domain.Problem
+		err = json.Unmarshal(rec.Body.Bytes(), &resp)
+		require.NoError(t, err)
+		assert.Equal(t, []domain.FieldError{{Field: "event_types[1]", Reason: "event_type"}}, resp.Errors)
+	})
+
+	// --- Missing Webhook
+	t.Run("GetWebhook_NotFound", func(t *testing.T) {
+		id := uuid.New()
+		mockWebhookService.
+			On("GetWebhook", mock.Anything, id).
+			Return(nil, domain.ErrWebhookNotFound).
+			Once()
+
+		e := newEcho()
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/webhooks/"+id.String(), nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(id.String())
+
+		err := handler.GetWebhook(c)
+		require.Error(t, err)
+		rest.HTTPErrorHandler(err, c)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+
+		var resp domain.Problem
+		err = json.Unmarshal(rec.Body.Bytes(), &resp)
+		require.NoError(t, err)
+		assert.Equal(t, "webhook_not_found", resp.Code)
+		mockWebhookService.AssertExpectations(t)
+	})
+}
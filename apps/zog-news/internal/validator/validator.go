@@ -1,17 +1,82 @@
 package validator
 
-import "github.com/go-playground/validator/v10"
+import (
+    "errors"
+    "reflect"
+    "strings"
+    "zog-news/domain"
+
+    "github.com/go-playground/validator/v10"
+)
 
 type Validator struct {
     validator *validator.Validate
 }
 
 func NewValidator() *Validator {
+    v := validator.New()
+
+    // report fields by the name clients send them with
+    v.RegisterTagNameFunc(func(field reflect.StructField) string {
+        for _, tag := range []string{"json", "query", "param"} {
+            name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+            if name == "-" {
+                return ""
+            }
+            if name != "" {
+                return name
+            }
+        }
+        return field.Name
+    })
+
+    // article_status accepts the known article statuses
+    v.RegisterValidation("article_status", func(fl validator.FieldLevel) bool {
+        return domain.ArticleStatus(fl.Field().String()).IsValid()
+    })
+
+    // content_format accepts the known article content formats
+    v.RegisterValidation("content_format", func(fl validator.FieldLevel) bool {
+        return domain.ContentFormat(fl.Field().String()).IsValid()
+    })
+
+    // event_type accepts the known event types and * for all of them
+    v.RegisterValidation("event_type", func(fl validator.FieldLevel) bool {
+        t := domain.EventType(fl.Field().String())
+        return t == domain.EventAll || t.IsValid()
+    })
+
     return &Validator{
-        validator: validator.New(),
+        validator: v,
     }
 }
 
+// Validate checks the validate tags of i and reports every broken constraint
+// as a domain.ValidationError
 func (v *Validator) Validate(i interface{}) error {
-    return v.validator.Struct(i)
+    err := v.validator.Struct(i)
+    var invalid validator.ValidationErrors
+    if !errors.As(err, &invalid) {
+        return err
+    }
+
+    fields := make([]domain.FieldError, len(invalid))
+    for i, fe := range invalid {
+        fields[i] = domain.FieldError{
+            Field:  fieldPath(fe.Namespace()),
+            Reason: fe.Tag(),
+            Param:  fe.Param(),
+        }
+    }
+    return domain.NewValidationError(fields...)
+}
+
+// fieldPath drops the struct name from a namespace like
+// CreateArticleRequest.topic_names[1]
+func fieldPath(namespace string) string {
+    _, path, found := strings.Cut(namespace, ".")
+    if !found {
+        return namespace
+    }
+    return path
 }
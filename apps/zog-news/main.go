@@ -13,11 +13,17 @@ import (
 	"zog-news/database"
 	_ "zog-news/docs"
 	"zog-news/domain"
+	"zog-news/events"
+	"zog-news/internal/repository/cache"
 	"zog-news/internal/repository/postgres"
 	"zog-news/internal/rest"
 	"zog-news/internal/rest/middleware"
 	"zog-news/internal/validator"
+	"zog-news/notification"
 	"zog-news/service"
+	"zog-news/stats"
+	"zog-news/storage"
+	"zog-news/webhook"
 
 	"github.com/labstack/echo/v4"
 	"github.com/lmittmann/tint"
@@ -34,6 +40,11 @@ func init() {
 
 // @host		localhost:8080
 // @BasePath	/api/v1
+//
+// @securityDefinitions.apikey	BearerAuth
+// @in							header
+// @name						Authorization
+// @description				Access token from /auth/login, as "Bearer <token>"
 func main() {
 
 	env := os.Getenv("APP_ENVIRONMENT")
@@ -66,6 +77,7 @@ func main() {
 	e.Logger.SetLevel(0)
 
 	e.Validator = validator.NewValidator()
+	e.HTTPErrorHandler = rest.HTTPErrorHandler
 
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
 	defer stop()
@@ -96,18 +108,111 @@ func main() {
 
 	e.GET("/swagger/*", echoSwagger.WrapHandler)
 
-	articleRepo := postgres.NewArticleRepository(dbPool)
+	authConfig, err := config.LoadAuthConfig()
+	if err != nil {
+		slog.Error("Failed to load auth config", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
+	userRepo := postgres.NewUserRepository(dbPool)
+	userService := service.NewUserService(userRepo, authConfig)
+	authMiddleware := middleware.JWTAuth(userService)
+	readAuthMiddleware := middleware.OptionalJWTAuth(userService)
+
+	cacheConfig, err := config.LoadCacheConfig()
+	if err != nil {
+		slog.Error("Failed to load cache config", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
+	var cacheStore cache.Store = cache.NewMemoryStore()
+	if cacheConfig.ValkeyURL != "" {
+		valkeyClient, err := database.SetupValkey(cacheConfig.ValkeyURL)
+		if err != nil {
+			slog.Error("Failed to set up valkey", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+		defer valkeyClient.Close()
+		cacheStore = cache.NewValkeyStore(valkeyClient)
+	}
+	readCache := cache.New(cacheStore, cacheConfig)
+
+	articleRepo := cache.NewArticleRepository(postgres.NewArticleRepository(dbPool), readCache)
 	articleService := service.NewArticleService(articleRepo)
 
-	topicRepo := postgres.NewTopicRepository(dbPool)
+	statsConfig, err := config.LoadStatsConfig()
+	if err != nil {
+		slog.Error("Failed to load stats config", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
+	// Article reads are counted in memory and written in batches until
+	// shutdown, when the last ones are flushed
+	statsRepo := postgres.NewStatsRepository(dbPool)
+	statsService := service.NewStatsService(statsRepo, articleRepo)
+	viewCounter := stats.NewCounter(statsRepo, statsConfig)
+	go viewCounter.Run(ctx)
+
+	topicRepo := cache.NewTopicRepository(postgres.NewTopicRepository(dbPool), readCache)
 	topicService := service.NewTopicService(topicRepo)
 
+	webhookRepo := postgres.NewWebhookRepository(dbPool)
+	webhookService := service.NewWebhookService(webhookRepo)
+
+	mediaConfig, err := config.LoadMediaConfig()
+	if err != nil {
+		slog.Error("Failed to load media config", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
+	var mediaStore storage.Storage
+	switch mediaConfig.Storage {
+	case config.MediaStorageS3:
+		mediaStore = storage.NewS3(storage.S3Config{
+			Endpoint:  mediaConfig.S3Endpoint,
+			Bucket:    mediaConfig.S3Bucket,
+			Region:    mediaConfig.S3Region,
+			AccessKey: mediaConfig.S3AccessKeyID,
+			SecretKey: mediaConfig.S3SecretAccessKey,
+		})
+	default:
+		mediaStore, err = storage.NewLocal(mediaConfig.Dir)
+		if err != nil {
+			slog.Error("Failed to set up media storage", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+	}
+	mediaRepo := postgres.NewMediaRepository(dbPool)
+	mediaService := service.NewMediaService(mediaRepo, mediaStore, mediaConfig.MaxSize, mediaConfig.ThumbnailSize)
+
 	apiV1 := e.Group("/api/v1")
+	authGroup := apiV1.Group("")
+	usersGroup := apiV1.Group("")
 	articlesGroup := apiV1.Group("")
 	topicsGroup := apiV1.Group("")
+	trashGroup := apiV1.Group("")
+	webhooksGroup := apiV1.Group("")
+	mediaGroup := apiV1.Group("")
+
+	rest.NewAuthHandler(authGroup, userService)
+	rest.NewUserHandler(usersGroup, userService, authMiddleware)
+	rest.NewArticleHandler(articlesGroup, articleService, authMiddleware, readAuthMiddleware, viewCounter)
+	rest.NewStatsHandler(articlesGroup, statsService, authMiddleware)
+	rest.NewTopicHandler(topicsGroup, topicService, authMiddleware, readAuthMiddleware)
+	rest.NewTrashHandler(trashGroup, articleService, topicService, authMiddleware)
+	rest.NewWebhookHandler(webhooksGroup, webhookService, authMiddleware)
+	rest.NewMediaHandler(mediaGroup, mediaService, authMiddleware, mediaConfig)
 
-	rest.NewArticleHandler(articlesGroup, articleService)
-	rest.NewTopicHandler(topicsGroup, topicService)
+	feedConfig, err := config.LoadFeedConfig()
+	if err != nil {
+		slog.Error("Failed to load feed config", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	// feeds and sitemaps live outside the API so readers and crawlers get
+	// short, stable URLs
+	rest.NewFeedHandler(e.Group(""), articleService, topicService, feedConfig)
+	sitemapService := service.NewSitemapService(postgres.NewSitemapRepository(dbPool))
+	rest.NewSitemapHandler(e.Group(""), sitemapService, feedConfig)
 
 	// Get host from environment variable, default to 127.0.0.1 if not set
 	host := os.Getenv("APP_HOST")
@@ -124,6 +229,78 @@ func main() {
 	// Server address and port to listen on
 	serverAddr := fmt.Sprintf("%s:%s", host, port)
 
+	schedulerInterval, err := config.SchedulerInterval()
+	if err != nil {
+		slog.Error("Failed to load scheduler config", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
+	// Publish articles whose scheduled time has passed until shutdown
+	go func() {
+		ticker := time.NewTicker(schedulerInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				published, err := articleService.PublishScheduledArticles(ctx)
+				if err != nil {
+					slog.Error("Scheduled publishing failed", "error", err)
+					continue
+				}
+				if published > 0 {
+					slog.Info("Published scheduled articles", "count", published)
+				}
+			}
+		}
+	}()
+
+	mailConfig, err := config.LoadMailConfig()
+	if err != nil {
+		slog.Error("Failed to load mail config", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
+	// Deliver the queued emails until shutdown, without an SMTP server they
+	// wait in the outbox
+	if mailConfig.Host != "" {
+		outboxRepo := postgres.NewEmailOutboxRepository(dbPool)
+		mailWorker := notification.NewWorker(outboxRepo, notification.NewSMTPSender(mailConfig), mailConfig)
+		go mailWorker.Run(ctx)
+	} else {
+		slog.Warn("SMTP_HOST not set, emails stay queued in the outbox")
+	}
+
+	eventsConfig, err := config.LoadEventsConfig()
+	if err != nil {
+		slog.Error("Failed to load events config", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
+	// Relay the recorded domain events until shutdown, every event is queued
+	// for the subscribed webhooks and published to EVENT_SINK if set
+	eventSink := events.MultiSink{webhook.NewSink(webhookRepo)}
+	switch eventsConfig.Sink {
+	case config.EventSinkStdout:
+		eventSink = append(eventSink, events.NewStdoutSink(os.Stdout))
+	case config.EventSinkWebhook:
+		eventSink = append(eventSink, events.NewWebhookSink(eventsConfig.WebhookURL))
+	}
+	relay := events.NewRelay(postgres.NewOutboxRepository(dbPool), eventSink, eventsConfig)
+	go relay.Run(ctx)
+
+	webhookConfig, err := config.LoadWebhookConfig()
+	if err != nil {
+		slog.Error("Failed to load webhook config", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
+	// Send the queued webhook deliveries until shutdown
+	dispatcher := webhook.NewDispatcher(webhookRepo, webhookConfig)
+	go dispatcher.Run(ctx)
+
 	go func() {
 		slog.Info("Server starting", "address", serverAddr)
 		if err := e.Start(serverAddr); err != nil && err != http.ErrServerClosed {
@@ -141,4 +318,7 @@ func main() {
 	if err := e.Shutdown(ctx); err != nil {
 		slog.Error("Shutdown error", "error", err)
 	}
+	if err := viewCounter.Flush(ctx); err != nil {
+		slog.Error("Writing article views failed", "error", err)
+	}
 }
@@ -0,0 +1,94 @@
+// Package media inspects uploaded files: it sniffs their content type from
+// their bytes, reads the dimensions of images and scales them down to
+// thumbnails.
+//
+// JPEG, PNG, GIF and WebP images are understood. Thumbnails of JPEGs are
+// JPEGs, thumbnails of everything else are PNGs so transparency survives.
+// Animated GIFs are thumbnailed from their first frame.
+package media
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"mime"
+	"net/http"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// MaxPixels caps the size of images that are decoded, a small file can
+// declare dimensions that take gigabytes to decode
+const MaxPixels = 50_000_000
+
+// ErrTooManyPixels is returned for images larger than MaxPixels
+var ErrTooManyPixels = errors.New("media: image has too many pixels")
+
+// Sniff returns the content type of a file from its first bytes, without
+// parameters such as charset. At most 512 bytes are looked at.
+func Sniff(head []byte) string {
+	contentType := http.DetectContentType(head)
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		return mediaType
+	}
+	return contentType
+}
+
+// Dimensions returns the width and height of an image in pixels, reading
+// only its header
+func Dimensions(data []byte) (width, height int, err error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return 0, 0, err
+	}
+	if config.Width*config.Height > MaxPixels {
+		return 0, 0, ErrTooManyPixels
+	}
+	return config.Width, config.Height, nil
+}
+
+// Thumbnail scales an image down so its longest edge is at most maxEdge
+// pixels, smaller images keep their size. It returns the encoded thumbnail
+// and its content type.
+func Thumbnail(data []byte, maxEdge int) ([]byte, string, error) {
+	if _, _, err := Dimensions(data); err != nil {
+		return nil, "", err
+	}
+	src, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", err
+	}
+
+	bounds := src.Bounds()
+	width, height := fit(bounds.Dx(), bounds.Dy(), maxEdge)
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, bounds, draw.Over, nil)
+
+	var buf bytes.Buffer
+	if format == "jpeg" {
+		if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 85}); err != nil {
+			return nil, "", err
+		}
+		return buf.Bytes(), "image/jpeg", nil
+	}
+	if err := png.Encode(&buf, dst); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), "image/png", nil
+}
+
+// fit scales width and height down to fit in a maxEdge square, keeping the
+// aspect ratio and at least one pixel per side
+func fit(width, height, maxEdge int) (int, int) {
+	if width <= maxEdge && height <= maxEdge {
+		return width, height
+	}
+	if width >= height {
+		return maxEdge, max(1, height*maxEdge/width)
+	}
+	return max(1, width*maxEdge/height), maxEdge
+}
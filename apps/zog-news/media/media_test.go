@@ -0,0 +1,117 @@
+package media_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"testing"
+	"zog-news/media"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newImage(width, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		img.Set(x, 0, color.RGBA{R: 255, A: 255})
+	}
+	return img
+}
+
+func encode(t *testing.T, format string, img image.Image) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	var err error
+	switch format {
+	case "jpeg":
+		err = jpeg.Encode(&buf, img, nil)
+	case "png":
+		err = png.Encode(&buf, img)
+	case "gif":
+		err = gif.Encode(&buf, img, nil)
+	}
+	require.NoError(t, err)
+	return buf.Bytes()
+}
+
+func TestSniff(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		data []byte
+		want string
+	}{
+		"JPEG":  {encode(t, "jpeg", newImage(4, 4)), "image/jpeg"},
+		"PNG":   {encode(t, "png", newImage(4, 4)), "image/png"},
+		"GIF":   {encode(t, "gif", newImage(4, 4)), "image/gif"},
+		"WebP":  {[]byte("RIFF\x00\x00\x00\x00WEBPVP8 "), "image/webp"},
+		"PDF":   {[]byte("%PDF-1.7\n"), "application/pdf"},
+		"Text":  {[]byte("just some notes"), "text/plain"},
+		"HTML":  {[]byte("<html><script>alert(1)</script>"), "text/html"},
+		"Empty": {nil, "text/plain"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.want, media.Sniff(tt.data))
+		})
+	}
+}
+
+func TestDimensions(t *testing.T) {
+	t.Parallel()
+
+	width, height, err := media.Dimensions(encode(t, "png", newImage(640, 480)))
+	require.NoError(t, err)
+	assert.Equal(t, 640, width)
+	assert.Equal(t, 480, height)
+
+	_, _, err = media.Dimensions([]byte("%PDF-1.7\n"))
+	assert.Error(t, err)
+
+	t.Run("TooManyPixels", func(t *testing.T) {
+		// a PNG header declaring 10000x10000 pixels, nothing is decoded
+		header := encode(t, "png", newImage(1, 1))
+		copy(header[16:24], []byte{0, 0, 0x27, 0x10, 0, 0, 0x27, 0x10})
+		binary.BigEndian.PutUint32(header[29:33], crc32.ChecksumIEEE(header[12:29]))
+
+		_, _, err := media.Dimensions(header)
+		assert.ErrorIs(t, err, media.ErrTooManyPixels)
+	})
+}
+
+func TestThumbnail(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		format        string
+		width, height int
+		wantType      string
+		wantWidth     int
+		wantHeight    int
+	}{
+		"LandscapeJPEG":  {"jpeg", 1200, 800, "image/jpeg", 320, 213},
+		"PortraitPNG":    {"png", 400, 1600, "image/png", 80, 320},
+		"GIF":            {"gif", 640, 640, "image/png", 320, 320},
+		"SmallKeepsSize": {"png", 100, 50, "image/png", 100, 50},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			thumbnail, contentType, err := media.Thumbnail(encode(t, tt.format, newImage(tt.width, tt.height)), 320)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantType, contentType)
+			assert.Equal(t, tt.wantType, media.Sniff(thumbnail))
+
+			width, height, err := media.Dimensions(thumbnail)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantWidth, width)
+			assert.Equal(t, tt.wantHeight, height)
+		})
+	}
+}
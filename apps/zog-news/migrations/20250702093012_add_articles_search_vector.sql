@@ -0,0 +1,18 @@
+-- @docs https://www.postgresql.org/docs/current/textsearch-tables.html
+-- +goose Up
+-- +goose StatementBegin
+-- 'simple' config since articles are written in more than one language
+ALTER TABLE articles
+    ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', coalesce(title, '')), 'A') ||
+        setweight(to_tsvector('simple', coalesce(content, '')), 'B')
+    ) STORED;
+
+CREATE INDEX articles_search_vector_idx ON articles USING GIN (search_vector);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX articles_search_vector_idx;
+ALTER TABLE articles DROP COLUMN search_vector;
+-- +goose StatementEnd
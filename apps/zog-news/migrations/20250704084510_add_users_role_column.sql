@@ -0,0 +1,14 @@
+-- @docs https://www.postgresql.org/docs/current/datatype-enum.html
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE user_role AS ENUM ('author', 'editor', 'admin');
+
+ALTER TABLE users ADD COLUMN role user_role NOT NULL DEFAULT 'author';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users DROP COLUMN role;
+
+DROP TYPE user_role;
+-- +goose StatementEnd
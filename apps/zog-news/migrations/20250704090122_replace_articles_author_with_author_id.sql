@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE articles ADD COLUMN author_id UUID REFERENCES users(id) ON DELETE SET NULL;
+
+-- best effort: keep the author of existing articles when a user has the same name
+UPDATE articles a
+SET author_id = u.id
+FROM users u
+WHERE u.name = a.author;
+
+ALTER TABLE articles DROP COLUMN author;
+
+CREATE INDEX articles_author_id_idx ON articles (author_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE articles ADD COLUMN author VARCHAR(100) NOT NULL DEFAULT '';
+
+UPDATE articles a
+SET author = u.name
+FROM users u
+WHERE u.id = a.author_id;
+
+ALTER TABLE articles DROP COLUMN author_id;
+-- +goose StatementEnd
@@ -0,0 +1,55 @@
+-- @docs https://www.postgresql.org/docs/current/sql-altertype.html
+-- +goose Up
+-- +goose StatementBegin
+-- Values added with ADD VALUE can't be used inside the same transaction,
+-- so the enum is rebuilt instead. Soft deletion lives in deleted_at, the old
+-- 'deleted' status becomes 'archived'.
+ALTER TYPE article_status RENAME TO article_status_old;
+
+CREATE TYPE article_status AS ENUM ('draft', 'in_review', 'published', 'archived');
+
+ALTER TABLE articles
+    ALTER COLUMN status DROP DEFAULT,
+    ALTER COLUMN status TYPE article_status USING (
+        CASE status::text WHEN 'deleted' THEN 'archived' ELSE status::text END
+    )::article_status,
+    ALTER COLUMN status SET DEFAULT 'draft';
+
+DROP TYPE article_status_old;
+
+ALTER TABLE articles
+    ADD COLUMN published_at TIMESTAMP DEFAULT NULL,
+    ADD COLUMN scheduled_at TIMESTAMP DEFAULT NULL;
+
+UPDATE articles SET published_at = updated_at WHERE status = 'published';
+
+-- the scheduler only looks at articles waiting for their publishing time
+CREATE INDEX articles_scheduled_at_idx ON articles (scheduled_at)
+    WHERE scheduled_at IS NOT NULL AND deleted_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX articles_scheduled_at_idx;
+
+ALTER TABLE articles
+    DROP COLUMN published_at,
+    DROP COLUMN scheduled_at;
+
+ALTER TYPE article_status RENAME TO article_status_new;
+
+CREATE TYPE article_status AS ENUM ('draft', 'deleted', 'published');
+
+ALTER TABLE articles
+    ALTER COLUMN status DROP DEFAULT,
+    ALTER COLUMN status TYPE article_status USING (
+        CASE status::text
+            WHEN 'archived' THEN 'deleted'
+            WHEN 'in_review' THEN 'draft'
+            ELSE status::text
+        END
+    )::article_status,
+    ALTER COLUMN status SET DEFAULT 'draft';
+
+DROP TYPE article_status_new;
+-- +goose StatementEnd
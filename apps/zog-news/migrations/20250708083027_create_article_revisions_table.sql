@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Every create and update of an article stores a full snapshot, revisions are
+-- numbered per article starting from 1
+CREATE TABLE article_revisions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    article_id UUID NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+    revision INTEGER NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    content TEXT NOT NULL,
+    editor_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (article_id, revision)
+);
+
+-- existing articles start their history from their current text
+INSERT INTO article_revisions (article_id, revision, title, content, editor_id, created_at)
+SELECT id, 1, title, content, author_id, updated_at FROM articles;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE article_revisions;
+-- +goose StatementEnd
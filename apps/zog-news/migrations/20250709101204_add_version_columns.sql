@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Bumped on every write, clients send it back in If-Match to detect lost updates
+ALTER TABLE articles ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE topics ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE articles DROP COLUMN version;
+ALTER TABLE topics DROP COLUMN version;
+-- +goose StatementEnd
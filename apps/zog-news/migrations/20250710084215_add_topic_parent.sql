@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Topics form a tree, a purged parent leaves its children at the root
+ALTER TABLE topics ADD COLUMN parent_id UUID REFERENCES topics(id) ON DELETE SET NULL;
+ALTER TABLE topics ADD CONSTRAINT topics_parent_id_check CHECK (parent_id <> id);
+CREATE INDEX topics_parent_id_idx ON topics (parent_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX topics_parent_id_idx;
+ALTER TABLE topics DROP COLUMN parent_id;
+-- +goose StatementEnd
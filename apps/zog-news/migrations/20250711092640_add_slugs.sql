@@ -0,0 +1,45 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Articles and topics get unique URL slugs, renames keep their old slugs in a
+-- history table so links keep resolving
+ALTER TABLE articles ADD COLUMN slug TEXT;
+ALTER TABLE topics ADD COLUMN slug TEXT;
+
+-- existing rows get an ASCII slug, duplicates and empty ones take an id suffix
+UPDATE articles SET slug = NULLIF(trim(both '-' from left(regexp_replace(lower(title), '[^a-z0-9]+', '-', 'g'), 80)), '');
+UPDATE articles a SET slug = COALESCE(a.slug, 'article') || '-' || left(a.id::text, 8)
+WHERE a.slug IS NULL OR EXISTS (SELECT 1 FROM articles b WHERE b.slug = a.slug AND b.id < a.id);
+
+UPDATE topics SET slug = NULLIF(trim(both '-' from left(regexp_replace(lower(name), '[^a-z0-9]+', '-', 'g'), 80)), '');
+UPDATE topics a SET slug = COALESCE(a.slug, 'topic') || '-' || left(a.id::text, 8)
+WHERE a.slug IS NULL OR EXISTS (SELECT 1 FROM topics b WHERE b.slug = a.slug AND b.id < a.id);
+
+ALTER TABLE articles ALTER COLUMN slug SET NOT NULL;
+ALTER TABLE topics ALTER COLUMN slug SET NOT NULL;
+CREATE UNIQUE INDEX articles_slug_key ON articles (slug);
+CREATE UNIQUE INDEX topics_slug_key ON topics (slug);
+
+CREATE TABLE article_slug_history (
+    slug TEXT PRIMARY KEY,
+    article_id UUID NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX article_slug_history_article_id_idx ON article_slug_history (article_id);
+
+CREATE TABLE topic_slug_history (
+    slug TEXT PRIMARY KEY,
+    topic_id UUID NOT NULL REFERENCES topics(id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX topic_slug_history_topic_id_idx ON topic_slug_history (topic_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE topic_slug_history;
+DROP TABLE article_slug_history;
+DROP INDEX topics_slug_key;
+DROP INDEX articles_slug_key;
+ALTER TABLE topics DROP COLUMN slug;
+ALTER TABLE articles DROP COLUMN slug;
+-- +goose StatementEnd
@@ -0,0 +1,44 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Topic names are unique among live topics regardless of case and spacing.
+-- Existing duplicates are merged into the oldest spelling first.
+UPDATE topics SET name = regexp_replace(trim(name), '\s+', ' ', 'g')
+WHERE name <> regexp_replace(trim(name), '\s+', ' ', 'g');
+
+CREATE TEMPORARY TABLE topic_merges ON COMMIT DROP AS
+SELECT id, first_value(id) OVER (PARTITION BY lower(name) ORDER BY created_at, id) AS target
+FROM topics
+WHERE deleted_at IS NULL;
+DELETE FROM topic_merges WHERE id = target;
+
+INSERT INTO article_topics (article_id, topic_id)
+SELECT at.article_id, m.target
+FROM article_topics at
+JOIN topic_merges m ON m.id = at.topic_id
+ON CONFLICT DO NOTHING;
+DELETE FROM article_topics WHERE topic_id IN (SELECT id FROM topic_merges);
+
+UPDATE topics t SET parent_id = m.target
+FROM topic_merges m
+WHERE t.parent_id = m.id AND t.id <> m.target;
+
+-- the old slugs of the duplicates redirect to the topic they were merged into
+INSERT INTO topic_slug_history (slug, topic_id)
+SELECT t.slug, m.target
+FROM topics t
+JOIN topic_merges m ON m.id = t.id
+ON CONFLICT (slug) DO UPDATE SET topic_id = EXCLUDED.topic_id;
+UPDATE topic_slug_history h SET topic_id = m.target
+FROM topic_merges m
+WHERE h.topic_id = m.id;
+
+UPDATE topics SET deleted_at = NOW(), version = version + 1
+WHERE id IN (SELECT id FROM topic_merges);
+
+CREATE UNIQUE INDEX topics_name_key ON topics (lower(name)) WHERE deleted_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX topics_name_key;
+-- +goose StatementEnd
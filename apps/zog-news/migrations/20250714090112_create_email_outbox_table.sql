@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Emails are queued in the same transaction as the change they announce and
+-- delivered by a background worker. next_attempt_at is NULL once delivery
+-- gave up after too many failures.
+CREATE TABLE email_outbox (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    template VARCHAR(64) NOT NULL,
+    recipient VARCHAR(255) NOT NULL,
+    data JSONB NOT NULL DEFAULT '{}',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP DEFAULT (NOW() AT TIME ZONE 'UTC'),
+    last_error TEXT DEFAULT NULL,
+    sent_at TIMESTAMP DEFAULT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX email_outbox_pending_idx ON email_outbox (next_attempt_at)
+WHERE sent_at IS NULL AND next_attempt_at IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE email_outbox;
+-- +goose StatementEnd
@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Domain events are recorded in the same transaction as the change they
+-- describe and relayed to the configured sink by a background worker.
+-- next_attempt_at is NULL once the event is published or given up on.
+CREATE TABLE outbox (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    sequence BIGSERIAL NOT NULL UNIQUE,
+    event_type VARCHAR(64) NOT NULL,
+    aggregate_type VARCHAR(32) NOT NULL,
+    aggregate_id UUID NOT NULL,
+    payload JSONB NOT NULL DEFAULT '{}',
+    occurred_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP DEFAULT (NOW() AT TIME ZONE 'UTC'),
+    last_error TEXT DEFAULT NULL,
+    published_at TIMESTAMP DEFAULT NULL
+);
+
+CREATE INDEX outbox_pending_idx ON outbox (next_attempt_at, sequence)
+WHERE next_attempt_at IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE outbox;
+-- +goose StatementEnd
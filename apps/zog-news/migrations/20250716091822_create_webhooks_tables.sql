@@ -0,0 +1,46 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A webhook is disabled, disabled_at set, once failure_count consecutive
+-- delivery attempts failed
+CREATE TABLE webhooks (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    url VARCHAR(2048) NOT NULL,
+    event_types VARCHAR(64)[] NOT NULL,
+    secret VARCHAR(128) NOT NULL,
+    failure_count INTEGER NOT NULL DEFAULT 0,
+    disabled_at TIMESTAMP DEFAULT NULL,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Every event a webhook subscribes to is delivered once, the body is the
+-- event as JSON. next_attempt_at is NULL once the delivery succeeded or
+-- failed for good.
+CREATE TABLE webhook_deliveries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    webhook_id UUID NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+    event_id UUID NOT NULL,
+    event_type VARCHAR(64) NOT NULL,
+    body JSONB NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    response_status INTEGER DEFAULT NULL,
+    last_error TEXT DEFAULT NULL,
+    next_attempt_at TIMESTAMP DEFAULT (NOW() AT TIME ZONE 'UTC'),
+    delivered_at TIMESTAMP DEFAULT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (webhook_id, event_id)
+);
+
+CREATE INDEX webhook_deliveries_pending_idx ON webhook_deliveries (next_attempt_at)
+WHERE next_attempt_at IS NOT NULL;
+CREATE INDEX webhook_deliveries_webhook_id_idx ON webhook_deliveries (webhook_id, created_at DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE webhook_deliveries;
+
+DROP TABLE webhooks;
+-- +goose StatementEnd
@@ -0,0 +1,60 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Articles declare how their content is written and keep the sanitized HTML
+-- rendered from it, with the excerpt and reading time derived from that HTML
+CREATE TYPE content_format AS ENUM ('markdown', 'html', 'plain');
+
+ALTER TABLE articles
+    ADD COLUMN content_format content_format NOT NULL DEFAULT 'plain',
+    ADD COLUMN content_html TEXT NOT NULL DEFAULT '',
+    ADD COLUMN excerpt TEXT NOT NULL DEFAULT '',
+    ADD COLUMN reading_time INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE article_revisions
+    ADD COLUMN content_format content_format NOT NULL DEFAULT 'plain';
+
+-- existing content is plain text, rendered like render.plain does: escaped,
+-- one paragraph per block between blank lines and <br> for single breaks
+UPDATE articles a
+SET content_html = r.html,
+    excerpt = CASE
+        WHEN char_length(r.text) <= 280 THEN r.text
+        ELSE COALESCE(substring(left(r.text, 280) FROM '^(.+) '), left(r.text, 280)) || '…'
+    END,
+    reading_time = CASE
+        WHEN r.text = '' THEN 0
+        ELSE ceil(array_length(string_to_array(r.text, ' '), 1) / 200.0)::integer
+    END
+FROM (
+    SELECT
+        id,
+        trim(regexp_replace(content, '\s+', ' ', 'g')) AS text,
+        COALESCE((
+            SELECT string_agg(
+                '<p>' || replace(
+                    replace(replace(replace(replace(replace(
+                        btrim(p, E' \t\n'), '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&#34;'), '''', '&#39;'),
+                    E'\n', E'<br>\n'
+                ) || '</p>',
+                E'\n' ORDER BY n
+            )
+            FROM regexp_split_to_table(replace(content, E'\r\n', E'\n'), E'\n[ \t]*\n') WITH ORDINALITY AS s(p, n)
+            WHERE btrim(p, E' \t\n') <> ''
+        ), '') AS html
+    FROM articles
+) r
+WHERE a.id = r.id;
+
+ALTER TABLE articles ALTER COLUMN content_format SET DEFAULT 'markdown';
+ALTER TABLE article_revisions ALTER COLUMN content_format SET DEFAULT 'markdown';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE article_revisions DROP COLUMN content_format;
+ALTER TABLE articles
+    DROP COLUMN reading_time,
+    DROP COLUMN excerpt,
+    DROP COLUMN content_html,
+    DROP COLUMN content_format;
+DROP TYPE content_format;
+-- +goose StatementEnd
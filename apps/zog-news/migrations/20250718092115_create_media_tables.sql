@@ -0,0 +1,41 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Uploaded files live in the media storage under storage_key, images also
+-- keep a thumbnail under thumbnail_key
+CREATE TABLE media (
+    id UUID PRIMARY KEY,
+    filename VARCHAR(255) NOT NULL,
+    content_type VARCHAR(128) NOT NULL,
+    size BIGINT NOT NULL,
+    width INTEGER DEFAULT NULL,
+    height INTEGER DEFAULT NULL,
+    alt VARCHAR(500) NOT NULL DEFAULT '',
+    storage_key VARCHAR(512) NOT NULL UNIQUE,
+    thumbnail_key VARCHAR(512) DEFAULT NULL,
+    uploaded_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- media can't be deleted while an article, trashed or not, still uses it
+ALTER TABLE articles
+    ADD COLUMN cover_media_id UUID DEFAULT NULL REFERENCES media(id);
+
+-- Attachments of an article in the order they were given
+CREATE TABLE article_media (
+    article_id UUID NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+    media_id UUID NOT NULL REFERENCES media(id),
+    position INTEGER NOT NULL,
+    PRIMARY KEY (article_id, media_id)
+);
+
+CREATE INDEX article_media_media_id_idx ON article_media (media_id);
+CREATE INDEX articles_cover_media_id_idx ON articles (cover_media_id)
+WHERE cover_media_id IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE article_media;
+ALTER TABLE articles DROP COLUMN cover_media_id;
+DROP TABLE media;
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Views of an article per UTC day, counted in memory and added in batches
+CREATE TABLE article_stats (
+    article_id UUID NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+    day DATE NOT NULL,
+    views BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (article_id, day)
+);
+
+-- trending ranks the articles viewed since a day
+CREATE INDEX article_stats_day_idx ON article_stats (day);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE article_stats;
+-- +goose StatementEnd
@@ -0,0 +1,30 @@
+// Package notification delivers the emails queued in the outbox.
+//
+// Repositories queue emails in the transaction of the change they announce,
+// so an email is sent if and only if the change commits. The Worker picks
+// them up, renders their template and hands them to a Sender, retrying
+// failed deliveries with a growing delay.
+package notification
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrUnknownTemplate is returned by Render for a template that doesn't exist
+var ErrUnknownTemplate = errors.New("unknown email template")
+
+// Message is a rendered email
+type Message struct {
+	// ID stays the same across retries so clients can drop duplicates
+	ID      string
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Sender delivers a message
+type Sender interface {
+	Send(ctx context.Context, msg Message) error
+}
@@ -0,0 +1,76 @@
+package notification
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"path"
+	"strings"
+	texttemplate "text/template"
+)
+
+// templateFS holds a NAME.txt and NAME.html pair per email. The text file
+// also defines the subject in a "subject" block.
+//
+//go:embed templates
+var templateFS embed.FS
+
+type emailTemplate struct {
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+// templates is parsed once at startup, so a broken template stops the service
+// from starting instead of failing every delivery
+var templates = mustParseTemplates()
+
+func mustParseTemplates() map[string]emailTemplate {
+	files, err := templateFS.ReadDir("templates")
+	if err != nil {
+		panic(err)
+	}
+
+	parsed := make(map[string]emailTemplate)
+	for _, file := range files {
+		name, ok := strings.CutSuffix(file.Name(), ".txt")
+		if !ok {
+			continue
+		}
+		// every template is parsed on its own, they all define "subject"
+		parsed[name] = emailTemplate{
+			text: texttemplate.Must(texttemplate.New(name).
+				Option("missingkey=error").
+				ParseFS(templateFS, path.Join("templates", name+".txt"))),
+			html: htmltemplate.Must(htmltemplate.New(name).
+				Option("missingkey=error").
+				ParseFS(templateFS, path.Join("templates", name+".html"))),
+		}
+	}
+	return parsed
+}
+
+// Render fills the named template with data, the message has no recipient yet
+func Render(name string, data map[string]string) (Message, error) {
+	tmpl, ok := templates[name]
+	if !ok {
+		return Message{}, fmt.Errorf("%w: %q", ErrUnknownTemplate, name)
+	}
+
+	var subject, text, html bytes.Buffer
+	if err := tmpl.text.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return Message{}, err
+	}
+	if err := tmpl.text.ExecuteTemplate(&text, name+".txt", data); err != nil {
+		return Message{}, err
+	}
+	if err := tmpl.html.ExecuteTemplate(&html, name+".html", data); err != nil {
+		return Message{}, err
+	}
+
+	return Message{
+		Subject: strings.TrimSpace(subject.String()),
+		Text:    text.String(),
+		HTML:    html.String(),
+	}, nil
+}
@@ -0,0 +1,146 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+	"zog-news/config"
+	"zog-news/domain"
+
+	"go.opentelemetry.io/otel"
+)
+
+const (
+	// batchSize is how many emails one round claims
+	batchSize = 50
+	// claimLease is how long a claimed email is hidden from other workers
+	claimLease = 5 * time.Minute
+
+	// the first retry waits retryBase, every further one twice as long up
+	// to retryMax
+	retryBase = 30 * time.Second
+	retryMax  = time.Hour
+)
+
+// errUnrenderable marks emails whose template or data is broken, retrying
+// them can't help
+var errUnrenderable = errors.New("email can't be rendered")
+
+// Outbox is where emails wait for delivery
+type Outbox interface {
+	ClaimEmails(ctx context.Context, now, lease time.Time, limit int) ([]domain.OutboxEmail, error)
+	MarkEmailSent(ctx context.Context, id string, sentAt time.Time) error
+	MarkEmailFailed(ctx context.Context, id string, reason string, retryAt *time.Time) error
+}
+
+// Worker delivers the emails in the outbox
+type Worker struct {
+	outbox Outbox
+	sender Sender
+	config config.MailConfig
+	now    func() time.Time
+}
+
+func NewWorker(outbox Outbox, sender Sender, config config.MailConfig) *Worker {
+	return &Worker{
+		outbox: outbox,
+		sender: sender,
+		config: config,
+		now:    time.Now,
+	}
+}
+
+// Run delivers due emails every interval until ctx is done
+func (w *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			sent, err := w.DeliverDue(ctx)
+			if err != nil && ctx.Err() == nil {
+				slog.Error("Email delivery failed", "error", err)
+				continue
+			}
+			if sent > 0 {
+				slog.Info("Sent emails", "count", sent)
+			}
+		}
+	}
+}
+
+// DeliverDue claims the emails due now, sends them and returns how many were
+// sent. Failed emails are rescheduled, so only outbox errors are returned.
+func (w *Worker) DeliverDue(ctx context.Context) (int, error) {
+	tracer := otel.Tracer("notification.worker")
+	ctx, span := tracer.Start(ctx, "Worker.DeliverDue")
+	defer span.End()
+
+	now := w.now().UTC()
+	emails, err := w.outbox.ClaimEmails(ctx, now, now.Add(claimLease), batchSize)
+	if err != nil {
+		span.RecordError(err)
+		return 0, err
+	}
+
+	sent := 0
+	for _, email := range emails {
+		if ctx.Err() != nil {
+			// unsent claims are retried once their lease passes
+			return sent, ctx.Err()
+		}
+
+		deliverErr := w.deliver(ctx, email)
+		if deliverErr == nil {
+			sent++
+			err = w.outbox.MarkEmailSent(ctx, email.ID, w.now().UTC())
+		} else {
+			err = w.outbox.MarkEmailFailed(ctx, email.ID, deliverErr.Error(), w.retryAt(email, deliverErr))
+		}
+		if err != nil {
+			span.RecordError(err)
+			return sent, err
+		}
+	}
+	return sent, nil
+}
+
+func (w *Worker) deliver(ctx context.Context, email domain.OutboxEmail) error {
+	msg, err := Render(email.Template, email.Data)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errUnrenderable, err)
+	}
+	msg.ID = email.ID
+	msg.To = email.Recipient
+
+	if err := w.sender.Send(ctx, msg); err != nil {
+		slog.WarnContext(ctx, "Sending email failed",
+			slog.String("id", email.ID),
+			slog.String("template", email.Template),
+			slog.Int("attempt", email.Attempts),
+			slog.String("error", err.Error()),
+		)
+		return err
+	}
+	return nil
+}
+
+// retryAt returns when a failed email is tried again, nil once it used up its
+// attempts or can't be rendered
+func (w *Worker) retryAt(email domain.OutboxEmail, err error) *time.Time {
+	if email.Attempts >= w.config.MaxAttempts || errors.Is(err, errUnrenderable) {
+		return nil
+	}
+
+	delay := retryBase
+	for i := 1; i < email.Attempts && delay < retryMax; i++ {
+		delay *= 2
+	}
+	at := w.now().UTC().Add(min(delay, retryMax))
+	return &at
+}
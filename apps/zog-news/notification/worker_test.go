@@ -0,0 +1,138 @@
+package notification_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"zog-news/config"
+	"zog-news/domain"
+	"zog-news/notification"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeOutbox struct {
+	due    []domain.OutboxEmail
+	sent   []string
+	failed map[string]*time.Time
+}
+
+func (f *fakeOutbox) ClaimEmails(_ context.Context, _, _ time.Time, _ int) ([]domain.OutboxEmail, error) {
+	due := f.due
+	f.due = nil
+	return due, nil
+}
+
+func (f *fakeOutbox) MarkEmailSent(_ context.Context, id string, _ time.Time) error {
+	f.sent = append(f.sent, id)
+	return nil
+}
+
+func (f *fakeOutbox) MarkEmailFailed(_ context.Context, id string, _ string, retryAt *time.Time) error {
+	if f.failed == nil {
+		f.failed = make(map[string]*time.Time)
+	}
+	f.failed[id] = retryAt
+	return nil
+}
+
+type fakeSender struct {
+	err      error
+	messages []notification.Message
+}
+
+func (f *fakeSender) Send(_ context.Context, msg notification.Message) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.messages = append(f.messages, msg)
+	return nil
+}
+
+func articleEmail(id string, attempts int) domain.OutboxEmail {
+	return domain.OutboxEmail{
+		ID:        id,
+		Template:  domain.EmailArticlePublished,
+		Recipient: "john@example.com",
+		Attempts:  attempts,
+		Data: map[string]string{
+			"recipient_name": "John Doe",
+			"article_id":     "d4b8583d-5038-4838-bcd7-3d8dddfedd6a",
+			"article_title":  "Breaking News",
+			"article_slug":   "breaking-news",
+			"author_name":    "John Doe",
+		},
+	}
+}
+
+func TestRenderTemplates(t *testing.T) {
+	t.Parallel()
+
+	data := articleEmail("1", 1).Data
+	for _, name := range []string{domain.EmailArticleSubmitted, domain.EmailArticlePublished} {
+		msg, err := notification.Render(name, data)
+		require.NoError(t, err, name)
+		assert.Contains(t, msg.Subject, "Breaking News", name)
+		assert.Contains(t, msg.Text, "Hi John Doe", name)
+		assert.Contains(t, msg.HTML, "<strong>Breaking News</strong>", name)
+	}
+
+	_, err := notification.Render("missing", data)
+	assert.ErrorIs(t, err, notification.ErrUnknownTemplate)
+
+	_, err = notification.Render(domain.EmailArticlePublished, map[string]string{})
+	assert.Error(t, err)
+}
+
+func TestWorkerDeliverDue(t *testing.T) {
+	t.Parallel()
+
+	mailConfig := config.MailConfig{Interval: time.Second, MaxAttempts: 3}
+
+	t.Run("Sent", func(t *testing.T) {
+		outbox := &fakeOutbox{due: []domain.OutboxEmail{articleEmail("1", 1)}}
+		sender := &fakeSender{}
+
+		sent, err := notification.NewWorker(outbox, sender, mailConfig).DeliverDue(context.Background())
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, sent)
+		assert.Equal(t, []string{"1"}, outbox.sent)
+		require.Len(t, sender.messages, 1)
+		assert.Equal(t, "1", sender.messages[0].ID)
+		assert.Equal(t, "john@example.com", sender.messages[0].To)
+		assert.Equal(t, "Published: Breaking News", sender.messages[0].Subject)
+	})
+
+	t.Run("RetriedWithBackoff", func(t *testing.T) {
+		outbox := &fakeOutbox{due: []domain.OutboxEmail{articleEmail("1", 1), articleEmail("2", 2)}}
+		sender := &fakeSender{err: errors.New("connection refused")}
+
+		before := time.Now().UTC()
+		sent, err := notification.NewWorker(outbox, sender, mailConfig).DeliverDue(context.Background())
+		require.NoError(t, err)
+
+		assert.Zero(t, sent)
+		require.NotNil(t, outbox.failed["1"])
+		require.NotNil(t, outbox.failed["2"])
+		assert.WithinDuration(t, before.Add(30*time.Second), *outbox.failed["1"], 5*time.Second)
+		assert.WithinDuration(t, before.Add(time.Minute), *outbox.failed["2"], 5*time.Second)
+	})
+
+	t.Run("GivenUp", func(t *testing.T) {
+		broken := articleEmail("2", 1)
+		broken.Template = "missing"
+		outbox := &fakeOutbox{due: []domain.OutboxEmail{articleEmail("1", 3), broken}}
+		sender := &fakeSender{err: errors.New("connection refused")}
+
+		_, err := notification.NewWorker(outbox, sender, mailConfig).DeliverDue(context.Background())
+		require.NoError(t, err)
+
+		require.Contains(t, outbox.failed, "1")
+		require.Contains(t, outbox.failed, "2")
+		assert.Nil(t, outbox.failed["1"])
+		assert.Nil(t, outbox.failed["2"])
+	})
+}
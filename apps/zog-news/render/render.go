@@ -0,0 +1,151 @@
+// Package render turns the content of an article into sanitized HTML and
+// derives the plain text excerpt and reading time shown with it.
+//
+// Whatever the source format, the HTML goes through the same sanitizer
+// policy, so markdown with inline HTML and HTML articles can't smuggle in
+// scripts, event handlers or javascript: links. Plain text is escaped and
+// split into paragraphs on blank lines.
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+	"zog-news/domain"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	goldmarkhtml "github.com/yuin/goldmark/renderer/html"
+)
+
+const (
+	// ExcerptLength caps excerpts, in characters
+	ExcerptLength = 280
+	// WordsPerMinute is the reading speed reading times are estimated with
+	WordsPerMinute = 200
+)
+
+var (
+	// inline HTML is kept so the sanitizer decides what stays, instead of
+	// goldmark dropping all of it
+	markdown = goldmark.New(
+		goldmark.WithExtensions(extension.GFM),
+		goldmark.WithRendererOptions(goldmarkhtml.WithUnsafe()),
+	)
+
+	// policy is what articles may contain: the usual formatting, links,
+	// images and tables, plus the language class goldmark puts on code
+	policy = func() *bluemonday.Policy {
+		p := bluemonday.UGCPolicy()
+		p.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+-]+$`)).OnElements("code")
+		return p
+	}()
+
+	// text keeps only the text of sanitized HTML, the space added for every
+	// stripped tag keeps words of adjacent blocks apart
+	text = func() *bluemonday.Policy {
+		p := bluemonday.StrictPolicy()
+		p.AddSpaceWhenStrippingTag(true)
+		return p
+	}()
+
+	// paragraphBreak is a blank line between two paragraphs of plain text
+	paragraphBreak = regexp.MustCompile(`\n[ \t]*\n`)
+)
+
+// Content is an article body rendered for display
+type Content struct {
+	HTML        string
+	Excerpt     string
+	ReadingTime int
+}
+
+// Render renders source written in format to sanitized HTML and derives its
+// excerpt and reading time
+func Render(format domain.ContentFormat, source string) (*Content, error) {
+	var rendered string
+	switch format {
+	case domain.FormatMarkdown:
+		var buf bytes.Buffer
+		if err := markdown.Convert([]byte(source), &buf); err != nil {
+			return nil, err
+		}
+		rendered = policy.Sanitize(buf.String())
+	case domain.FormatHTML:
+		rendered = policy.Sanitize(source)
+	case domain.FormatPlain:
+		rendered = plain(source)
+	default:
+		return nil, fmt.Errorf("%w: unknown content format %q", domain.ErrBadParamInput, format)
+	}
+
+	plainText := Text(rendered)
+	return &Content{
+		HTML:        rendered,
+		Excerpt:     Excerpt(plainText),
+		ReadingTime: ReadingTime(plainText),
+	}, nil
+}
+
+// Text returns the text of sanitized HTML with its whitespace collapsed
+func Text(sanitized string) string {
+	return strings.Join(strings.Fields(html.UnescapeString(text.Sanitize(sanitized))), " ")
+}
+
+// Markers ts_headline wraps the matches of a search in, private use
+// characters no article text contains
+const (
+	HighlightStart = "\uE000"
+	HighlightStop  = "\uE001"
+)
+
+// Highlight turns a search headline cut from sanitized HTML into the plain
+// text of the fragment, escaped, with <mark> around the matches as the only
+// markup
+func Highlight(headline string) string {
+	s := html.EscapeString(Text(headline))
+	s = strings.ReplaceAll(s, HighlightStart, "<mark>")
+	return strings.ReplaceAll(s, HighlightStop, "</mark>")
+}
+
+// Excerpt returns the start of s cut at a word boundary, whitespace runs are
+// collapsed to single spaces
+func Excerpt(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if utf8.RuneCountInString(s) <= ExcerptLength {
+		return s
+	}
+
+	runes := []rune(s)[:ExcerptLength]
+	if cut := strings.LastIndexByte(string(runes), ' '); cut > 0 {
+		return string(runes)[:cut] + "…"
+	}
+	return string(runes) + "…"
+}
+
+// ReadingTime estimates the minutes it takes to read s, rounded up
+func ReadingTime(s string) int {
+	words := len(strings.Fields(s))
+	return (words + WordsPerMinute - 1) / WordsPerMinute
+}
+
+// plain escapes plain text and wraps its paragraphs, a single line break
+// within a paragraph is kept as <br>. The migration adding content formats
+// renders existing articles the same way in SQL.
+func plain(source string) string {
+	source = strings.ReplaceAll(source, "\r\n", "\n")
+	var paragraphs []string
+	for _, paragraph := range paragraphBreak.Split(source, -1) {
+		paragraph = strings.TrimSpace(paragraph)
+		if paragraph == "" {
+			continue
+		}
+		escaped := strings.ReplaceAll(html.EscapeString(paragraph), "\n", "<br>\n")
+		paragraphs = append(paragraphs, "<p>"+escaped+"</p>")
+	}
+	return strings.Join(paragraphs, "\n")
+}
@@ -0,0 +1,102 @@
+package render_test
+
+import (
+	"strings"
+	"testing"
+	"zog-news/domain"
+	"zog-news/render"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Markdown", func(t *testing.T) {
+		source := "# Breaking\n\nSome **bold** news, [source](https://example.com).\n\n```go\nfmt.Println(1)\n```\n"
+		content, err := render.Render(domain.FormatMarkdown, source)
+		require.NoError(t, err)
+
+		assert.Contains(t, content.HTML, "<h1>Breaking</h1>")
+		assert.Contains(t, content.HTML, "<strong>bold</strong>")
+		assert.Contains(t, content.HTML, `<a href="https://example.com" rel="nofollow">source</a>`)
+		assert.Contains(t, content.HTML, `<code class="language-go">`)
+		assert.Equal(t, "Breaking Some bold news, source . fmt.Println(1)", content.Excerpt)
+		assert.Equal(t, 1, content.ReadingTime)
+	})
+
+	t.Run("MarkdownInlineHTML", func(t *testing.T) {
+		source := "Hello <script>alert(1)</script><em onclick=\"steal()\">there</em> [x](javascript:alert(1))"
+		content, err := render.Render(domain.FormatMarkdown, source)
+		require.NoError(t, err)
+
+		assert.NotContains(t, content.HTML, "script")
+		assert.NotContains(t, content.HTML, "onclick")
+		assert.NotContains(t, content.HTML, "javascript:")
+		assert.Contains(t, content.HTML, "<em>there</em>")
+	})
+
+	t.Run("HTML", func(t *testing.T) {
+		content, err := render.Render(domain.FormatHTML, `<p style="color:red">Fish &amp; chips</p><iframe src="https://example.com"></iframe>`)
+		require.NoError(t, err)
+
+		assert.Equal(t, "<p>Fish &amp; chips</p>", content.HTML)
+		assert.Equal(t, "Fish & chips", content.Excerpt)
+	})
+
+	t.Run("Plain", func(t *testing.T) {
+		content, err := render.Render(domain.FormatPlain, "  First <line>\r\nsecond line\n \nNext paragraph\n\n\n")
+		require.NoError(t, err)
+
+		assert.Equal(t, "<p>First &lt;line&gt;<br>\nsecond line</p>\n<p>Next paragraph</p>", content.HTML)
+		assert.Equal(t, "First <line> second line Next paragraph", content.Excerpt)
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		content, err := render.Render(domain.FormatPlain, " \n ")
+		require.NoError(t, err)
+
+		assert.Empty(t, content.HTML)
+		assert.Zero(t, content.ReadingTime)
+	})
+
+	t.Run("UnknownFormat", func(t *testing.T) {
+		_, err := render.Render("rtf", "text")
+		assert.ErrorIs(t, err, domain.ErrBadParamInput)
+	})
+}
+
+func TestExcerpt(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "Short content", render.Excerpt("  Short\n\ncontent  "))
+
+	excerpt := render.Excerpt(strings.Repeat("word ", 100))
+	assert.True(t, strings.HasSuffix(excerpt, "word…"), excerpt)
+	assert.LessOrEqual(t, len([]rune(excerpt)), render.ExcerptLength+1)
+}
+
+func TestHighlight(t *testing.T) {
+	t.Parallel()
+
+	content, err := render.Render(domain.FormatHTML,
+		`<p>Breaking <b>news</b> &amp; more</p><script>alert("xss")</script><img src="x" onerror="alert(1)">`)
+	require.NoError(t, err)
+	headline := strings.Replace(content.HTML, "news", render.HighlightStart+"news"+render.HighlightStop, 1)
+
+	assert.Equal(t, "Breaking <mark>news</mark> &amp; more", render.Highlight(headline))
+
+	// whatever reaches it, only the marks stay markup
+	raw := `<script>alert("xss")</script> ` + render.HighlightStart + "news" + render.HighlightStop
+	highlighted := render.Highlight(raw)
+	assert.NotContains(t, highlighted, "<script")
+	assert.Contains(t, highlighted, "<mark>news</mark>")
+}
+
+func TestReadingTime(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 1, render.ReadingTime("a few words"))
+	assert.Equal(t, 2, render.ReadingTime(strings.Repeat("word ", render.WordsPerMinute+1)))
+}
@@ -5,11 +5,20 @@ import (
 )
 
 func SeedArticles(db *sql.DB) error {
-    // Insert users with password hashes
+    // Authors come from SeedUsers
     _, err := db.Exec(`
-        INSERT INTO articles (title, content, author, status) VALUES
-        ('Great news title', 'Artikel ini membahas tentang perubahan teknologi...', 'Seya', 'published'),
-        ('Bad clickbait', 'Perkembangan teknologi yang pesat...', 'Cikal', 'draft')
+        INSERT INTO articles (
+            title, slug, content, content_format, content_html, excerpt, reading_time,
+            author_id, status, published_at
+        ) VALUES
+        ('Great news title', 'great-news-title', 'Artikel ini membahas tentang perubahan teknologi...',
+            'plain', '<p>Artikel ini membahas tentang perubahan teknologi...</p>',
+            'Artikel ini membahas tentang perubahan teknologi...', 1,
+            (SELECT id FROM users WHERE email = 'seya@example.com'), 'published', NOW()),
+        ('Bad clickbait', 'bad-clickbait', 'Perkembangan teknologi yang pesat...',
+            'plain', '<p>Perkembangan teknologi yang pesat...</p>',
+            'Perkembangan teknologi yang pesat...', 1,
+            (SELECT id FROM users WHERE email = 'cikal@example.com'), 'draft', NULL)
         ON CONFLICT DO NOTHING;
     `)
 
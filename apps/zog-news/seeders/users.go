@@ -0,0 +1,27 @@
+package seeders
+
+import (
+	"database/sql"
+	"zog-news/utils"
+)
+
+// seedPassword is shared by every seeded account, for local development only
+const seedPassword = "password123"
+
+func SeedUsers(db *sql.DB) error {
+    hash, err := utils.HashPassword(seedPassword)
+    if err != nil {
+        return err
+    }
+
+    // Insert one user per role
+    _, err = db.Exec(`
+        INSERT INTO users (name, email, role, password_hash) VALUES
+        ('Admin', 'admin@example.com', 'admin', $1),
+        ('Seya', 'seya@example.com', 'editor', $1),
+        ('Cikal', 'cikal@example.com', 'author', $1)
+        ON CONFLICT DO NOTHING;
+    `, hash)
+
+    return err
+}
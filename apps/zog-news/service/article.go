@@ -3,32 +3,49 @@ package service
 import (
 	"context"
 	"fmt"
+	"time"
 	"zog-news/domain"
+	"zog-news/render"
 
 	"github.com/google/uuid"
 	"go.opentelemetry.io/otel"
 )
 
 type ArticleRepository interface {
-	CreateArticle(ctx context.Context, article *domain.CreateArticleRequest) (*domain.Article, error)
-	GetArticleList(ctx context.Context, filter *domain.ArticleFilter) ([]domain.Article, error)
+	CreateArticle(ctx context.Context, article *domain.Article) (*domain.Article, error)
+	GetArticleList(ctx context.Context, filter *domain.ArticleFilter) ([]domain.Article, domain.CursorMeta, error)
 	GetArticle(ctx context.Context, id uuid.UUID) (*domain.Article, error)
-	UpdateArticle(ctx context.Context, id uuid.UUID, article *domain.Article) (*domain.Article, error)
-	DeleteArticle(ctx context.Context, id uuid.UUID) error
+	GetArticleIDBySlug(ctx context.Context, slug string) (uuid.UUID, error)
+	UpdateArticle(ctx context.Context, id uuid.UUID, article *domain.Article, editorID string) (*domain.Article, error)
+	DeleteArticle(ctx context.Context, id uuid.UUID, version int) error
+	SearchArticles(ctx context.Context, filter *domain.ArticleSearchFilter) ([]domain.ArticleSearchResult, error)
+	TransitionArticle(ctx context.Context, id uuid.UUID, transition *domain.ArticleTransition) (*domain.Article, error)
+	PublishScheduledArticles(ctx context.Context, now time.Time) (int64, error)
+
+	GetDeletedArticles(ctx context.Context) ([]domain.Article, error)
+	RestoreArticle(ctx context.Context, id uuid.UUID) (*domain.Article, error)
+	PurgeArticle(ctx context.Context, id uuid.UUID) error
+
+	GetArticleRevisions(ctx context.Context, articleID uuid.UUID) ([]domain.ArticleRevision, error)
+	GetArticleRevision(ctx context.Context, articleID uuid.UUID, revision int) (*domain.ArticleRevision, error)
 
     GetTopicsByArticleID(ctx context.Context, articleID uuid.UUID) ([]domain.Topic, error)
     AddTopicToArticle(ctx context.Context, articleID uuid.UUID, topicID string) error
-    // AddTopicsToArticle(ctx context.Context, articleID uuid.UUID, topicIDs []string) error
+    AddTopicsToArticle(ctx context.Context, articleID uuid.UUID, topicIDs []string) error
+    ReplaceArticleTopics(ctx context.Context, articleID uuid.UUID, topicIDs []string) error
+    UpdateArticleTopics(ctx context.Context, articleID uuid.UUID, add []string, remove []string) error
     RemoveTopicFromArticle(ctx context.Context, articleID uuid.UUID, topicID string) error
 }
 
 type ArticleService struct {
 	articleRepo ArticleRepository
+	now         func() time.Time
 }
 
 func NewArticleService(a ArticleRepository) *ArticleService {
 	return &ArticleService{
 		articleRepo: a,
+		now:         time.Now,
 	}
 }
 
@@ -37,14 +54,53 @@ func (a *ArticleService) CreateArticle(
 	ctx context.Context,
 	u *domain.CreateArticleRequest,
 ) (*domain.Article, error) {
-	createdArticle, err := a.articleRepo.CreateArticle(ctx, u)
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := canCreateArticle(caller); err != nil {
+		return nil, err
+	}
+
+	topicIDs, topicNames, err := normalizeArticleTopics(caller, u.TopicIDs, u.TopicNames)
+	if err != nil {
+		return nil, err
+	}
+	attachmentIDs, err := normalizeAttachmentIDs(u.AttachmentIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	article := &domain.Article{
+		Title:         u.Title,
+		Content:       u.Content,
+		ContentFormat: u.ContentFormat,
+		Status:        domain.StatusDraft,
+		AuthorID:      caller.ID,
+		TopicIDs:      topicIDs,
+		TopicNames:    topicNames,
+		CoverMediaID:  u.CoverMediaID,
+		AttachmentIDs: attachmentIDs,
+	}
+	if article.CoverMediaID != nil && *article.CoverMediaID == "" {
+		article.CoverMediaID = nil
+	}
+	if article.ContentFormat == "" {
+		article.ContentFormat = domain.DefaultContentFormat
+	}
+	if err := renderContent(article); err != nil {
+		return nil, err
+	}
+
+	createdArticle, err := a.articleRepo.CreateArticle(ctx, article)
 	if err != nil {
 		return nil, err
 	}
 	return createdArticle, nil
 }
 
-// GetArticle fetches a article by ID.
+// GetArticle fetches a article by ID, unpublished ones only for callers
+// allowed to see them.
 func (a *ArticleService) GetArticle(
 	ctx context.Context,
 	id uuid.UUID,
@@ -57,52 +113,279 @@ func (a *ArticleService) GetArticle(
 	if err != nil {
 		return nil, err
 	}
+	caller, _ := domain.AuthUserFromContext(ctx)
+	if err := canViewArticle(caller, article); err != nil {
+		return nil, err
+	}
+	return article, nil
+}
+
+// GetArticleBySlug fetches an article by its current or a previous slug, the
+// returned article carries the current one.
+func (a *ArticleService) GetArticleBySlug(ctx context.Context, slug string) (*domain.Article, error) {
+	tracer := otel.Tracer("service.article")
+	ctxTrace, span := tracer.Start(ctx, "ArticleService.GetArticleBySlug")
+	defer span.End()
+
+	id, err := a.articleRepo.GetArticleIDBySlug(ctxTrace, slug)
+	if err != nil {
+		return nil, err
+	}
+	article, err := a.articleRepo.GetArticle(ctxTrace, id)
+	if err != nil {
+		return nil, err
+	}
+	caller, _ := domain.AuthUserFromContext(ctx)
+	if err := canViewArticle(caller, article); err != nil {
+		return nil, err
+	}
 	return article, nil
 }
 
-// UpdateArticle updates title/content/author/status of an existing article.
+// UpdateArticle updates title/content of an existing article, the status only
+// changes through the workflow actions. A non-zero u.Version must match the
+// stored version.
 func (a *ArticleService) UpdateArticle(
 	ctx context.Context,
 	id uuid.UUID,
 	u *domain.Article,
 ) (*domain.Article, error) {
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
 
 	existing, err := a.articleRepo.GetArticle(ctx, id)
 	if err != nil {
 		return nil, err
 	}
-	if existing == nil {
-		return nil, domain.ErrArticleNotFound
+	if err := canEditArticle(caller, existing); err != nil {
+		return nil, err
+	}
+	if u.Version != 0 && u.Version != existing.Version {
+		return nil, domain.ErrPreconditionFailed
+	}
+	if u.Status != "" && u.Status != existing.Status {
+		return nil, fmt.Errorf("%w: use the workflow actions to change the status", domain.ErrInvalidTransition)
+	}
+
+	topicIDs, topicNames, err := normalizeArticleTopics(caller, u.TopicIDs, u.TopicNames)
+	if err != nil {
+		return nil, err
+	}
+	attachmentIDs, err := normalizeAttachmentIDs(u.AttachmentIDs)
+	if err != nil {
+		return nil, err
 	}
 
 	existing.Title = u.Title
 	existing.Content = u.Content
-	existing.Author = u.Author
-	existing.Status = u.Status
+	if u.ContentFormat != "" {
+		existing.ContentFormat = u.ContentFormat
+	}
+	existing.TopicIDs = topicIDs
+	existing.TopicNames = topicNames
+	// no cover keeps the current one, an empty one removes it
+	if u.CoverMediaID != nil {
+		existing.CoverMediaID = u.CoverMediaID
+		if *u.CoverMediaID == "" {
+			existing.CoverMediaID = nil
+		}
+	}
+	existing.AttachmentIDs = attachmentIDs
+	if err := renderContent(existing); err != nil {
+		return nil, err
+	}
+
+	return a.articleRepo.UpdateArticle(ctx, id, existing, caller.ID)
+}
 
-	_, err = a.articleRepo.UpdateArticle(ctx, id, existing)
+// renderContent renders the content of an article to sanitized HTML and
+// fills in what is derived from it.
+func renderContent(article *domain.Article) error {
+	content, err := render.Render(article.ContentFormat, article.Content)
 	if err != nil {
+		return err
+	}
+	article.ContentHTML = content.HTML
+	article.Excerpt = content.Excerpt
+	article.ReadingTimeMinutes = content.ReadingTime
+	return nil
+}
+
+// normalizeAttachmentIDs validates the attachments sent with an article. A
+// nil list stays nil so an update without attachments keeps the current ones.
+func normalizeAttachmentIDs(ids []string) ([]string, error) {
+	if ids == nil {
+		return nil, nil
+	}
+	return domain.NormalizeMediaIDs(ids)
+}
+
+// normalizeArticleTopics validates the topics sent inline with an article.
+// Nil lists stay nil so an update without topics keeps the current ones.
+func normalizeArticleTopics(caller *domain.AuthUser, ids, names []string) ([]string, []string, error) {
+	if ids != nil {
+		var err error
+		if ids, err = domain.NormalizeTopicIDs(ids); err != nil {
+			return nil, nil, err
+		}
+	}
+	if names != nil {
+		names = domain.NormalizeTopicNames(names)
+		if len(names) > 0 {
+			if err := canCreateTopicsInline(caller); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+	return ids, names, nil
+}
+
+// GetArticleRevisions lists the revision history of an article.
+func (a *ArticleService) GetArticleRevisions(
+	ctx context.Context,
+	id uuid.UUID,
+) ([]domain.ArticleRevision, error) {
+	if _, err := a.articleForRevisions(ctx, id); err != nil {
 		return nil, err
 	}
+	return a.articleRepo.GetArticleRevisions(ctx, id)
+}
 
-	return existing, nil
+// GetArticleRevision fetches a single revision of an article.
+func (a *ArticleService) GetArticleRevision(
+	ctx context.Context,
+	id uuid.UUID,
+	revision int,
+) (*domain.ArticleRevision, error) {
+	if _, err := a.articleForRevisions(ctx, id); err != nil {
+		return nil, err
+	}
+	if revision < 1 {
+		return nil, domain.ErrRevisionNotFound
+	}
+	return a.articleRepo.GetArticleRevision(ctx, id, revision)
+}
+
+// DiffArticleRevisions compares two revisions of an article line by line.
+func (a *ArticleService) DiffArticleRevisions(
+	ctx context.Context,
+	id uuid.UUID,
+	filter *domain.RevisionDiffFilter,
+) (*domain.ArticleRevisionDiff, error) {
+	if _, err := a.articleForRevisions(ctx, id); err != nil {
+		return nil, err
+	}
+	if filter.From < 0 || filter.To < 0 {
+		return nil, fmt.Errorf("%w: revisions must be positive", domain.ErrBadParamInput)
+	}
+
+	// zero asks the repository for the latest revision
+	to, err := a.articleRepo.GetArticleRevision(ctx, id, filter.To)
+	if err != nil {
+		return nil, err
+	}
+
+	fromRevision := filter.From
+	if fromRevision == 0 {
+		fromRevision = to.Revision - 1
+	}
+	if fromRevision < 1 {
+		// the first revision is compared against an empty article
+		from := &domain.ArticleRevision{ArticleID: to.ArticleID}
+		diff := domain.DiffRevisions(from, to)
+		return &diff, nil
+	}
+
+	from, err := a.articleRepo.GetArticleRevision(ctx, id, fromRevision)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := domain.DiffRevisions(from, to)
+	return &diff, nil
+}
+
+// RestoreArticleRevision copies an old revision back onto the article, which
+// is recorded as a new revision so the restore itself can be undone.
+func (a *ArticleService) RestoreArticleRevision(
+	ctx context.Context,
+	id uuid.UUID,
+	revision int,
+) (*domain.Article, error) {
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := a.articleRepo.GetArticle(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := canEditArticle(caller, existing); err != nil {
+		return nil, err
+	}
+	if revision < 1 {
+		return nil, domain.ErrRevisionNotFound
+	}
+
+	old, err := a.articleRepo.GetArticleRevision(ctx, id, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	existing.Title = old.Title
+	existing.Content = old.Content
+	existing.ContentFormat = old.ContentFormat
+	if err := renderContent(existing); err != nil {
+		return nil, err
+	}
+	return a.articleRepo.UpdateArticle(ctx, id, existing, caller.ID)
+}
+
+// articleForRevisions loads an article and checks the caller may read its
+// history.
+func (a *ArticleService) articleForRevisions(ctx context.Context, id uuid.UUID) (*domain.Article, error) {
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	article, err := a.articleRepo.GetArticle(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := canViewRevisions(caller, article); err != nil {
+		return nil, err
+	}
+	return article, nil
 }
 
-// DeleteArticle removes a article by ID.
+// DeleteArticle removes a article by ID, a non-zero version must match the
+// stored version.
 func (a *ArticleService) DeleteArticle(
 	ctx context.Context,
 	id uuid.UUID,
+	version int,
 ) error {
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		return err
+	}
 
 	article, err := a.articleRepo.GetArticle(ctx, id)
 	if err != nil {
 		return err
 	}
-	if article == nil {
-		return domain.ErrArticleNotFound
+	if err := canDeleteArticle(caller, article); err != nil {
+		return err
+	}
+	if version != 0 && version != article.Version {
+		return domain.ErrPreconditionFailed
 	}
 
-	err = a.articleRepo.DeleteArticle(ctx, id)
+	err = a.articleRepo.DeleteArticle(ctx, id, article.Version)
 	if err != nil {
 		return err
 	}
@@ -110,14 +393,157 @@ func (a *ArticleService) DeleteArticle(
 	return nil
 }
 
-func (a *ArticleService) GetArticleList(ctx context.Context, filter *domain.ArticleFilter) ([]domain.Article, error) {
-	articles, err := a.articleRepo.GetArticleList(ctx, filter)
+// SubmitArticle sends a draft to the editors for review.
+func (a *ArticleService) SubmitArticle(ctx context.Context, id uuid.UUID) (*domain.Article, error) {
+	return a.transitionArticle(ctx, id, domain.StatusInReview, nil)
+}
+
+// PublishArticle publishes a reviewed article. With a scheduled time in the
+// future the article stays in review until the scheduler publishes it.
+func (a *ArticleService) PublishArticle(
+	ctx context.Context,
+	id uuid.UUID,
+	u *domain.PublishArticleRequest,
+) (*domain.Article, error) {
+	var scheduledAt *time.Time
+	if u != nil && u.ScheduledAt != nil && u.ScheduledAt.After(a.now()) {
+		at := u.ScheduledAt.UTC()
+		scheduledAt = &at
+	}
+	return a.transitionArticle(ctx, id, domain.StatusPublished, scheduledAt)
+}
+
+// ArchiveArticle takes a published article down.
+func (a *ArticleService) ArchiveArticle(ctx context.Context, id uuid.UUID) (*domain.Article, error) {
+	return a.transitionArticle(ctx, id, domain.StatusArchived, nil)
+}
+
+// RejectArticle sends an article in review back to its author as a draft.
+func (a *ArticleService) RejectArticle(ctx context.Context, id uuid.UUID) (*domain.Article, error) {
+	return a.transitionArticle(ctx, id, domain.StatusDraft, nil)
+}
+
+// PublishScheduledArticles publishes every article whose scheduled time has
+// passed, it is run periodically by the scheduler.
+func (a *ArticleService) PublishScheduledArticles(ctx context.Context) (int64, error) {
+	tracer := otel.Tracer("service.article")
+	ctxTrace, span := tracer.Start(ctx, "ArticleService.PublishScheduledArticles")
+	defer span.End()
+
+	return a.articleRepo.PublishScheduledArticles(ctxTrace, a.now().UTC())
+}
+
+func (a *ArticleService) transitionArticle(
+	ctx context.Context,
+	id uuid.UUID,
+	to domain.ArticleStatus,
+	scheduledAt *time.Time,
+) (*domain.Article, error) {
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	article, err := a.articleRepo.GetArticle(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := canTransitionArticle(caller, article, to); err != nil {
+		return nil, err
+	}
+	if !article.Status.CanTransitionTo(to) {
+		return nil, fmt.Errorf("%w: %s to %s", domain.ErrInvalidTransition, article.Status, to)
+	}
+
+	transition := &domain.ArticleTransition{From: article.Status, To: to}
+	switch {
+	case scheduledAt != nil:
+		// stays in review until the scheduler picks it up
+		transition.To = domain.StatusInReview
+		transition.ScheduledAt = scheduledAt
+	case to == domain.StatusPublished:
+		now := a.now().UTC()
+		transition.PublishedAt = &now
+	}
+
+	return a.articleRepo.TransitionArticle(ctx, id, transition)
+}
+
+// GetDeletedArticles lists the articles in the trash.
+func (a *ArticleService) GetDeletedArticles(ctx context.Context) ([]domain.Article, error) {
+	caller, err := callerFromContext(ctx)
 	if err != nil {
-		fmt.Println(err)
+		return nil, err
+	}
+	if err := canManageArticleTrash(caller); err != nil {
 		return nil, err
 	}
 
-	return articles, nil
+	return a.articleRepo.GetDeletedArticles(ctx)
+}
+
+// RestoreArticle takes an article out of the trash.
+func (a *ArticleService) RestoreArticle(ctx context.Context, id uuid.UUID) (*domain.Article, error) {
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := canManageArticleTrash(caller); err != nil {
+		return nil, err
+	}
+
+	return a.articleRepo.RestoreArticle(ctx, id)
+}
+
+// PurgeArticle permanently removes an article from the trash.
+func (a *ArticleService) PurgeArticle(ctx context.Context, id uuid.UUID) error {
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	if err := canPurge(caller); err != nil {
+		return err
+	}
+
+	return a.articleRepo.PurgeArticle(ctx, id)
+}
+
+// GetArticleList fetches a page of the articles the caller may read and the
+// cursor to the next page.
+func (a *ArticleService) GetArticleList(ctx context.Context, filter *domain.ArticleFilter) ([]domain.Article, domain.CursorMeta, error) {
+	if filter == nil {
+		filter = &domain.ArticleFilter{}
+	}
+	filter.Scope = articleScope(ctx)
+
+	articles, meta, err := a.articleRepo.GetArticleList(ctx, filter)
+	if err != nil {
+		return nil, domain.CursorMeta{}, err
+	}
+
+	return articles, meta, nil
+}
+
+// SearchArticles runs a ranked full-text search over the titles and content of
+// the articles the caller may read.
+func (a *ArticleService) SearchArticles(
+	ctx context.Context,
+	filter *domain.ArticleSearchFilter,
+) ([]domain.ArticleSearchResult, error) {
+	tracer := otel.Tracer("service.article")
+	ctxTrace, span := tracer.Start(ctx, "ArticleService.SearchArticles")
+	defer span.End()
+
+	if err := filter.Normalize(); err != nil {
+		return nil, err
+	}
+	filter.Scope = articleScope(ctx)
+
+	results, err := a.articleRepo.SearchArticles(ctxTrace, filter)
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
 }
 
 func (a *ArticleService) GetTopicsByArticleID(
@@ -136,19 +562,19 @@ func (a *ArticleService) AddTopicToArticle(
     articleID uuid.UUID,
     topicID string,
 ) error {
+    caller, err := callerFromContext(ctx)
+    if err != nil {
+        return err
+    }
+
     article, err := a.articleRepo.GetArticle(ctx, articleID)
     if err != nil {
         return err
     }
-    if article == nil {
-        return domain.ErrArticleNotFound
+    if err := canEditArticle(caller, article); err != nil {
+        return err
     }
 
-    // TODO: article topics are not bound here?
-    // if err := article.AddTopicID(topicID); err != nil {
-    //     return err
-    // }
-
     return a.articleRepo.AddTopicToArticle(ctx, articleID, topicID)
 }
 
@@ -157,16 +583,77 @@ func (a *ArticleService) RemoveTopicFromArticle(
     articleID uuid.UUID,
     topicID string,
 ) error {
+    caller, err := callerFromContext(ctx)
+    if err != nil {
+        return err
+    }
+
     article, err := a.articleRepo.GetArticle(ctx, articleID)
     if err != nil {
         return err
     }
-    if article == nil {
-        return domain.ErrArticleNotFound
+    if err := canEditArticle(caller, article); err != nil {
+        return err
     }
-    // TODO: article topics are not bound here?
-    // if err := article.RemoveTopicID(topicID); err != nil {
-    //     return err
-    // }
     return a.articleRepo.RemoveTopicFromArticle(ctx, articleID, topicID)
 }
+
+// ReplaceArticleTopics sets the full topic list of an article and returns it.
+func (a *ArticleService) ReplaceArticleTopics(
+    ctx context.Context,
+    articleID uuid.UUID,
+    topicIDs []string,
+) ([]domain.Topic, error) {
+    if err := a.canEditArticleTopics(ctx, articleID); err != nil {
+        return nil, err
+    }
+
+    topicIDs, err := domain.NormalizeTopicIDs(topicIDs)
+    if err != nil {
+        return nil, err
+    }
+
+    if err := a.articleRepo.ReplaceArticleTopics(ctx, articleID, topicIDs); err != nil {
+        return nil, err
+    }
+    return a.articleRepo.GetTopicsByArticleID(ctx, articleID)
+}
+
+// PatchArticleTopics adds and removes some topics of an article and returns
+// the resulting list. A topic in both lists ends up added.
+func (a *ArticleService) PatchArticleTopics(
+    ctx context.Context,
+    articleID uuid.UUID,
+    u *domain.PatchArticleTopicsRequest,
+) ([]domain.Topic, error) {
+    if err := a.canEditArticleTopics(ctx, articleID); err != nil {
+        return nil, err
+    }
+
+    add, err := domain.NormalizeTopicIDs(u.Add)
+    if err != nil {
+        return nil, err
+    }
+    remove, err := domain.NormalizeTopicIDs(u.Remove)
+    if err != nil {
+        return nil, err
+    }
+
+    if err := a.articleRepo.UpdateArticleTopics(ctx, articleID, add, remove); err != nil {
+        return nil, err
+    }
+    return a.articleRepo.GetTopicsByArticleID(ctx, articleID)
+}
+
+func (a *ArticleService) canEditArticleTopics(ctx context.Context, articleID uuid.UUID) error {
+    caller, err := callerFromContext(ctx)
+    if err != nil {
+        return err
+    }
+
+    article, err := a.articleRepo.GetArticle(ctx, articleID)
+    if err != nil {
+        return err
+    }
+    return canEditArticle(caller, article)
+}
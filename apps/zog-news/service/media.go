@@ -0,0 +1,227 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"path"
+	"strings"
+	"time"
+	"zog-news/domain"
+	"zog-news/media"
+	"zog-news/storage"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+)
+
+type MediaRepository interface {
+	CreateMedia(ctx context.Context, media *domain.Media) (*domain.Media, error)
+	GetMedia(ctx context.Context, id uuid.UUID) (*domain.Media, error)
+	DeleteMedia(ctx context.Context, id uuid.UUID) error
+}
+
+type MediaService struct {
+	mediaRepo MediaRepository
+	store     storage.Storage
+	// maxSize is the largest upload accepted in bytes, thumbnailSize the
+	// longest edge of thumbnails in pixels
+	maxSize       int64
+	thumbnailSize int
+	now           func() time.Time
+}
+
+func NewMediaService(m MediaRepository, store storage.Storage, maxSize int64, thumbnailSize int) *MediaService {
+	return &MediaService{
+		mediaRepo:     m,
+		store:         store,
+		maxSize:       maxSize,
+		thumbnailSize: thumbnailSize,
+		now:           time.Now,
+	}
+}
+
+// UploadMedia stores an uploaded file and records it. The content type is
+// sniffed from the file itself, images also get their dimensions read and a
+// thumbnail stored next to them.
+func (s *MediaService) UploadMedia(ctx context.Context, r *domain.UploadMediaRequest) (*domain.Media, error) {
+	tracer := otel.Tracer("service.media")
+	ctx, span := tracer.Start(ctx, "MediaService.UploadMedia")
+	defer span.End()
+
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := canUploadMedia(caller); err != nil {
+		return nil, err
+	}
+
+	// one byte past the limit tells a file at the limit from a larger one
+	data, err := io.ReadAll(io.LimitReader(r.Body, s.maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > s.maxSize {
+		return nil, fmt.Errorf("%w: files may be at most %d bytes", domain.ErrMediaTooLarge, s.maxSize)
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("%w: file is empty", domain.ErrBadParamInput)
+	}
+
+	contentType := media.Sniff(data)
+	ext, ok := domain.MediaTypes[contentType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", domain.ErrUnsupportedMediaType, contentType)
+	}
+
+	now := s.now().UTC()
+	id := uuid.NewString()
+	m := &domain.Media{
+		ID:          id,
+		Filename:    cleanFilename(r.Filename, ext),
+		ContentType: contentType,
+		Size:        int64(len(data)),
+		Alt:         strings.TrimSpace(r.Alt),
+		StorageKey:  now.Format("2006/01/") + id + ext,
+		UploadedBy:  caller.ID,
+		CreatedAt:   now,
+	}
+
+	var thumbnail []byte
+	if m.IsImage() {
+		m.Width, m.Height, err = media.Dimensions(data)
+		if errors.Is(err, media.ErrTooManyPixels) {
+			return nil, fmt.Errorf("%w: images may have at most %d pixels", domain.ErrMediaTooLarge, media.MaxPixels)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: image can't be decoded", domain.ErrUnsupportedMediaType)
+		}
+		var thumbnailType string
+		thumbnail, thumbnailType, err = media.Thumbnail(data, s.thumbnailSize)
+		if err != nil {
+			return nil, fmt.Errorf("%w: image can't be decoded", domain.ErrUnsupportedMediaType)
+		}
+		m.ThumbnailKey = now.Format("2006/01/") + id + "-thumbnail" + domain.MediaTypes[thumbnailType]
+	}
+
+	if err := s.store.Put(ctx, m.StorageKey, bytes.NewReader(data), m.Size, m.ContentType); err != nil {
+		return nil, err
+	}
+	if thumbnail != nil {
+		thumbnailType := thumbnailContentType(m.ThumbnailKey)
+		err := s.store.Put(ctx, m.ThumbnailKey, bytes.NewReader(thumbnail), int64(len(thumbnail)), thumbnailType)
+		if err != nil {
+			s.deleteFiles(ctx, m)
+			return nil, err
+		}
+	}
+
+	created, err := s.mediaRepo.CreateMedia(ctx, m)
+	if err != nil {
+		s.deleteFiles(ctx, m)
+		return nil, err
+	}
+	return created, nil
+}
+
+// GetMedia fetches a media by ID.
+func (s *MediaService) GetMedia(ctx context.Context, id uuid.UUID) (*domain.Media, error) {
+	return s.mediaRepo.GetMedia(ctx, id)
+}
+
+// OpenMedia returns the stored file of a media, or its thumbnail. Only
+// images have thumbnails, the caller closes the returned body.
+func (s *MediaService) OpenMedia(ctx context.Context, id uuid.UUID, thumbnail bool) (*domain.Media, *domain.MediaFile, error) {
+	m, err := s.mediaRepo.GetMedia(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	file := &domain.MediaFile{ContentType: m.ContentType, Size: m.Size}
+	key := m.StorageKey
+	if thumbnail {
+		if m.ThumbnailKey == "" {
+			return nil, nil, domain.ErrMediaNotFound
+		}
+		key = m.ThumbnailKey
+		file.ContentType = thumbnailContentType(key)
+		file.Size = 0
+	}
+
+	file.Body, err = s.store.Open(ctx, key)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, nil, domain.ErrMediaNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return m, file, nil
+}
+
+// DeleteMedia removes a media no article uses anymore. The stored files are
+// removed after the record, a file left behind is only logged.
+func (s *MediaService) DeleteMedia(ctx context.Context, id uuid.UUID) error {
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	m, err := s.mediaRepo.GetMedia(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := canDeleteMedia(caller, m); err != nil {
+		return err
+	}
+
+	if err := s.mediaRepo.DeleteMedia(ctx, id); err != nil {
+		return err
+	}
+	s.deleteFiles(ctx, m)
+	return nil
+}
+
+// deleteFiles removes the stored file and thumbnail of a media
+func (s *MediaService) deleteFiles(ctx context.Context, m *domain.Media) {
+	for _, key := range []string{m.StorageKey, m.ThumbnailKey} {
+		if key == "" {
+			continue
+		}
+		if err := s.store.Delete(ctx, key); err != nil {
+			slog.WarnContext(ctx, "Deleting media file failed",
+				slog.String("media_id", m.ID),
+				slog.String("key", key),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+}
+
+// thumbnailContentType tells the content type of a thumbnail from the
+// extension of its key
+func thumbnailContentType(key string) string {
+	ext := path.Ext(key)
+	for contentType, typeExt := range domain.MediaTypes {
+		if typeExt == ext {
+			return contentType
+		}
+	}
+	return "application/octet-stream"
+}
+
+// cleanFilename keeps the last element of the name a client uploaded, some
+// send whole paths, and falls back to a name made from the file type
+func cleanFilename(name, ext string) string {
+	name = strings.TrimSpace(name)
+	if i := strings.LastIndexAny(name, `/\`); i >= 0 {
+		name = name[i+1:]
+	}
+	if name == "" || name == "." || name == ".." {
+		return "upload" + ext
+	}
+	return name
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"zog-news/domain"
+)
+
+// The policy functions below are consulted by every mutating service method
+// and by article reads. Published articles are public, authors may only work
+// on their own drafts, editors may edit, review, publish and archive any
+// article, and admins may additionally manage topics, users and webhooks.
+
+// callerFromContext returns the authenticated caller or ErrUnauthorized.
+func callerFromContext(ctx context.Context) (*domain.AuthUser, error) {
+	caller, ok := domain.AuthUserFromContext(ctx)
+	if !ok {
+		return nil, domain.ErrUnauthorized
+	}
+	return caller, nil
+}
+
+// canCreateArticle checks whether the caller may write articles at all, new
+// articles always start as drafts.
+func canCreateArticle(caller *domain.AuthUser) error {
+	if caller.HasRole(domain.RoleAuthor) {
+		return nil
+	}
+	return domain.ErrForbidden
+}
+
+// canEditArticle checks whether the caller may change an article's content
+// or topics without touching its status.
+func canEditArticle(caller *domain.AuthUser, article *domain.Article) error {
+	if caller.HasRole(domain.RoleEditor) {
+		return nil
+	}
+	if caller.HasRole(domain.RoleAuthor) && article.AuthorID == caller.ID && isDraft(article.Status) {
+		return nil
+	}
+	return domain.ErrForbidden
+}
+
+// canCreateTopicsInline checks whether the caller may attach topics by name
+// to an article, which creates the topics that don't exist yet.
+func canCreateTopicsInline(caller *domain.AuthUser) error {
+	if caller.HasRole(domain.RoleEditor) {
+		return nil
+	}
+	return domain.ErrForbidden
+}
+
+// canTransitionArticle checks a workflow action. Authors may submit their own
+// drafts for review, every other transition is reserved to editors.
+func canTransitionArticle(caller *domain.AuthUser, article *domain.Article, to domain.ArticleStatus) error {
+	if caller.HasRole(domain.RoleEditor) {
+		return nil
+	}
+	if to == domain.StatusInReview {
+		return canEditArticle(caller, article)
+	}
+	return domain.ErrForbidden
+}
+
+// canViewRevisions checks whether the caller may read an article's history,
+// authors keep access to their own articles after they leave draft.
+func canViewRevisions(caller *domain.AuthUser, article *domain.Article) error {
+	if caller.HasRole(domain.RoleEditor) {
+		return nil
+	}
+	if caller.HasRole(domain.RoleAuthor) && article.AuthorID == caller.ID {
+		return nil
+	}
+	return domain.ErrForbidden
+}
+
+// canViewArticle checks whether the caller, nil when anonymous, may read an
+// article. Unpublished articles follow canViewRevisions, anonymous callers
+// get ErrArticleNotFound so unreleased articles don't give away they exist.
+func canViewArticle(caller *domain.AuthUser, article *domain.Article) error {
+	if article.Status == domain.StatusPublished {
+		return nil
+	}
+	if caller == nil {
+		return domain.ErrArticleNotFound
+	}
+	return canViewRevisions(caller, article)
+}
+
+// articleScope limits article lists to what canViewArticle lets the caller
+// read: editors see every article, authors the published ones and their own,
+// and anonymous callers only the published ones.
+func articleScope(ctx context.Context) domain.ArticleScope {
+	caller, ok := domain.AuthUserFromContext(ctx)
+	switch {
+	case ok && caller.HasRole(domain.RoleEditor):
+		return domain.ArticleScope{}
+	case ok && caller.HasRole(domain.RoleAuthor):
+		return domain.ArticleScope{Restricted: true, OwnerID: caller.ID}
+	}
+	return domain.ArticleScope{Restricted: true}
+}
+
+// canViewArticleStats checks whether the caller may read how often an
+// article was viewed, authors may read the stats of their own articles.
+func canViewArticleStats(caller *domain.AuthUser, article *domain.Article) error {
+	return canViewRevisions(caller, article)
+}
+
+// canDeleteArticle follows the same rules as editing.
+func canDeleteArticle(caller *domain.AuthUser, article *domain.Article) error {
+	return canEditArticle(caller, article)
+}
+
+// canManageArticleTrash checks whether the caller may list and restore
+// deleted articles.
+func canManageArticleTrash(caller *domain.AuthUser) error {
+	if caller.HasRole(domain.RoleEditor) {
+		return nil
+	}
+	return domain.ErrForbidden
+}
+
+// canPurge checks whether the caller may permanently remove deleted rows.
+func canPurge(caller *domain.AuthUser) error {
+	if caller.HasRole(domain.RoleAdmin) {
+		return nil
+	}
+	return domain.ErrForbidden
+}
+
+// canManageTopics checks whether the caller may create, rename or delete topics.
+func canManageTopics(caller *domain.AuthUser) error {
+	if caller.HasRole(domain.RoleAdmin) {
+		return nil
+	}
+	return domain.ErrForbidden
+}
+
+// canManageUsers checks whether the caller may change other users.
+func canManageUsers(caller *domain.AuthUser) error {
+	if caller.HasRole(domain.RoleAdmin) {
+		return nil
+	}
+	return domain.ErrForbidden
+}
+
+// canManageWebhooks checks whether the caller may register, list and remove
+// webhooks, which see every event.
+func canManageWebhooks(caller *domain.AuthUser) error {
+	if caller.HasRole(domain.RoleAdmin) {
+		return nil
+	}
+	return domain.ErrForbidden
+}
+
+// canUploadMedia checks whether the caller may upload files for articles.
+func canUploadMedia(caller *domain.AuthUser) error {
+	if caller.HasRole(domain.RoleAuthor) {
+		return nil
+	}
+	return domain.ErrForbidden
+}
+
+// canDeleteMedia checks whether the caller may remove a file, authors may
+// only remove their own uploads.
+func canDeleteMedia(caller *domain.AuthUser, media *domain.Media) error {
+	if caller.HasRole(domain.RoleEditor) {
+		return nil
+	}
+	if caller.HasRole(domain.RoleAuthor) && media.UploadedBy == caller.ID {
+		return nil
+	}
+	return domain.ErrForbidden
+}
+
+func isDraft(status domain.ArticleStatus) bool {
+	return status == "" || status == domain.StatusDraft
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"zog-news/domain"
+)
+
+type SitemapRepository interface {
+	CountSitemapArticles(ctx context.Context) (int, error)
+	GetSitemapArticles(ctx context.Context, offset, limit int) ([]domain.SitemapEntry, error)
+	GetNewsSitemapArticles(ctx context.Context, since time.Time, limit int) ([]domain.SitemapEntry, error)
+	GetSitemapTopics(ctx context.Context, limit int) ([]domain.SitemapEntry, error)
+}
+
+type SitemapService struct {
+	sitemapRepo SitemapRepository
+	now         func() time.Time
+}
+
+func NewSitemapService(s SitemapRepository) *SitemapService {
+	return &SitemapService{
+		sitemapRepo: s,
+		now:         time.Now,
+	}
+}
+
+// CountSitemapArticles counts the published articles, which decides how many
+// sitemap pages they fill.
+func (s *SitemapService) CountSitemapArticles(ctx context.Context) (int, error) {
+	return s.sitemapRepo.CountSitemapArticles(ctx)
+}
+
+// GetSitemapArticles returns a sitemap page of published articles, pages
+// start at 1 and hold up to domain.SitemapMaxURLs articles.
+func (s *SitemapService) GetSitemapArticles(ctx context.Context, page int) ([]domain.SitemapEntry, error) {
+	if page < 1 {
+		return nil, fmt.Errorf("%w: sitemap pages start at 1", domain.ErrBadParamInput)
+	}
+	return s.sitemapRepo.GetSitemapArticles(ctx, (page-1)*domain.SitemapMaxURLs, domain.SitemapMaxURLs)
+}
+
+// GetNewsSitemapArticles returns the articles published within the Google
+// News window, newest first.
+func (s *SitemapService) GetNewsSitemapArticles(ctx context.Context) ([]domain.SitemapEntry, error) {
+	// timestamps are stored without a time zone
+	since := s.now().UTC().Add(-domain.NewsSitemapWindow)
+	return s.sitemapRepo.GetNewsSitemapArticles(ctx, since, domain.NewsSitemapMaxURLs)
+}
+
+// GetSitemapTopics returns the live topics, as many as fit in one sitemap.
+func (s *SitemapService) GetSitemapTopics(ctx context.Context) ([]domain.SitemapEntry, error) {
+	return s.sitemapRepo.GetSitemapTopics(ctx, domain.SitemapMaxURLs)
+}
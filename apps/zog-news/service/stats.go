@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"zog-news/domain"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+)
+
+type StatsRepository interface {
+	GetTrendingArticles(ctx context.Context, filter *domain.TrendingFilter, now time.Time) ([]domain.TrendingArticle, error)
+	GetArticleViews(ctx context.Context, articleID uuid.UUID, since time.Time) ([]domain.DailyViews, error)
+}
+
+type StatsService struct {
+	statsRepo   StatsRepository
+	articleRepo ArticleRepository
+	now         func() time.Time
+}
+
+func NewStatsService(s StatsRepository, a ArticleRepository) *StatsService {
+	return &StatsService{
+		statsRepo:   s,
+		articleRepo: a,
+		now:         time.Now,
+	}
+}
+
+// GetTrendingArticles ranks the published articles by their views in the
+// window of the filter, recent views weigh more.
+func (s *StatsService) GetTrendingArticles(ctx context.Context, filter *domain.TrendingFilter) ([]domain.TrendingArticle, error) {
+	tracer := otel.Tracer("service.stats")
+	ctx, span := tracer.Start(ctx, "StatsService.GetTrendingArticles")
+	defer span.End()
+
+	if err := filter.Normalize(); err != nil {
+		return nil, err
+	}
+	// timestamps are stored without a time zone
+	return s.statsRepo.GetTrendingArticles(ctx, filter, s.now().UTC())
+}
+
+// GetArticleStats returns the views of an article on each of the last days,
+// today included.
+func (s *StatsService) GetArticleStats(ctx context.Context, id uuid.UUID, days int) (*domain.ArticleStats, error) {
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if days < 1 || days > domain.MaxStatsDays {
+		return nil, fmt.Errorf("%w: days must be between 1 and %d", domain.ErrBadParamInput, domain.MaxStatsDays)
+	}
+
+	article, err := s.articleRepo.GetArticle(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := canViewArticleStats(caller, article); err != nil {
+		return nil, err
+	}
+
+	today := s.now().UTC().Truncate(24 * time.Hour)
+	since := today.AddDate(0, 0, 1-days)
+	viewed, err := s.statsRepo.GetArticleViews(ctx, id, since)
+	if err != nil {
+		return nil, err
+	}
+
+	// fill in the days nobody read the article
+	counts := make(map[string]int64, len(viewed))
+	for _, day := range viewed {
+		counts[day.Date] = day.Views
+	}
+	stats := &domain.ArticleStats{
+		ArticleID: article.ID,
+		Days:      make([]domain.DailyViews, 0, days),
+	}
+	for day := since; !day.After(today); day = day.AddDate(0, 0, 1) {
+		date := day.Format(time.DateOnly)
+		stats.Days = append(stats.Days, domain.DailyViews{Date: date, Views: counts[date]})
+		stats.TotalViews += counts[date]
+	}
+	return stats, nil
+}
@@ -13,10 +13,17 @@ type TopicRepository interface {
 	CreateTopic(ctx context.Context, topic *domain.CreateTopicRequest) (*domain.Topic, error)
 	GetTopicList(ctx context.Context, filter *domain.TopicFilter) ([]domain.Topic, error)
 	GetTopic(ctx context.Context, id uuid.UUID) (*domain.Topic, error)
+	GetTopicIDBySlug(ctx context.Context, slug string) (uuid.UUID, error)
 	UpdateTopic(ctx context.Context, id uuid.UUID, topic *domain.Topic) (*domain.Topic, error)
-	DeleteTopic(ctx context.Context, id uuid.UUID) error
+	DeleteTopic(ctx context.Context, id uuid.UUID, version int) error
+	MergeTopics(ctx context.Context, targetID uuid.UUID, sourceIDs []string) (*domain.Topic, error)
 
-    GetTopicArticles(ctx context.Context, id uuid.UUID) ([]domain.Article, error)
+	GetTopicChildren(ctx context.Context, id uuid.UUID) ([]domain.Topic, error)
+    GetTopicArticles(ctx context.Context, id uuid.UUID, filter *domain.TopicArticlesFilter) ([]domain.Article, error)
+
+	GetDeletedTopics(ctx context.Context) ([]domain.Topic, error)
+	RestoreTopic(ctx context.Context, id uuid.UUID) (*domain.Topic, error)
+	PurgeTopic(ctx context.Context, id uuid.UUID) error
 }
 
 type TopicService struct {
@@ -29,11 +36,27 @@ func NewTopicService(a TopicRepository) *TopicService {
 	}
 }
 
-// CreateTopic adds a new topic.
+// CreateTopic adds a new topic, its name must not match another live topic
+// ignoring case and spacing.
 func (a *TopicService) CreateTopic(
 	ctx context.Context,
 	u *domain.CreateTopicRequest,
 ) (*domain.Topic, error) {
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := canManageTopics(caller); err != nil {
+		return nil, err
+	}
+	if err := checkTopicParentID(nil, u.ParentID); err != nil {
+		return nil, err
+	}
+	u.Name = domain.NormalizeTopicName(u.Name)
+	if u.Name == "" {
+		return nil, fmt.Errorf("%w: topic name is required", domain.ErrBadParamInput)
+	}
+
 	createdTopic, err := a.topicRepo.CreateTopic(ctx, u)
 	if err != nil {
 		return nil, err
@@ -57,46 +80,76 @@ func (a *TopicService) GetTopic(
 	return topic, nil
 }
 
-// UpdateTopic updates name/email of an existing topic.
+// GetTopicBySlug fetches a topic by its current or a previous slug, the
+// returned topic carries the current one.
+func (a *TopicService) GetTopicBySlug(ctx context.Context, slug string) (*domain.Topic, error) {
+	id, err := a.topicRepo.GetTopicIDBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	return a.topicRepo.GetTopic(ctx, id)
+}
+
+// UpdateTopic renames an existing topic and moves it under u.ParentID, a
+// non-zero u.Version must match the stored version.
 func (a *TopicService) UpdateTopic(
 	ctx context.Context,
 	id uuid.UUID,
 	u *domain.Topic,
 ) (*domain.Topic, error) {
-
-	existing, err := a.topicRepo.GetTopic(ctx, id)
+	caller, err := callerFromContext(ctx)
 	if err != nil {
 		return nil, err
 	}
-	if existing == nil {
-		return nil, domain.ErrTopicNotFound
+	if err := canManageTopics(caller); err != nil {
+		return nil, err
 	}
 
-	existing.Name = u.Name
-
-	_, err = a.topicRepo.UpdateTopic(ctx, id, existing)
+	existing, err := a.topicRepo.GetTopic(ctx, id)
 	if err != nil {
 		return nil, err
 	}
+	if u.Version != 0 && u.Version != existing.Version {
+		return nil, domain.ErrPreconditionFailed
+	}
+	if err := checkTopicParentID(&id, u.ParentID); err != nil {
+		return nil, err
+	}
+	name := domain.NormalizeTopicName(u.Name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: topic name is required", domain.ErrBadParamInput)
+	}
+
+	existing.Name = name
+	existing.ParentID = u.ParentID
 
-	return existing, nil
+	return a.topicRepo.UpdateTopic(ctx, id, existing)
 }
 
-// DeleteTopic removes a topic by ID.
+// DeleteTopic removes a topic by ID, a non-zero version must match the
+// stored version.
 func (a *TopicService) DeleteTopic(
 	ctx context.Context,
 	id uuid.UUID,
+	version int,
 ) error {
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	if err := canManageTopics(caller); err != nil {
+		return err
+	}
 
 	topic, err := a.topicRepo.GetTopic(ctx, id)
 	if err != nil {
 		return err
 	}
-	if topic == nil {
-		return domain.ErrTopicNotFound
+	if version != 0 && version != topic.Version {
+		return domain.ErrPreconditionFailed
 	}
 
-	err = a.topicRepo.DeleteTopic(ctx, id)
+	err = a.topicRepo.DeleteTopic(ctx, id, topic.Version)
 	if err != nil {
 		return err
 	}
@@ -104,22 +157,125 @@ func (a *TopicService) DeleteTopic(
 	return nil
 }
 
+// MergeTopics folds the source topics into the target, their articles,
+// subtopics and slugs move over and the sources go to the trash.
+func (a *TopicService) MergeTopics(
+	ctx context.Context,
+	targetID uuid.UUID,
+	req *domain.MergeTopicsRequest,
+) (*domain.Topic, error) {
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := canManageTopics(caller); err != nil {
+		return nil, err
+	}
+
+	sourceIDs, err := domain.NormalizeTopicIDs(req.SourceIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(sourceIDs) == 0 {
+		return nil, fmt.Errorf("%w: no topics to merge", domain.ErrBadParamInput)
+	}
+	for _, id := range sourceIDs {
+		if id == targetID.String() {
+			return nil, fmt.Errorf("%w: topic cannot be merged into itself", domain.ErrBadParamInput)
+		}
+	}
+
+	return a.topicRepo.MergeTopics(ctx, targetID, sourceIDs)
+}
+
+// GetDeletedTopics lists the topics in the trash.
+func (a *TopicService) GetDeletedTopics(ctx context.Context) ([]domain.Topic, error) {
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := canManageTopics(caller); err != nil {
+		return nil, err
+	}
+
+	return a.topicRepo.GetDeletedTopics(ctx)
+}
+
+// RestoreTopic takes a topic out of the trash.
+func (a *TopicService) RestoreTopic(ctx context.Context, id uuid.UUID) (*domain.Topic, error) {
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := canManageTopics(caller); err != nil {
+		return nil, err
+	}
+
+	return a.topicRepo.RestoreTopic(ctx, id)
+}
+
+// PurgeTopic permanently removes a topic from the trash.
+func (a *TopicService) PurgeTopic(ctx context.Context, id uuid.UUID) error {
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	if err := canPurge(caller); err != nil {
+		return err
+	}
+
+	return a.topicRepo.PurgeTopic(ctx, id)
+}
+
 func (a *TopicService) GetTopicList(ctx context.Context, filter *domain.TopicFilter) ([]domain.Topic, error) {
 	topics, err := a.topicRepo.GetTopicList(ctx, filter)
 	if err != nil {
-		fmt.Println(err)
 		return nil, err
 	}
 
 	return topics, nil
 }
 
-func (a *TopicService) GetTopicArticles(ctx context.Context, id uuid.UUID) ([]domain.Article, error) {
-    articles, err := a.topicRepo.GetTopicArticles(ctx, id)
+// GetTopicChildren lists the direct subtopics of a topic.
+func (a *TopicService) GetTopicChildren(ctx context.Context, id uuid.UUID) ([]domain.Topic, error) {
+	return a.topicRepo.GetTopicChildren(ctx, id)
+}
+
+// GetTopicTree returns every topic nested under its parent.
+func (a *TopicService) GetTopicTree(ctx context.Context) ([]domain.Topic, error) {
+	topics, err := a.topicRepo.GetTopicList(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return domain.BuildTopicTree(topics), nil
+}
+
+// GetTopicArticles lists the articles of a topic the caller may read.
+func (a *TopicService) GetTopicArticles(ctx context.Context, id uuid.UUID, filter *domain.TopicArticlesFilter) ([]domain.Article, error) {
+    scoped := domain.TopicArticlesFilter{Scope: articleScope(ctx)}
+    if filter != nil {
+        scoped.IncludeDescendants = filter.IncludeDescendants
+    }
+    articles, err := a.topicRepo.GetTopicArticles(ctx, id, &scoped)
     if err != nil {
-        fmt.Println(err)
         return nil, err
     }
 
     return articles, nil
 }
+
+// checkTopicParentID rejects a malformed parent ID and a topic made its own
+// parent, the repository catches deeper cycles.
+func checkTopicParentID(id *uuid.UUID, parentID *string) error {
+	if parentID == nil {
+		return nil
+	}
+	parent, err := uuid.Parse(*parentID)
+	if err != nil {
+		return fmt.Errorf("%w: invalid parent topic ID %q", domain.ErrBadParamInput, *parentID)
+	}
+	if id != nil && parent == *id {
+		return fmt.Errorf("%w: topic cannot be its own parent", domain.ErrBadParamInput)
+	}
+	return nil
+}
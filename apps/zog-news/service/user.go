@@ -0,0 +1,264 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+	"zog-news/config"
+	"zog-news/domain"
+	"zog-news/utils"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+)
+
+type UserRepository interface {
+	CreateUser(ctx context.Context, user *domain.User) (*domain.User, error)
+	GetUser(ctx context.Context, id uuid.UUID) (*domain.User, error)
+	GetUserByEmail(ctx context.Context, email string) (*domain.User, error)
+	UpdateUserRole(ctx context.Context, id uuid.UUID, role domain.Role) (*domain.User, error)
+
+	CreateRefreshToken(ctx context.Context, token *domain.RefreshToken) error
+	GetRefreshToken(ctx context.Context, tokenHash string) (*domain.RefreshToken, error)
+	RevokeRefreshToken(ctx context.Context, id string) error
+}
+
+type UserService struct {
+	userRepo UserRepository
+	auth     config.AuthConfig
+	now      func() time.Time
+}
+
+func NewUserService(u UserRepository, auth config.AuthConfig) *UserService {
+	return &UserService{
+		userRepo: u,
+		auth:     auth,
+		now:      time.Now,
+	}
+}
+
+// accessClaims are the JWT claims carried by an access token.
+type accessClaims struct {
+	Email string      `json:"email"`
+	Role  domain.Role `json:"role"`
+	jwt.RegisteredClaims
+}
+
+// Register creates a new user account, new users always start as authors.
+func (s *UserService) Register(
+	ctx context.Context,
+	r *domain.RegisterRequest,
+) (*domain.User, error) {
+	name := strings.TrimSpace(r.Name)
+	email := strings.TrimSpace(r.Email)
+	if name == "" {
+		return nil, fmt.Errorf("%w: name is required", domain.ErrBadParamInput)
+	}
+	if _, err := mail.ParseAddress(email); err != nil {
+		return nil, fmt.Errorf("%w: email is not valid", domain.ErrBadParamInput)
+	}
+	// bcrypt ignores everything after 72 bytes
+	if len(r.Password) < 8 || len(r.Password) > 72 {
+		return nil, fmt.Errorf("%w: password must be between 8 and 72 characters", domain.ErrBadParamInput)
+	}
+
+	hash, err := utils.HashPassword(r.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.userRepo.CreateUser(ctx, &domain.User{
+		Name:         name,
+		Email:        email,
+		Role:         domain.RoleAuthor,
+		PasswordHash: hash,
+	})
+}
+
+// Login checks the credentials and starts a new session.
+func (s *UserService) Login(
+	ctx context.Context,
+	r *domain.LoginRequest,
+) (*domain.AuthTokens, error) {
+	tracer := otel.Tracer("service.user")
+	ctxTrace, span := tracer.Start(ctx, "UserService.Login")
+	defer span.End()
+
+	user, err := s.userRepo.GetUserByEmail(ctxTrace, strings.TrimSpace(r.Email))
+	if errors.Is(err, domain.ErrUserNotFound) {
+		return nil, domain.ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !utils.ComparePassword(r.Password, user.PasswordHash) {
+		return nil, domain.ErrInvalidCredentials
+	}
+
+	return s.issueTokens(ctxTrace, user)
+}
+
+// Refresh rotates a refresh token, the old token can't be used again.
+func (s *UserService) Refresh(
+	ctx context.Context,
+	r *domain.RefreshTokenRequest,
+) (*domain.AuthTokens, error) {
+	stored, err := s.userRepo.GetRefreshToken(ctx, hashToken(r.RefreshToken))
+	if err != nil {
+		return nil, err
+	}
+	if stored.RevokedAt != nil || !s.now().Before(stored.ExpiresAt) {
+		return nil, domain.ErrInvalidToken
+	}
+
+	userID, err := uuid.Parse(stored.UserID)
+	if err != nil {
+		return nil, domain.ErrInvalidToken
+	}
+	user, err := s.userRepo.GetUser(ctx, userID)
+	if errors.Is(err, domain.ErrUserNotFound) {
+		return nil, domain.ErrInvalidToken
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.RevokeRefreshToken(ctx, stored.ID); err != nil {
+		return nil, err
+	}
+	return s.issueTokens(ctx, user)
+}
+
+// Logout revokes a refresh token, access tokens expire on their own.
+func (s *UserService) Logout(
+	ctx context.Context,
+	r *domain.RefreshTokenRequest,
+) error {
+	stored, err := s.userRepo.GetRefreshToken(ctx, hashToken(r.RefreshToken))
+	if err != nil {
+		return err
+	}
+	return s.userRepo.RevokeRefreshToken(ctx, stored.ID)
+}
+
+// VerifyAccessToken validates an access token and returns its caller.
+func (s *UserService) VerifyAccessToken(token string) (*domain.AuthUser, error) {
+	claims := new(accessClaims)
+	_, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		return s.auth.SecretKey, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(s.auth.Issuer),
+		jwt.WithExpirationRequired(),
+		jwt.WithTimeFunc(s.now),
+	)
+	if err != nil || claims.Subject == "" {
+		return nil, domain.ErrInvalidToken
+	}
+
+	return &domain.AuthUser{
+		ID:    claims.Subject,
+		Email: claims.Email,
+		Role:  claims.Role,
+	}, nil
+}
+
+// GetCurrentUser fetches the account of the authenticated caller.
+func (s *UserService) GetCurrentUser(ctx context.Context) (*domain.User, error) {
+	tracer := otel.Tracer("service.user")
+	ctxTrace, span := tracer.Start(ctx, "UserService.GetCurrentUser")
+	defer span.End()
+
+	caller, err := callerFromContext(ctxTrace)
+	if err != nil {
+		return nil, err
+	}
+	id, err := uuid.Parse(caller.ID)
+	if err != nil {
+		return nil, domain.ErrUnauthorized
+	}
+	return s.userRepo.GetUser(ctxTrace, id)
+}
+
+// UpdateUserRole changes the role of a user, the new role applies from the
+// user's next login or token refresh.
+func (s *UserService) UpdateUserRole(
+	ctx context.Context,
+	id uuid.UUID,
+	role domain.Role,
+) (*domain.User, error) {
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := canManageUsers(caller); err != nil {
+		return nil, err
+	}
+	if !role.IsValid() {
+		return nil, fmt.Errorf("%w: unknown role %q", domain.ErrBadParamInput, role)
+	}
+
+	return s.userRepo.UpdateUserRole(ctx, id, role)
+}
+
+func (s *UserService) issueTokens(ctx context.Context, user *domain.User) (*domain.AuthTokens, error) {
+	// timestamps are stored without a time zone
+	now := s.now().UTC()
+	expiresAt := now.Add(s.auth.AccessTokenTTL)
+
+	accessToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, accessClaims{
+		Email: user.Email,
+		Role:  user.Role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    s.auth.Issuer,
+			Subject:   user.ID,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			ID:        uuid.NewString(),
+		},
+	}).SignedString(s.auth.SecretKey)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+	err = s.userRepo.CreateRefreshToken(ctx, &domain.RefreshToken{
+		UserID:    user.ID,
+		TokenHash: hashToken(refreshToken),
+		ExpiresAt: now.Add(s.auth.RefreshTokenTTL),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.AuthTokens{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresAt:    expiresAt,
+	}, nil
+}
+
+func randomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
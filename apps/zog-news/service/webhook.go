@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"slices"
+	"zog-news/domain"
+
+	"github.com/google/uuid"
+)
+
+type WebhookRepository interface {
+	CreateWebhook(ctx context.Context, webhook *domain.Webhook) (*domain.Webhook, error)
+	GetWebhookList(ctx context.Context) ([]domain.Webhook, error)
+	GetWebhook(ctx context.Context, id uuid.UUID) (*domain.Webhook, error)
+	DeleteWebhook(ctx context.Context, id uuid.UUID) error
+	GetWebhookDeliveries(ctx context.Context, id uuid.UUID) ([]domain.WebhookDelivery, error)
+}
+
+type WebhookService struct {
+	webhookRepo WebhookRepository
+}
+
+func NewWebhookService(w WebhookRepository) *WebhookService {
+	return &WebhookService{
+		webhookRepo: w,
+	}
+}
+
+// CreateWebhook registers a URL for the given event types and returns it
+// with the secret its deliveries are signed with, the only time the secret
+// is shown.
+func (s *WebhookService) CreateWebhook(
+	ctx context.Context,
+	r *domain.CreateWebhookRequest,
+) (*domain.Webhook, error) {
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := canManageWebhooks(caller); err != nil {
+		return nil, err
+	}
+
+	u, err := url.Parse(r.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("%w: url must be an absolute http or https URL", domain.ErrBadParamInput)
+	}
+
+	var eventTypes []domain.EventType
+	for _, t := range r.EventTypes {
+		if t != domain.EventAll && !t.IsValid() {
+			return nil, fmt.Errorf("%w: unknown event type %q", domain.ErrBadParamInput, t)
+		}
+		if !slices.Contains(eventTypes, t) {
+			eventTypes = append(eventTypes, t)
+		}
+	}
+	if len(eventTypes) == 0 {
+		return nil, fmt.Errorf("%w: at least one event type is required", domain.ErrBadParamInput)
+	}
+
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	return s.webhookRepo.CreateWebhook(ctx, &domain.Webhook{
+		URL:        u.String(),
+		EventTypes: eventTypes,
+		Secret:     secret,
+		CreatedBy:  caller.ID,
+	})
+}
+
+func (s *WebhookService) GetWebhookList(ctx context.Context) ([]domain.Webhook, error) {
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := canManageWebhooks(caller); err != nil {
+		return nil, err
+	}
+
+	return s.webhookRepo.GetWebhookList(ctx)
+}
+
+func (s *WebhookService) GetWebhook(ctx context.Context, id uuid.UUID) (*domain.Webhook, error) {
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := canManageWebhooks(caller); err != nil {
+		return nil, err
+	}
+
+	return s.webhookRepo.GetWebhook(ctx, id)
+}
+
+// DeleteWebhook removes a webhook, its pending deliveries are dropped.
+func (s *WebhookService) DeleteWebhook(ctx context.Context, id uuid.UUID) error {
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	if err := canManageWebhooks(caller); err != nil {
+		return err
+	}
+
+	return s.webhookRepo.DeleteWebhook(ctx, id)
+}
+
+// GetWebhookDeliveries returns the latest deliveries of a webhook, newest
+// first.
+func (s *WebhookService) GetWebhookDeliveries(ctx context.Context, id uuid.UUID) ([]domain.WebhookDelivery, error) {
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := canManageWebhooks(caller); err != nil {
+		return nil, err
+	}
+
+	// an unknown webhook is a 404, not an empty log
+	if _, err := s.webhookRepo.GetWebhook(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.webhookRepo.GetWebhookDeliveries(ctx, id)
+}
+
+// newWebhookSecret returns a random HMAC key for a new webhook
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,157 @@
+// Package stats counts article views without writing to the database on
+// every read.
+//
+// Handlers Record views into the Counter, which adds them up in memory per
+// article and UTC day. Run writes the sums every interval in one batch and
+// Flush writes what is left on shutdown. Views counted by an instance that
+// crashes before a flush are lost, which is fine for statistics, and so are
+// views of new article days while MaxPending of them wait for the database.
+package stats
+
+import (
+	"cmp"
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+	"zog-news/config"
+	"zog-news/domain"
+
+	"go.opentelemetry.io/otel"
+)
+
+// Store is where counted views are added up
+type Store interface {
+	AddArticleViews(ctx context.Context, views []domain.ArticleViews) error
+}
+
+// viewKey identifies the views of an article on a day
+type viewKey struct {
+	articleID string
+	day       time.Time
+}
+
+// Counter adds up article views in memory and writes them in batches
+type Counter struct {
+	store  Store
+	config config.StatsConfig
+	now    func() time.Time
+
+	mu      sync.Mutex
+	pending map[viewKey]int64
+	// dropped counts the views refused since the last flush because pending
+	// was full
+	dropped int64
+	// full is signalled when pending reached MaxPending keys
+	full chan struct{}
+}
+
+func NewCounter(store Store, config config.StatsConfig) *Counter {
+	return &Counter{
+		store:   store,
+		config:  config,
+		now:     time.Now,
+		pending: make(map[viewKey]int64),
+		full:    make(chan struct{}, 1),
+	}
+}
+
+// Record counts one view of an article, it never blocks on the database. The
+// view is dropped when MaxPending other article days are already waiting.
+func (c *Counter) Record(articleID string) {
+	key := viewKey{articleID: articleID, day: c.now().UTC().Truncate(24 * time.Hour)}
+
+	c.mu.Lock()
+	_, counted := c.pending[key]
+	if !counted && len(c.pending) >= c.config.MaxPending {
+		c.dropped++
+		c.mu.Unlock()
+		return
+	}
+	c.pending[key]++
+	// only the key reaching the limit asks for a flush, so a failing store
+	// isn't hammered with one attempt per view
+	full := !counted && len(c.pending) == c.config.MaxPending
+	c.mu.Unlock()
+
+	if full {
+		select {
+		case c.full <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Run writes the counted views every interval, or sooner once MaxPending
+// articles were viewed, until ctx is done. Call Flush afterwards to write
+// the views counted since the last round.
+func (c *Counter) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.config.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-c.full:
+		}
+		if err := c.Flush(ctx); err != nil && ctx.Err() == nil {
+			slog.Error("Writing article views failed", "error", err)
+		}
+	}
+}
+
+// Flush writes the views counted so far. Views that fail to be written are
+// kept and retried with the next flush, within MaxPending article days.
+func (c *Counter) Flush(ctx context.Context) error {
+	tracer := otel.Tracer("stats.counter")
+	ctx, span := tracer.Start(ctx, "Counter.Flush")
+	defer span.End()
+
+	c.mu.Lock()
+	pending, dropped := c.pending, c.dropped
+	c.pending, c.dropped = make(map[viewKey]int64, len(pending)), 0
+	c.mu.Unlock()
+
+	if dropped > 0 {
+		slog.WarnContext(ctx, "Dropped article views, too many waiting to be written",
+			"views", dropped, "max_pending", c.config.MaxPending)
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+	views := make([]domain.ArticleViews, 0, len(pending))
+	for key, count := range pending {
+		views = append(views, domain.ArticleViews{ArticleID: key.articleID, Day: key.day, Views: count})
+	}
+	// instances upserting overlapping rows lock them in the same order, so
+	// they wait for each other instead of deadlocking
+	slices.SortFunc(views, func(a, b domain.ArticleViews) int {
+		return cmp.Or(a.Day.Compare(b.Day), cmp.Compare(a.ArticleID, b.ArticleID))
+	})
+
+	if err := c.store.AddArticleViews(ctx, views); err != nil {
+		span.RecordError(err)
+		c.restore(pending)
+		return err
+	}
+	return nil
+}
+
+// restore puts views that failed to be written back in front of the ones
+// counted meanwhile. Newer article days that no longer fit are dropped.
+func (c *Counter) restore(failed map[viewKey]int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, count := range c.pending {
+		if _, ok := failed[key]; ok || len(failed) < c.config.MaxPending {
+			failed[key] += count
+		} else {
+			c.dropped += count
+		}
+	}
+	c.pending = failed
+}
@@ -0,0 +1,118 @@
+package stats_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+	"zog-news/config"
+	"zog-news/domain"
+	"zog-news/stats"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStore struct {
+	mu      sync.Mutex
+	batches [][]domain.ArticleViews
+	err     error
+}
+
+func (f *fakeStore) AddArticleViews(_ context.Context, views []domain.ArticleViews) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return f.err
+	}
+	f.batches = append(f.batches, views)
+	return nil
+}
+
+func (f *fakeStore) batchCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.batches)
+}
+
+func TestCounter(t *testing.T) {
+	t.Parallel()
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+
+	t.Run("Flush", func(t *testing.T) {
+		store := &fakeStore{}
+		counter := stats.NewCounter(store, config.StatsConfig{FlushInterval: time.Hour, MaxPending: 100})
+
+		counter.Record("b")
+		counter.Record("a")
+		counter.Record("a")
+		counter.Record("c")
+		require.NoError(t, counter.Flush(context.Background()))
+
+		require.Len(t, store.batches, 1)
+		// sorted, so concurrent upserts lock rows in the same order
+		assert.Equal(t, []domain.ArticleViews{
+			{ArticleID: "a", Day: today, Views: 2},
+			{ArticleID: "b", Day: today, Views: 1},
+			{ArticleID: "c", Day: today, Views: 1},
+		}, store.batches[0])
+
+		// nothing new, nothing written
+		require.NoError(t, counter.Flush(context.Background()))
+		assert.Len(t, store.batches, 1)
+	})
+
+	t.Run("FailedFlushIsRetried", func(t *testing.T) {
+		store := &fakeStore{err: errors.New("database down")}
+		counter := stats.NewCounter(store, config.StatsConfig{FlushInterval: time.Hour, MaxPending: 100})
+
+		counter.Record("a")
+		require.Error(t, counter.Flush(context.Background()))
+
+		store.err = nil
+		counter.Record("a")
+		require.NoError(t, counter.Flush(context.Background()))
+
+		require.Len(t, store.batches, 1)
+		assert.Equal(t, []domain.ArticleViews{{ArticleID: "a", Day: today, Views: 2}}, store.batches[0])
+	})
+
+	t.Run("DropsWhenFull", func(t *testing.T) {
+		store := &fakeStore{err: errors.New("database down")}
+		counter := stats.NewCounter(store, config.StatsConfig{FlushInterval: time.Hour, MaxPending: 2})
+
+		counter.Record("a")
+		counter.Record("b")
+		require.Error(t, counter.Flush(context.Background()))
+
+		// the failed views still fill the counter, new articles don't fit
+		counter.Record("c")
+		counter.Record("a")
+		require.Error(t, counter.Flush(context.Background()))
+		counter.Record("d")
+
+		store.err = nil
+		require.NoError(t, counter.Flush(context.Background()))
+
+		require.Len(t, store.batches, 1)
+		assert.Equal(t, []domain.ArticleViews{
+			{ArticleID: "a", Day: today, Views: 2},
+			{ArticleID: "b", Day: today, Views: 1},
+		}, store.batches[0])
+	})
+
+	t.Run("FlushWhenFull", func(t *testing.T) {
+		store := &fakeStore{}
+		counter := stats.NewCounter(store, config.StatsConfig{FlushInterval: time.Hour, MaxPending: 2})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go counter.Run(ctx)
+
+		counter.Record("a")
+		counter.Record("b")
+		assert.Eventually(t, func() bool { return store.batchCount() == 1 }, time.Second, 10*time.Millisecond)
+	})
+}
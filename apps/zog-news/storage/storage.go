@@ -0,0 +1,40 @@
+// Package storage keeps uploaded files outside the database, on the local
+// disk or in an S3 compatible object store.
+//
+// Keys are slash separated paths chosen by the caller, such as
+// 2025/07/5b1e9d2c-4f3a-4c8e-9a7b-2d6f8e1c3a5b.jpg. Objects are written once
+// and never modified, a new version of a file gets a new key.
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path"
+	"strings"
+)
+
+// ErrNotFound is returned when no object is stored under a key
+var ErrNotFound = errors.New("storage: object not found")
+
+// ErrInvalidKey is returned for keys that would escape the storage root
+var ErrInvalidKey = errors.New("storage: invalid key")
+
+// Storage stores objects under keys
+type Storage interface {
+	// Put stores size bytes read from body under key
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	// Open returns the object under key, the caller closes it
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the object under key, deleting a missing key is not
+	// an error
+	Delete(ctx context.Context, key string) error
+}
+
+// checkKey rejects empty, absolute and non-canonical keys such as a/../b
+func checkKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || path.Clean(key) != key || strings.HasPrefix(key, "../") || key == ".." {
+		return ErrInvalidKey
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package storage_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"zog-news/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// s3StandIn keeps objects in memory and answers the requests the S3 storage
+// sends, checking each is signed
+type s3StandIn struct {
+	mu      sync.Mutex
+	objects map[string]string
+	types   map[string]string
+}
+
+func (s *s3StandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=test-key/") {
+		http.Error(w, "<Error><Code>AccessDenied</Code></Error>", http.StatusForbidden)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch r.Method {
+	case http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		s.objects[r.URL.Path] = string(body)
+		s.types[r.URL.Path] = r.Header.Get("Content-Type")
+	case http.MethodGet:
+		object, ok := s.objects[r.URL.Path]
+		if !ok {
+			http.Error(w, "<Error><Code>NoSuchKey</Code></Error>", http.StatusNotFound)
+			return
+		}
+		_, _ = io.WriteString(w, object)
+	case http.MethodDelete:
+		delete(s.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestStorage(t *testing.T) {
+	t.Parallel()
+
+	local, err := storage.NewLocal(t.TempDir())
+	require.NoError(t, err)
+
+	standIn := &s3StandIn{objects: map[string]string{}, types: map[string]string{}}
+	server := httptest.NewServer(standIn)
+	t.Cleanup(server.Close)
+	s3 := storage.NewS3(storage.S3Config{
+		Endpoint:  server.URL,
+		Bucket:    "media",
+		Region:    "us-east-1",
+		AccessKey: "test-key",
+		SecretKey: "test-secret",
+	})
+
+	for name, store := range map[string]storage.Storage{"Local": local, "S3": s3} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			key := "2025/07/flood photo.jpg"
+
+			err := store.Put(ctx, key, strings.NewReader("jpeg bytes"), 10, "image/jpeg")
+			require.NoError(t, err)
+
+			object, err := store.Open(ctx, key)
+			require.NoError(t, err)
+			content, err := io.ReadAll(object)
+			require.NoError(t, err)
+			require.NoError(t, object.Close())
+			assert.Equal(t, "jpeg bytes", string(content))
+
+			require.NoError(t, store.Delete(ctx, key))
+			_, err = store.Open(ctx, key)
+			assert.ErrorIs(t, err, storage.ErrNotFound)
+
+			// deleting twice is fine
+			assert.NoError(t, store.Delete(ctx, key))
+		})
+	}
+
+	t.Run("S3_ContentType", func(t *testing.T) {
+		err := s3.Put(context.Background(), "a.png", strings.NewReader("png"), 3, "image/png")
+		require.NoError(t, err)
+
+		standIn.mu.Lock()
+		defer standIn.mu.Unlock()
+		assert.Equal(t, "image/png", standIn.types["/media/a.png"])
+	})
+
+	t.Run("S3_Denied", func(t *testing.T) {
+		denied := storage.NewS3(storage.S3Config{Endpoint: server.URL, Bucket: "media", Region: "us-east-1", AccessKey: "other"})
+		err := denied.Put(context.Background(), "a.png", strings.NewReader("png"), 3, "image/png")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "AccessDenied")
+	})
+
+	t.Run("InvalidKey", func(t *testing.T) {
+		for _, key := range []string{"", "/etc/passwd", "../secret", "a/../../b", "a//b"} {
+			_, err := local.Open(context.Background(), key)
+			assert.ErrorIs(t, err, storage.ErrInvalidKey, key)
+			_, err = s3.Open(context.Background(), key)
+			assert.ErrorIs(t, err, storage.ErrInvalidKey, key)
+		}
+	})
+}
@@ -17,11 +17,9 @@ func HashPassword(password string) (string, error) {
 	return hash, nil
 }
 
+// ComparePassword reports whether password matches the hash, a mismatch is
+// a wrong password rather than an error
 func ComparePassword(password, hash string) bool {
 	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
-	if err != nil {
-		fmt.Printf("Password is invalid: %v\n", password)
-		return false
-	}
-	 return true
+	return err == nil
 }
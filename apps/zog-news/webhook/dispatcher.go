@@ -0,0 +1,196 @@
+// Package webhook delivers domain events to the URLs registered through the
+// webhooks API.
+//
+// The event relay hands every event to the Sink, which queues a delivery for
+// each subscribed webhook. The Dispatcher sends the queued deliveries as a
+// signed POST, see Sign, and retries failed ones with a growing delay. A
+// webhook whose attempts keep failing is disabled and gets no new deliveries.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+	"zog-news/config"
+	"zog-news/domain"
+
+	"go.opentelemetry.io/otel"
+)
+
+const (
+	// batchSize is how many deliveries one round claims
+	batchSize = 50
+	// claimLease is how long a claimed delivery is hidden from other
+	// dispatchers
+	claimLease = 5 * time.Minute
+	// requestTimeout bounds a single POST to a subscriber
+	requestTimeout = 10 * time.Second
+
+	// the first retry waits retryBase, every further one twice as long up
+	// to retryMax
+	retryBase = 30 * time.Second
+	retryMax  = time.Hour
+)
+
+// Outbox is where deliveries wait to be sent
+type Outbox interface {
+	ClaimDeliveries(ctx context.Context, now, lease time.Time, limit int) ([]domain.PendingDelivery, error)
+	MarkDeliverySucceeded(ctx context.Context, id string, responseStatus int, deliveredAt time.Time) error
+	MarkDeliveryFailed(ctx context.Context, id string, responseStatus *int, reason string, retryAt *time.Time, disableAfter int) (bool, error)
+}
+
+// Dispatcher sends the queued deliveries to their webhooks
+type Dispatcher struct {
+	outbox Outbox
+	client *http.Client
+	config config.WebhookConfig
+	now    func() time.Time
+}
+
+func NewDispatcher(outbox Outbox, config config.WebhookConfig) *Dispatcher {
+	return &Dispatcher{
+		outbox: outbox,
+		client: &http.Client{
+			Timeout: requestTimeout,
+			// a redirect would resend the body unsigned by us to another
+			// URL, report it as a failure instead
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		config: config,
+		now:    time.Now,
+	}
+}
+
+// Run sends due deliveries every interval until ctx is done
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := d.DeliverDue(ctx); err != nil && ctx.Err() == nil {
+				slog.Error("Webhook delivery failed", "error", err)
+			}
+		}
+	}
+}
+
+// DeliverDue claims the deliveries due now, sends them and returns how many
+// succeeded. Failed deliveries are rescheduled, so only outbox errors are
+// returned.
+func (d *Dispatcher) DeliverDue(ctx context.Context) (int, error) {
+	tracer := otel.Tracer("webhook.dispatcher")
+	ctx, span := tracer.Start(ctx, "Dispatcher.DeliverDue")
+	defer span.End()
+
+	now := d.now().UTC()
+	deliveries, err := d.outbox.ClaimDeliveries(ctx, now, now.Add(claimLease), batchSize)
+	if err != nil {
+		span.RecordError(err)
+		return 0, err
+	}
+
+	delivered := 0
+	for _, delivery := range deliveries {
+		if ctx.Err() != nil {
+			// unsent claims are retried once their lease passes
+			return delivered, ctx.Err()
+		}
+
+		status, sendErr := d.send(ctx, delivery)
+		if sendErr == nil {
+			delivered++
+			err = d.outbox.MarkDeliverySucceeded(ctx, delivery.ID, status, d.now().UTC())
+		} else {
+			err = d.fail(ctx, delivery, status, sendErr)
+		}
+		if err != nil {
+			span.RecordError(err)
+			return delivered, err
+		}
+	}
+	return delivered, nil
+}
+
+// send POSTs the delivery and returns the response status, 0 when no
+// response came back
+func (d *Dispatcher) send(ctx context.Context, delivery domain.PendingDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "zog-news-webhooks")
+	req.Header.Set("X-Webhook-ID", delivery.WebhookID)
+	req.Header.Set("X-Delivery-ID", delivery.ID)
+	req.Header.Set("X-Event-ID", delivery.EventID)
+	req.Header.Set("X-Event-Type", string(delivery.EventType))
+	req.Header.Set(SignatureHeader, Sign(delivery.Secret, d.now(), delivery.Body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	// drain the body so the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("webhook responded with %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+func (d *Dispatcher) fail(ctx context.Context, delivery domain.PendingDelivery, status int, sendErr error) error {
+	slog.WarnContext(ctx, "Delivering webhook failed",
+		slog.String("id", delivery.ID),
+		slog.String("webhook_id", delivery.WebhookID),
+		slog.String("event_type", string(delivery.EventType)),
+		slog.Int("attempt", delivery.Attempts),
+		slog.String("error", sendErr.Error()),
+	)
+
+	var responseStatus *int
+	if status != 0 {
+		responseStatus = &status
+	}
+	disabled, err := d.outbox.MarkDeliveryFailed(
+		ctx,
+		delivery.ID,
+		responseStatus,
+		sendErr.Error(),
+		d.retryAt(delivery),
+		d.config.DisableAfter,
+	)
+	if disabled {
+		slog.WarnContext(ctx, "Disabled webhook after repeated failures",
+			slog.String("webhook_id", delivery.WebhookID),
+			slog.String("url", delivery.URL),
+		)
+	}
+	return err
+}
+
+// retryAt returns when a failed delivery is tried again, nil once it used up
+// its attempts
+func (d *Dispatcher) retryAt(delivery domain.PendingDelivery) *time.Time {
+	if delivery.Attempts >= d.config.MaxAttempts {
+		return nil
+	}
+
+	delay := retryBase
+	for i := 1; i < delivery.Attempts && delay < retryMax; i++ {
+		delay *= 2
+	}
+	at := d.now().UTC().Add(min(delay, retryMax))
+	return &at
+}
@@ -0,0 +1,186 @@
+package webhook_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+	"zog-news/config"
+	"zog-news/domain"
+	"zog-news/webhook"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type failure struct {
+	responseStatus *int
+	retryAt        *time.Time
+}
+
+type fakeOutbox struct {
+	due       []domain.PendingDelivery
+	succeeded map[string]int
+	failed    map[string]failure
+	disable   bool
+}
+
+func (f *fakeOutbox) ClaimDeliveries(_ context.Context, _, _ time.Time, _ int) ([]domain.PendingDelivery, error) {
+	due := f.due
+	f.due = nil
+	return due, nil
+}
+
+func (f *fakeOutbox) MarkDeliverySucceeded(_ context.Context, id string, responseStatus int, _ time.Time) error {
+	if f.succeeded == nil {
+		f.succeeded = make(map[string]int)
+	}
+	f.succeeded[id] = responseStatus
+	return nil
+}
+
+func (f *fakeOutbox) MarkDeliveryFailed(_ context.Context, id string, responseStatus *int, _ string, retryAt *time.Time, _ int) (bool, error) {
+	if f.failed == nil {
+		f.failed = make(map[string]failure)
+	}
+	f.failed[id] = failure{responseStatus: responseStatus, retryAt: retryAt}
+	return f.disable, nil
+}
+
+type fakeQueue struct {
+	eventID   string
+	eventType domain.EventType
+	body      []byte
+}
+
+func (f *fakeQueue) EnqueueDeliveries(_ context.Context, eventID string, eventType domain.EventType, body []byte) (int, error) {
+	f.eventID, f.eventType, f.body = eventID, eventType, body
+	return 1, nil
+}
+
+const secret = "whsec_test"
+
+func pendingDelivery(id, url string, attempts int) domain.PendingDelivery {
+	return domain.PendingDelivery{
+		ID:        id,
+		WebhookID: "3f2b8c1e-7a4d-4e9b-9c2a-1d5e6f7a8b9c",
+		URL:       url,
+		Secret:    secret,
+		EventID:   "5b1d3e7f-2a4c-4b6d-8e0f-1a3c5e7b9d2f",
+		EventType: domain.EventArticlePublished,
+		Body:      []byte(`{"type":"article.published"}`),
+		Attempts:  attempts,
+	}
+}
+
+func TestSignature(t *testing.T) {
+	t.Parallel()
+
+	body := []byte(`{"type":"article.published"}`)
+	sentAt := time.Unix(1752566400, 0)
+	signature := webhook.Sign(secret, sentAt, body)
+	assert.Regexp(t, `^t=1752566400,sha256=[0-9a-f]{64}$`, signature)
+
+	assert.NoError(t, webhook.Verify(secret, signature, body, sentAt.Add(time.Minute), 5*time.Minute))
+	assert.ErrorIs(t, webhook.Verify("whsec_other", signature, body, sentAt, 5*time.Minute), webhook.ErrInvalidSignature)
+	assert.ErrorIs(t, webhook.Verify(secret, signature, []byte(`{}`), sentAt, 5*time.Minute), webhook.ErrInvalidSignature)
+	assert.ErrorIs(t, webhook.Verify(secret, signature, body, sentAt.Add(time.Hour), 5*time.Minute), webhook.ErrInvalidSignature)
+	assert.ErrorIs(t, webhook.Verify(secret, "sha256=abc", body, sentAt, 5*time.Minute), webhook.ErrInvalidSignature)
+}
+
+func TestSink(t *testing.T) {
+	t.Parallel()
+
+	queue := &fakeQueue{}
+	event := domain.Event{ID: "1", Type: domain.EventTopicCreated, AggregateType: domain.AggregateTopic}
+	require.NoError(t, webhook.NewSink(queue).Publish(context.Background(), event))
+
+	assert.Equal(t, "1", queue.eventID)
+	assert.Equal(t, domain.EventTopicCreated, queue.eventType)
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(queue.body, &body))
+	assert.Equal(t, "topic.created", body["type"])
+}
+
+func TestDispatcherDeliverDue(t *testing.T) {
+	t.Parallel()
+
+	webhookConfig := config.WebhookConfig{Interval: time.Second, MaxAttempts: 3, DisableAfter: 5}
+
+	t.Run("Signed", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			assert.NoError(t, webhook.Verify(secret, r.Header.Get("X-Signature"), body, time.Now(), time.Minute))
+			assert.Equal(t, "article.published", r.Header.Get("X-Event-Type"))
+			assert.Equal(t, "1", r.Header.Get("X-Delivery-ID"))
+			w.WriteHeader(http.StatusAccepted)
+		}))
+		defer server.Close()
+
+		outbox := &fakeOutbox{due: []domain.PendingDelivery{pendingDelivery("1", server.URL, 1)}}
+		delivered, err := webhook.NewDispatcher(outbox, webhookConfig).DeliverDue(context.Background())
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, delivered)
+		assert.Equal(t, map[string]int{"1": http.StatusAccepted}, outbox.succeeded)
+	})
+
+	t.Run("RetriedWithBackoff", func(t *testing.T) {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		outbox := &fakeOutbox{due: []domain.PendingDelivery{
+			pendingDelivery("1", server.URL, 1),
+			pendingDelivery("2", server.URL, 2),
+			pendingDelivery("3", server.URL, 3),
+		}}
+
+		before := time.Now().UTC()
+		delivered, err := webhook.NewDispatcher(outbox, webhookConfig).DeliverDue(context.Background())
+		require.NoError(t, err)
+
+		assert.Zero(t, delivered)
+		assert.Equal(t, int32(3), calls.Load())
+		require.NotNil(t, outbox.failed["1"].retryAt)
+		require.NotNil(t, outbox.failed["2"].retryAt)
+		assert.WithinDuration(t, before.Add(30*time.Second), *outbox.failed["1"].retryAt, 5*time.Second)
+		assert.WithinDuration(t, before.Add(time.Minute), *outbox.failed["2"].retryAt, 5*time.Second)
+		require.NotNil(t, outbox.failed["1"].responseStatus)
+		assert.Equal(t, http.StatusServiceUnavailable, *outbox.failed["1"].responseStatus)
+		// out of attempts
+		require.Contains(t, outbox.failed, "3")
+		assert.Nil(t, outbox.failed["3"].retryAt)
+	})
+
+	t.Run("Unreachable", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		server.Close()
+
+		outbox := &fakeOutbox{due: []domain.PendingDelivery{pendingDelivery("1", server.URL, 1)}, disable: true}
+		_, err := webhook.NewDispatcher(outbox, webhookConfig).DeliverDue(context.Background())
+		require.NoError(t, err)
+
+		require.Contains(t, outbox.failed, "1")
+		assert.Nil(t, outbox.failed["1"].responseStatus)
+	})
+
+	t.Run("RedirectNotFollowed", func(t *testing.T) {
+		server := httptest.NewServer(http.RedirectHandler("https://example.com", http.StatusFound))
+		defer server.Close()
+
+		outbox := &fakeOutbox{due: []domain.PendingDelivery{pendingDelivery("1", server.URL, 1)}}
+		_, err := webhook.NewDispatcher(outbox, webhookConfig).DeliverDue(context.Background())
+		require.NoError(t, err)
+
+		require.NotNil(t, outbox.failed["1"].responseStatus)
+		assert.Equal(t, http.StatusFound, *outbox.failed["1"].responseStatus)
+	})
+}
@@ -0,0 +1,33 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"zog-news/domain"
+)
+
+// Queue is where deliveries wait to be sent
+type Queue interface {
+	EnqueueDeliveries(ctx context.Context, eventID string, eventType domain.EventType, body []byte) (int, error)
+}
+
+// Sink is the events.Sink fanning every relayed event out to the webhooks
+// subscribed to its type. It only queues deliveries, the Dispatcher sends
+// them, so a slow or broken subscriber never holds up the relay.
+type Sink struct {
+	queue Queue
+}
+
+func NewSink(queue Queue) *Sink {
+	return &Sink{queue: queue}
+}
+
+func (s *Sink) Publish(ctx context.Context, event domain.Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.queue.EnqueueDeliveries(ctx, event.ID, event.Type, body)
+	return err
+}